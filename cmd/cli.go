@@ -6,6 +6,7 @@ import (
 	"runtime/debug"
 
 	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/plugin"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
@@ -33,6 +34,7 @@ type CommandLine struct {
 
 	cmds []cmder
 	gens []genConfig
+	mws  []gen.Middleware
 }
 
 type cmder interface {
@@ -52,6 +54,7 @@ func (c *CommandLine) addCommand(cmds ...cmder) *CommandLine {
 
 func (c *CommandLine) build() *cobra.Command {
 	cmd := c.newGqlcCmd(c.gens, c.fs, c.prefix)
+	cmd.AddCommand(cmd.newGraphCmd(c.fs).getCommand())
 	for _, cmdr := range c.cmds {
 		cmd.AddCommand(cmdr.getCommand())
 	}
@@ -79,8 +82,14 @@ func NewCLI(opts ...option) (c *CommandLine) {
 //
 func (c *CommandLine) AllowPlugins(prefix string) { c.prefix = prefix }
 
-// RegisterGenerator registers a generator with the compiler.
+// RegisterGenerator registers a generator with the compiler. Plugins
+// without a version report the version of gqlc to their executables.
+//
 func (c *CommandLine) RegisterGenerator(g gen.Generator, name, opt, help string) {
+	if p, ok := g.(*plugin.Generator); ok && p.Version == "" {
+		p.Version = version
+	}
+
 	c.gens = append(c.gens, genConfig{
 		g:    g,
 		name: name,
@@ -89,6 +98,11 @@ func (c *CommandLine) RegisterGenerator(g gen.Generator, name, opt, help string)
 	})
 }
 
+// Use adds middlewares, which wrap every call of every generator.
+// They are applied in order i.e. the first one is the outermost.
+//
+func (c *CommandLine) Use(mws ...gen.Middleware) { c.mws = append(c.mws, mws...) }
+
 func wrapPanic(err error, stack []byte) error {
 	return fmt.Errorf("gqlc: recovered from unexpected panic: %w\n\n%s", err, stack)
 }
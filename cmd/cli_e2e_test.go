@@ -278,3 +278,40 @@ func compareBytes(t *testing.T, name string, ex, out []byte) {
 		}
 	}
 }
+
+func TestE2E_GoBackend(t *testing.T) {
+	testCases := map[string]string{
+		"Ident":  "backend=graph-gophers",
+		"Quoted": `backend="graph-gophers"`,
+	}
+
+	for name, opt := range testCases {
+		t.Run(name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, "api.gql", []byte("type Query { hello: String }"), os.ModePerm)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			cli := NewCLI(WithFS(fs))
+			cli.RegisterGenerator(new(golang.Generator),
+				"go_out",
+				"go_opt",
+				"Generate Go source.",
+			)
+
+			err = cli.Run([]string{"gqlc", "--go_out", "/out", "--go_opt", opt, "api.gql"})
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			b, err := afero.ReadFile(fs, "/out/api.go")
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if ex := []byte(`"github.com/graph-gophers/graphql-go"`); !bytes.Contains(b, ex) {
+				subT.Errorf("expected graph-gophers output, but got:\n%s", b)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/plugin"
 )
 
 func newMockGenerator(t gomock.TestReporter) *gen.MockGenerator {
@@ -47,6 +48,13 @@ func TestCli_Run(t *testing.T) {
 				g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
+		{
+			Name: "OrderAndPrune",
+			Args: []string{"gqlc", "-I", "/usr/imports", "-I", "/home/graphql/imports", "--order", "topological", "--keep_type", "Obj", "five.gql"},
+			expect: func(g *gen.MockGenerator) {
+				g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -59,6 +67,30 @@ func TestCli_Run(t *testing.T) {
 	}
 }
 
+func TestCli_UnknownOrder(t *testing.T) {
+	c := NewCLI(WithFS(testFs))
+
+	err := c.Run([]string{"gqlc", "--order", "random", "/home/graphql/imports/thr.gql"})
+	if err == nil {
+		t.Error("expected error for unknown order")
+	}
+}
+
+func TestCli_RegisterPlugin(t *testing.T) {
+	c := NewCLI(WithFS(testFs))
+
+	p, own := &plugin.Generator{Name: "test"}, &plugin.Generator{Name: "own", Version: "v1.0.0"}
+	c.RegisterGenerator(p, "test_out", "test_opt", "")
+	c.RegisterGenerator(own, "own_out", "own_opt", "")
+
+	if p.Version != version {
+		t.Errorf("expected plugin version: %s, but got: %s", version, p.Version)
+	}
+	if own.Version != "v1.0.0" {
+		t.Errorf("expected plugin to keep its version, but got: %s", own.Version)
+	}
+}
+
 func compare(t *testing.T, out, ex map[string]interface{}) {
 	var match bool
 	var missing []string
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gqlc/gqlc/compile"
 	"github.com/zaba505/gws"
 	"go.uber.org/zap"
 )
@@ -147,6 +148,13 @@ func fetch(client *fetchClient, url *url.URL, headers http.Header) (io.ReadClose
 	return resp.Body, err
 }
 
+// newFetcher returns a compile.Fetcher, which fetches remote documents with the given client.
+func newFetcher(client *fetchClient, headers http.Header) compile.Fetcher {
+	return compile.FetcherFunc(func(ctx context.Context, endpoint *url.URL) (io.ReadCloser, error) {
+		return fetch(client, endpoint, headers)
+	})
+}
+
 type noopCloser struct {
 	io.Reader
 }
@@ -68,6 +68,7 @@ func (f *headerFlag) Set(val string) error {
 // genFlag represents a Generator flag: *_out
 type genFlag struct {
 	g    gen.Generator
+	name string
 	opts map[string]interface{}
 
 	geners  *[]generator
@@ -105,7 +106,7 @@ func (f genFlag) Set(arg string) (err error) {
 	}
 
 	*f.outDirs = append(*f.outDirs, *outDir)
-	*f.geners = append(*f.geners, generator{Generator: f.g, opts: f.opts, outDir: *outDir})
+	*f.geners = append(*f.geners, generator{Generator: f.g, name: f.name, opts: f.opts, outDir: *outDir})
 	return
 }
 
@@ -195,15 +196,21 @@ func parseValue(p *fparser, key string, opts map[string]interface{}) (val interf
 		return parseInt(p, opts, key)
 	case scanner.Float:
 		return parseFloat(p, opts, key)
-	case scanner.Ident:
+	case scanner.Ident, scanner.String:
 		valStr := p.TokenText()
-		if valStr == "true" || valStr == "false" {
-			return parseBool(p, opts, key)
+		if tt == scanner.Ident {
+			if valStr == "true" || valStr == "false" {
+				return parseBool(p, opts, key)
+			}
+
+			// Identifiers may be joined by dashes e.g. graph-gophers
+			for p.Peek() == '-' {
+				p.Next()
+				p.Scan()
+				valStr += "-" + p.TokenText()
+			}
 		}
 
-		fallthrough
-	case scanner.String:
-		valStr := p.TokenText()
 		oldV, ok := opts[key]
 		if !ok {
 			val = valStr
@@ -60,6 +60,11 @@ func TestGenOptFlag(t *testing.T) {
 			Arg:  "testIdents=one,testIdents=two,testIdents=three:",
 			Opts: map[string]interface{}{"testIdents": []string{"one", "two", "three"}},
 		},
+		{
+			Name: "DashedIdent",
+			Arg:  "backend=graph-gophers,split=true:",
+			Opts: map[string]interface{}{"backend": "graph-gophers", "split": true},
+		},
 	}
 
 	for _, testCase := range testCases {
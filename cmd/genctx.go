@@ -0,0 +1,253 @@
+// genctx.go contains the GeneratorContext generators write their output through
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/spf13/afero"
+)
+
+// Default permissions of generated files and directories.
+const (
+	filePerm os.FileMode = 0644
+	dirPerm  os.FileMode = 0755
+)
+
+// genCtx is a gen.GeneratorContext for a single output directory.
+//
+// Files can not be opened outside of the directory and are written to a
+// temporary file first, which only replaces the named file once it is closed
+// without any write errors. Thus, a failed run never leaves half-written files.
+//
+type genCtx struct {
+	fs  afero.Fs
+	dir string
+
+	// gen and doc are the names of the generator and
+	// document, which files are currently generated for.
+	//
+	gen, doc string
+
+	// files tracks every file generated during a run.
+	// It is shared by all the genCtxs of a run.
+	//
+	files map[string]*genFile
+
+	// pending tracks the files which have been opened, but not closed.
+	pending map[*atomicFile]bool
+}
+
+// Dir returns the directory files are opened in.
+func (ctx *genCtx) Dir() string { return ctx.dir }
+
+// Open opens a file with the default permissions.
+func (ctx *genCtx) Open(name string) (io.WriteCloser, error) {
+	return ctx.OpenFile(name, filePerm)
+}
+
+// OpenFile opens a file with the given permission bits. Any missing
+// parent directories are created. The file is only written once it is closed.
+//
+func (ctx *genCtx) OpenFile(name string, perm os.FileMode) (io.WriteCloser, error) {
+	fname, err := ctx.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(fname)
+	err = ctx.fs.MkdirAll(dir, dirPerm)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := afero.TempFile(ctx.fs, dir, "."+filepath.Base(fname)+".tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	f := &atomicFile{File: tmp, ctx: ctx, name: fname, perm: perm, hash: sha256.New()}
+	if ctx.pending == nil {
+		ctx.pending = make(map[*atomicFile]bool)
+	}
+	ctx.pending[f] = true
+	return f, nil
+}
+
+// Create opens a file, which doesn't exist yet, with the default permissions.
+// It belongs to the user once written, so it isn't tracked in the manifest.
+//
+func (ctx *genCtx) Create(name string) (io.WriteCloser, error) {
+	fname, err := ctx.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := afero.Exists(ctx.fs, fname)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, &os.PathError{Op: "create", Path: fname, Err: os.ErrExist}
+	}
+
+	w, err := ctx.OpenFile(name, filePerm)
+	if err != nil {
+		return nil, err
+	}
+	w.(*atomicFile).untracked = true
+	return w, nil
+}
+
+// ReadFile reads a file, which was generated earlier in the same run.
+func (ctx *genCtx) ReadFile(name string) ([]byte, error) {
+	fname, err := ctx.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.files[fname] == nil {
+		return nil, fmt.Errorf("gqlc: file was not generated during this run: %s", name)
+	}
+
+	return afero.ReadFile(ctx.fs, fname)
+}
+
+// Remove removes a file. It is not an error if the file does not exist.
+func (ctx *genCtx) Remove(name string) error {
+	fname, err := ctx.resolve(name)
+	if err != nil {
+		return err
+	}
+	delete(ctx.files, fname)
+
+	err = ctx.fs.Remove(fname)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// discard removes the temporary files of any files which were never closed.
+func (ctx *genCtx) discard() {
+	for f := range ctx.pending {
+		f.abort()
+	}
+}
+
+// resolve returns the path of the named file in the output directory.
+// The name must be relative and may not lie outside the output directory.
+//
+func (ctx *genCtx) resolve(name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("gqlc: file name must not be empty")
+	}
+
+	if filepath.IsAbs(name) || path.IsAbs(filepath.ToSlash(name)) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("gqlc: file name must be relative to the output directory: %s", name)
+	}
+
+	fname := filepath.Join(ctx.dir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(ctx.dir, fname)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("gqlc: file name must not lie outside the output directory: %s", name)
+	}
+	return fname, nil
+}
+
+// atomicFile writes to a temporary file, which
+// replaces the named file when it is closed.
+//
+type atomicFile struct {
+	afero.File
+
+	ctx  *genCtx
+	name string
+	perm os.FileMode
+	hash hash.Hash
+	err  error
+
+	// untracked is set for files created for the user
+	untracked bool
+}
+
+func (f *atomicFile) Write(b []byte) (int, error) {
+	n, err := f.File.Write(b)
+	f.hash.Write(b[:n])
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return n, err
+}
+
+// Close commits the file, unless any write failed, in
+// which case the temporary file is discarded instead.
+//
+func (f *atomicFile) Close() error {
+	if !f.ctx.pending[f] {
+		return nil
+	}
+	delete(f.ctx.pending, f)
+
+	tmp := f.File.Name()
+	err := f.File.Close()
+	if err == nil {
+		err = f.err
+	}
+	if err == nil {
+		err = f.ctx.fs.Chmod(tmp, f.perm)
+	}
+	if err == nil {
+		err = f.ctx.fs.Rename(tmp, f.name)
+	}
+	if err != nil {
+		f.ctx.fs.Remove(tmp)
+		return err
+	}
+
+	if f.ctx.files != nil && !f.untracked {
+		rel, _ := filepath.Rel(f.ctx.dir, f.name)
+		f.ctx.files[f.name] = &genFile{
+			manifestFile: manifestFile{
+				Name:      filepath.ToSlash(rel),
+				Generator: f.ctx.gen,
+				Document:  f.ctx.doc,
+				Hash:      hex.EncodeToString(f.hash.Sum(nil)),
+			},
+			dir: f.ctx.dir,
+		}
+	}
+	return nil
+}
+
+func (f *atomicFile) abort() {
+	delete(f.ctx.pending, f)
+
+	f.File.Close()
+	f.ctx.fs.Remove(f.File.Name())
+}
+
+// track is a gen.Middleware, which tracks the document files
+// are currently generated for. Files generated for multiple
+// documents at once, aren't attributed to any document.
+//
+func (ctx *genCtx) track(name string, next gen.GenerateFunc) gen.GenerateFunc {
+	return func(c context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+		ctx.doc = ""
+		if len(docs) == 1 {
+			ctx.doc = docs[0].Name
+		}
+		return next(c, docs, opts)
+	}
+}
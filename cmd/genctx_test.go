@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestGenCtx(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	fs.MkdirAll("/out", 0755)
+	afero.WriteFile(fs, "/out/old.txt", []byte("old"), 0644)
+	afero.WriteFile(fs, "/out/stale.txt", []byte("stale"), 0644)
+
+	ctx := &genCtx{fs: fs, dir: "/out", files: make(map[string]*genFile)}
+
+	w, err := ctx.OpenFile("run.sh", 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("#!/bin/sh\n"))
+	w.Close()
+
+	info, err := fs.Stat("/out/run.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("unexpected mode: %s", info.Mode())
+	}
+
+	b, err := ctx.ReadFile("run.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "#!/bin/sh\n" {
+		t.Errorf("unexpected content: %s", b)
+	}
+
+	_, err = ctx.ReadFile("old.txt")
+	if err == nil {
+		t.Error("expected error when reading file which wasn't generated")
+	}
+
+	err = ctx.Remove("stale.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exists, _ := afero.Exists(fs, "/out/stale.txt"); exists {
+		t.Error("expected stale.txt to be removed")
+	}
+
+	err = ctx.Remove("nonexistent.txt")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenCtx_Create(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/out/resolver.go", []byte("implemented"), 0644)
+
+	ctx := &genCtx{fs: fs, dir: "/out", files: make(map[string]*genFile)}
+
+	_, err := ctx.Create("resolver.go")
+	if !os.IsExist(err) {
+		t.Errorf("expected file to exist, but got: %v", err)
+	}
+	if b, _ := afero.ReadFile(fs, "/out/resolver.go"); string(b) != "implemented" {
+		t.Errorf("expected existing file to be kept, but got: %s", b)
+	}
+
+	w, err := ctx.Create("stubs.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("stubs"))
+	w.Close()
+
+	if b, _ := afero.ReadFile(fs, "/out/stubs.go"); string(b) != "stubs" {
+		t.Errorf("unexpected content: %s", b)
+	}
+	if len(ctx.files) != 0 {
+		t.Errorf("expected created files to be untracked, but got: %v", ctx.files)
+	}
+}
+
+func TestGenCtx_Open(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	ctx := &genCtx{fs: fs, dir: "/out"}
+
+	w, err := ctx.Open("a/b/c.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("abc"))
+
+	if exists, _ := afero.Exists(fs, "/out/a/b/c.txt"); exists {
+		t.Error("expected file to not be written before it's closed")
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := afero.ReadFile(fs, "/out/a/b/c.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "abc" {
+		t.Errorf("unexpected content: %s", b)
+	}
+
+	for name, perm := range map[string]os.FileMode{"/out/a/b/c.txt": filePerm, "/out/a/b": dirPerm | os.ModeDir} {
+		info, err := fs.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode() != perm {
+			t.Errorf("%s: expected mode: %s, but got: %s", name, perm, info.Mode())
+		}
+	}
+
+	assertNoTempFiles(t, fs, "/out")
+}
+
+func TestGenCtx_Sandbox(t *testing.T) {
+	ctx := &genCtx{fs: afero.NewMemMapFs(), dir: "/out"}
+
+	names := []string{
+		"",
+		"/etc/passwd",
+		"../x",
+		"../../etc/x",
+		"a/../../x",
+		"..",
+		".",
+	}
+	for _, name := range names {
+		if _, err := ctx.Open(name); err == nil {
+			t.Errorf("%q: expected error", name)
+		}
+		if err := ctx.Remove(name); err == nil {
+			t.Errorf("%q: expected error", name)
+		}
+	}
+
+	_, err := ctx.Open("a/../b.txt")
+	if err != nil {
+		t.Errorf("expected path within output directory to be allowed: %s", err)
+	}
+}
+
+type errFile struct {
+	afero.File
+}
+
+func (errFile) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestGenCtx_Atomic(t *testing.T) {
+	t.Run("WriteError", func(subT *testing.T) {
+		fs := afero.NewMemMapFs()
+		afero.WriteFile(fs, "/out/a.txt", []byte("old"), 0644)
+		ctx := &genCtx{fs: fs, dir: "/out"}
+
+		w, err := ctx.Open("a.txt")
+		if err != nil {
+			subT.Fatal(err)
+		}
+		af := w.(*atomicFile)
+		af.File = errFile{af.File}
+
+		w.Write([]byte("new"))
+		err = w.Close()
+		if err == nil || err.Error() != "disk full" {
+			subT.Errorf("expected write error, but got: %v", err)
+		}
+
+		b, _ := afero.ReadFile(fs, "/out/a.txt")
+		if string(b) != "old" {
+			subT.Errorf("expected file to be untouched, but got: %s", b)
+		}
+		assertNoTempFiles(subT, fs, "/out")
+	})
+
+	t.Run("NeverClosed", func(subT *testing.T) {
+		fs := afero.NewMemMapFs()
+		ctx := &genCtx{fs: fs, dir: "/out"}
+
+		w, err := ctx.Open("a.txt")
+		if err != nil {
+			subT.Fatal(err)
+		}
+		w.Write([]byte("half"))
+
+		ctx.discard()
+
+		if exists, _ := afero.Exists(fs, "/out/a.txt"); exists {
+			subT.Error("expected file to not be written")
+		}
+		assertNoTempFiles(subT, fs, "/out")
+
+		err = w.Close()
+		if err != nil {
+			subT.Errorf("expected closing discarded file to be a noop: %s", err)
+		}
+	})
+}
+
+func assertNoTempFiles(t *testing.T, fs afero.Fs, dir string) {
+	t.Helper()
+
+	afero.Walk(fs, dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && strings.Contains(info.Name(), ".tmp") {
+			t.Errorf("unexpected temporary file: %s", path)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,200 @@
+// graph.go implements the graph command, which visualizes documents
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/gqlc/gqlc/compile"
+	"github.com/gqlc/gqlc/graph"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// digraph is a directed graph, which can be rendered in multiple formats.
+type digraph struct {
+	name  string
+	nodes []string
+	edges map[string][]string
+}
+
+// writeDOT renders the graph in the Graphviz DOT language.
+func (g *digraph) writeDOT(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "digraph %s {\n", g.name)
+	for _, n := range g.nodes {
+		fmt.Fprintf(&b, "\t%s;\n", strconv.Quote(n))
+
+		for _, e := range g.edges[n] {
+			fmt.Fprintf(&b, "\t%s -> %s;\n", strconv.Quote(n), strconv.Quote(e))
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// writeMermaid renders the graph as a Mermaid flowchart.
+func (g *digraph) writeMermaid(w io.Writer) error {
+	ids := make(map[string]string, len(g.nodes))
+	for i, n := range g.nodes {
+		ids[n] = "n" + strconv.Itoa(i)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("graph LR\n")
+	for _, n := range g.nodes {
+		fmt.Fprintf(&b, "\t%s[%s]\n", ids[n], strconv.Quote(n))
+	}
+	for _, n := range g.nodes {
+		for _, e := range g.edges[n] {
+			fmt.Fprintf(&b, "\t%s --> %s\n", ids[n], ids[e])
+		}
+	}
+
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// writeGraph renders a graph in the given format. v is the
+// value, which is encoded when rendering as JSON.
+//
+func writeGraph(w io.Writer, format string, g *digraph, v interface{}) error {
+	switch format {
+	case "dot":
+		return g.writeDOT(w)
+	case "mermaid":
+		return g.writeMermaid(w)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	default:
+		return fmt.Errorf("gqlc: unknown graph format: %s", format)
+	}
+}
+
+// newGraphCmd returns the graph command, which fetches remote
+// documents like gqlc does, e.g. with the headers given by -H.
+//
+func (c *gqlcCmd) newGraphCmd(fs afero.Fs) *baseCmd {
+	cmd := &baseCmd{
+		Command: &cobra.Command{
+			Use:   "graph",
+			Short: "Print the graph of the given documents",
+			Long: `graph prints either the import graph of the given documents and all of
+			their imports or the type reference graph of the compiled schema in the
+			DOT, Mermaid or JSON format.`,
+			Example: "gqlc graph -I . --type_refs --focus User --depth 2 --format mermaid api.gql",
+			Args: func(cmd *cobra.Command, args []string) error {
+				err := cobra.MinimumNArgs(1)(cmd, args)
+				if err != nil {
+					return err
+				}
+
+				return validateFilenames(cmd, args)
+			},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ipaths, err := cmd.Flags().GetStringSlice("import_path")
+				if err != nil {
+					return err
+				}
+
+				format, err := cmd.Flags().GetString("format")
+				if err != nil {
+					return err
+				}
+
+				imports, err := cmd.Flags().GetBool("imports")
+				if err != nil {
+					return err
+				}
+
+				types, err := cmd.Flags().GetBool("type_refs")
+				if err != nil {
+					return err
+				}
+
+				switch {
+				case imports && types:
+					return fmt.Errorf("gqlc: graph accepts either --imports or --type_refs, not both")
+				case imports:
+					return graphImports(cmd.OutOrStdout(), fs, c.fetcher(), ipaths, format, args...)
+				case types:
+					focus, _ := cmd.Flags().GetString("focus")
+					depth, _ := cmd.Flags().GetInt("depth")
+
+					return graphTypes(cmd.OutOrStdout(), fs, c.fetcher(), ipaths, format, focus, depth, args...)
+				default:
+					return fmt.Errorf("gqlc: graph requires either --imports or --type_refs")
+				}
+			},
+			SilenceUsage:  true,
+			SilenceErrors: true,
+		},
+	}
+
+	cmd.Flags().StringSliceP("import_path", "I", []string{"."}, "Specify the directory in which to search for imports.")
+	cmd.Flags().Bool("imports", false, "Print the import graph of the documents.")
+	cmd.Flags().Bool("type_refs", false, "Print the type reference graph of the compiled schema.")
+	cmd.Flags().String("focus", "", "Only print the types referencing or referenced by the given type.")
+	cmd.Flags().Int("depth", -1, "Limit the number of references followed from the focused type.")
+	cmd.Flags().StringP("format", "f", "dot", "Output format: dot, mermaid or json")
+
+	return cmd
+}
+
+// graphImports prints the import graph of the given documents.
+func graphImports(w io.Writer, fs afero.Fs, fetcher compile.Fetcher, ipaths []string, format string, args ...string) error {
+	ig, err := compile.Imports(context.Background(), compile.Options{
+		Inputs:      args,
+		ImportPaths: ipaths,
+		FS:          fs,
+		Fetcher:     fetcher,
+	})
+	if err != nil {
+		return err
+	}
+
+	g := &digraph{name: "imports", nodes: ig.Documents, edges: ig.Imports}
+	return writeGraph(w, format, g, ig)
+}
+
+// graphTypes prints the type reference graph of the given documents.
+func graphTypes(w io.Writer, fs afero.Fs, fetcher compile.Fetcher, ipaths []string, format, focus string, depth int, args ...string) error {
+	res, err := compile.Compile(context.Background(), compile.Options{
+		Inputs:      args,
+		ImportPaths: ipaths,
+		FS:          fs,
+		Fetcher:     fetcher,
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(res.Documents, func(i, j int) bool { return res.Documents[i].Name < res.Documents[j].Name })
+
+	tg := graph.Types(res.Documents...)
+	if focus != "" {
+		tg, err = tg.Focus(focus, depth)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch format {
+	case "dot":
+		return tg.WriteDOT(w)
+	case "mermaid":
+		return tg.WriteMermaid(w)
+	default:
+		return writeGraph(w, format, nil, tg)
+	}
+}
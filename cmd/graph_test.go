@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGraphImports(t *testing.T) {
+	ipaths := []string{"/home", "/home/graphql/imports"}
+
+	testCases := []struct {
+		Name   string
+		Format string
+		Ex     string
+	}{
+		{
+			Name:   "DOT",
+			Format: "dot",
+			Ex: `digraph imports {
+	"two";
+	"two" -> "thr";
+	"thr";
+}
+`,
+		},
+		{
+			Name:   "Mermaid",
+			Format: "mermaid",
+			Ex: `graph LR
+	n0["two"]
+	n1["thr"]
+	n0 --> n1
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var b bytes.Buffer
+			err := graphImports(&b, testFs, nil, ipaths, testCase.Format, "two.gql")
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if b.String() != testCase.Ex {
+				subT.Errorf("expected:\n%s\nbut got:\n%s", testCase.Ex, b.String())
+			}
+		})
+	}
+
+	t.Run("JSON", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := graphImports(&b, testFs, nil, ipaths, "json", "two.gql")
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		var g struct {
+			Documents []string
+			Imports   map[string][]string
+		}
+		err = json.Unmarshal(b.Bytes(), &g)
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if len(g.Documents) != 2 || len(g.Imports["two"]) != 1 || g.Imports["two"][0] != "thr" {
+			subT.Errorf("unexpected graph: %s", b.String())
+		}
+	})
+
+	t.Run("UnknownFormat", func(subT *testing.T) {
+		err := graphImports(new(bytes.Buffer), testFs, nil, ipaths, "svg", "two.gql")
+		if err == nil {
+			subT.Error("expected error for unknown format")
+		}
+	})
+}
+
+func TestGraphTypes(t *testing.T) {
+	ipaths := []string{"/usr/imports", "/home/graphql/imports"}
+
+	t.Run("Focus", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := graphTypes(&b, testFs, nil, ipaths, "mermaid", "Obj", 1, "five.gql")
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		ex := `classDiagram
+	class Obj {
+		v: Version
+	}
+	class T {
+		v: Version
+		obj: Obj
+	}
+	T --> Obj : obj
+`
+		if b.String() != ex {
+			subT.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+		}
+	})
+
+	t.Run("UnknownType", func(subT *testing.T) {
+		err := graphTypes(new(bytes.Buffer), testFs, nil, ipaths, "dot", "Unknown", -1, "five.gql")
+		if err == nil {
+			subT.Error("expected error for unknown type")
+		}
+	})
+}
+
+func TestCli_Graph(t *testing.T) {
+	c := NewCLI(WithFS(testFs))
+
+	err := c.Run([]string{"gqlc", "graph", "-I", "/home/graphql/imports", "thr.gql"})
+	if err == nil {
+		t.Error("expected error without --imports")
+	}
+
+	err = c.Run([]string{"gqlc", "graph", "--imports", "-I", "/home/graphql/imports", "thr.gql"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = c.Run([]string{"gqlc", "graph", "--imports", "--type_refs", "-I", "/home/graphql/imports", "thr.gql"})
+	if err == nil {
+		t.Error("expected error with both --imports and --type_refs")
+	}
+}
+
+func TestCli_GraphHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Hello") != "World" {
+			t.Errorf("expected header Hello: World, but got: %v", req.Header)
+		}
+
+		w.Write(testIntroResp)
+	}))
+	defer srv.Close()
+
+	c := NewCLI(WithFS(testFs))
+	err := c.Run([]string{"gqlc", "graph", "--type_refs", "-H", "Hello=World", fmt.Sprintf("http://%s/graphql", srv.Listener.Addr())})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,183 @@
+// manifest.go tracks the files generated in each output directory
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+)
+
+// manifestName is the name of the manifest
+// file kept in each output directory.
+//
+const manifestName = ".gqlc-manifest.json"
+
+// manifest lists every file generated in an output directory.
+type manifest struct {
+	Files []manifestFile `json:"files"`
+}
+
+// manifestFile records a single generated file.
+type manifestFile struct {
+	// Name is the file name relative to the output directory.
+	Name string `json:"name"`
+
+	// Generator is the name of the generator which generated the file.
+	Generator string `json:"generator"`
+
+	// Document is the name of the document the file was generated from.
+	Document string `json:"document"`
+
+	// Hash is the hex encoded SHA-256 hash of the file contents.
+	Hash string `json:"sha256"`
+}
+
+// genFile records a file generated during a run.
+type genFile struct {
+	manifestFile
+
+	dir string
+}
+
+func hashBytes(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// readManifest reads the manifest of an output directory.
+// A missing manifest is treated as an empty one.
+//
+func readManifest(fs afero.Fs, dir string) (*manifest, error) {
+	m := new(manifest)
+
+	b, err := afero.ReadFile(fs, filepath.Join(dir, manifestName))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, m)
+	if err != nil {
+		return nil, fmt.Errorf("gqlc: malformed manifest: %s: %w", filepath.Join(dir, manifestName), err)
+	}
+	return m, nil
+}
+
+// writeManifest writes the manifest of an output directory.
+func writeManifest(fs afero.Fs, dir string, m *manifest) error {
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Name < m.Files[j].Name })
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	ctx := &genCtx{fs: fs, dir: dir}
+	w, err := ctx.Open(manifestName)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(b, '\n'))
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// updateManifests updates the manifest of every output directory with the
+// files generated during a run. Files listed in a previous manifest by a
+// generator, which ran again, but didn't generate them anymore, are stale.
+// Stale files are reported or, if clean is set, deleted. Stale files, which
+// weren't deleted, stay in the manifest, so later runs still find them.
+//
+func updateManifests(fs afero.Fs, geners []generator, files map[string]*genFile, clean bool) error {
+	ran := make(map[string]map[string]bool)
+	for _, g := range geners {
+		if ran[g.outDir] == nil {
+			ran[g.outDir] = make(map[string]bool)
+		}
+		ran[g.outDir][g.name] = true
+	}
+
+	cur := make(map[string][]manifestFile, len(ran))
+	for _, f := range files {
+		cur[f.dir] = append(cur[f.dir], f.manifestFile)
+	}
+
+	for dir, gens := range ran {
+		old, err := readManifest(fs, dir)
+		if err != nil {
+			return err
+		}
+
+		m := &manifest{Files: cur[dir]}
+		ctx := &genCtx{fs: fs, dir: dir}
+		for _, of := range old.Files {
+			fname, err := ctx.resolve(of.Name)
+			if err != nil {
+				return fmt.Errorf("gqlc: malformed manifest: %s: %w", filepath.Join(dir, manifestName), err)
+			}
+
+			if files[fname] != nil {
+				continue
+			}
+
+			if !gens[of.Generator] {
+				m.Files = append(m.Files, of)
+				continue
+			}
+
+			kept, err := removeStale(fs, fname, of, clean)
+			if err != nil {
+				return err
+			}
+			if kept {
+				m.Files = append(m.Files, of)
+			}
+		}
+
+		zap.L().Info("writing manifest", zap.String("dir", dir), zap.Int("files", len(m.Files)))
+		err = writeManifest(fs, dir, m)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeStale reports or deletes a stale file and returns whether it still
+// exists. Stale files which were modified since they were generated are
+// never deleted.
+//
+func removeStale(fs afero.Fs, fname string, f manifestFile, clean bool) (kept bool, err error) {
+	b, err := afero.ReadFile(fs, fname)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case !clean:
+		log.Printf("gqlc: stale file: %s (generated by %s from %s), use --clean to delete it\n", fname, f.Generator, f.Document)
+	case hashBytes(b) != f.Hash:
+		log.Printf("gqlc: stale file: %s (generated by %s from %s) was modified, so it was not deleted\n", fname, f.Generator, f.Document)
+	default:
+		zap.L().Info("deleting stale file", zap.String("file", fname))
+		return false, fs.Remove(fname)
+	}
+	return true, nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func generate(t *testing.T, fs afero.Fs, files map[string]*genFile, g generator, doc string, names ...string) {
+	ctx := &genCtx{fs: fs, dir: g.outDir, gen: g.name, doc: doc, files: files}
+	for _, name := range names {
+		w, err := ctx.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(name))
+		w.Close()
+	}
+}
+
+func TestUpdateManifests(t *testing.T) {
+	docG := generator{name: "doc", outDir: "/out"}
+	jsG := generator{name: "js", outDir: "/out"}
+
+	testCases := []struct {
+		Name   string
+		Clean  bool
+		Modify bool
+		Exists bool
+
+		// Files are the expected names in the manifest
+		Files []string
+	}{
+		{
+			Name:   "Report",
+			Exists: true,
+			Files:  []string{"a.md", "b.md", "c.js"},
+		},
+		{
+			Name:   "Clean",
+			Clean:  true,
+			Exists: false,
+			Files:  []string{"a.md", "c.js"},
+		},
+		{
+			Name:   "Modified",
+			Clean:  true,
+			Modify: true,
+			Exists: true,
+			Files:  []string{"a.md", "b.md", "c.js"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			fs := afero.NewMemMapFs()
+
+			// First run generates a.md, b.md and c.js
+			files := make(map[string]*genFile)
+			generate(subT, fs, files, docG, "a", "a.md", "b.md")
+			generate(subT, fs, files, jsG, "a", "c.js")
+			err := updateManifests(fs, []generator{docG, jsG}, files, false)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if testCase.Modify {
+				afero.WriteFile(fs, "/out/b.md", []byte("modified"), 0644)
+			}
+
+			// Second run only runs the doc generator, which doesn't generate b.md anymore
+			files = make(map[string]*genFile)
+			generate(subT, fs, files, docG, "a", "a.md")
+			err = updateManifests(fs, []generator{docG}, files, testCase.Clean)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if exists, _ := afero.Exists(fs, "/out/b.md"); exists != testCase.Exists {
+				subT.Errorf("expected b.md to exist: %v", testCase.Exists)
+			}
+
+			m, err := readManifest(fs, "/out")
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if len(m.Files) != len(testCase.Files) {
+				subT.Fatalf("expected %v in manifest, but got: %v", testCase.Files, m.Files)
+			}
+			for i, name := range testCase.Files {
+				if m.Files[i].Name != name {
+					subT.Errorf("expected %s in manifest, but got: %v", name, m.Files[i])
+				}
+			}
+
+			a, c := m.Files[0], m.Files[len(m.Files)-1]
+			if a.Name != "a.md" || a.Generator != "doc" || a.Document != "a" || a.Hash != hashBytes([]byte("a.md")) {
+				subT.Errorf("unexpected manifest entry: %v", a)
+			}
+			if c.Name != "c.js" || c.Generator != "js" {
+				subT.Errorf("unexpected manifest entry: %v", c)
+			}
+		})
+	}
+}
+
+func TestUpdateManifests_CleanReported(t *testing.T) {
+	docG := generator{name: "doc", outDir: "/out"}
+	fs := afero.NewMemMapFs()
+
+	files := make(map[string]*genFile)
+	generate(t, fs, files, docG, "a", "a.md", "b.md")
+	err := updateManifests(fs, []generator{docG}, files, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// b.md is only reported as stale, so a later run with --clean deletes it
+	for _, clean := range []bool{false, true} {
+		files = make(map[string]*genFile)
+		generate(t, fs, files, docG, "a", "a.md")
+		err = updateManifests(fs, []generator{docG}, files, clean)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if exists, _ := afero.Exists(fs, "/out/b.md"); exists {
+		t.Error("expected b.md to be deleted")
+	}
+}
@@ -1,15 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/gqlc/compiler"
-	"github.com/gqlc/compiler/spec"
-	"github.com/gqlc/graphql/ast"
-	"github.com/gqlc/graphql/token"
+	"github.com/gqlc/gqlc/compile"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -41,6 +38,9 @@ func validateFilenames(cmd *cobra.Command, args []string) error {
 }
 
 // validatePluginTypes parses and validates any types given by the --types flag.
+// Their type errors are returned as compile.Errors and fail the command,
+// since invalid types can't be registered.
+//
 func (c *gqlcCmd) validatePluginTypes(fs afero.Fs) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		pluginTypes, _ := cmd.Flags().GetStringSlice("types")
@@ -48,31 +48,13 @@ func (c *gqlcCmd) validatePluginTypes(fs afero.Fs) func(*cobra.Command, []string
 			return nil
 		}
 
-		docMap := make(map[string]*ast.Document, len(pluginTypes))
-		err := c.parseInputFiles(fs, token.NewDocSet(), docMap, pluginTypes...)
-		if err != nil {
-			return err
-		}
-
-		docs := make([]*ast.Document, 0, len(docMap))
-		for _, doc := range docMap {
-			docs = append(docs, doc)
-		}
-
-		docsIR := compiler.ToIR(docs)
-
-		docsIR, err = compiler.ReduceImports(docsIR)
-		if err != nil {
-			return err
-		}
-
-		errs := compiler.CheckTypes(docsIR, spec.Validator, compiler.ImportValidator)
-		if len(errs) > 0 {
-			// TODO: Compound errs
-			return nil
-		}
-
-		return nil
+		_, err := compile.Compile(context.Background(), compile.Options{
+			Types:       pluginTypes,
+			ImportPaths: c.cfg.ipaths,
+			FS:          fs,
+			Fetcher:     c.fetcher(),
+		})
+		return err
 	}
 }
 
@@ -81,7 +63,7 @@ func initGenDirs(fs afero.Fs, dirs *[]string) func(*cobra.Command, []string) err
 	return func(cmd *cobra.Command, args []string) (err error) {
 		for _, dir := range *dirs {
 			zap.S().Info("creating directory:", dir)
-			err = fs.MkdirAll(dir, os.ModeDir)
+			err = fs.MkdirAll(dir, dirPerm)
 			if err != nil {
 				break
 			}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/gqlc/gqlc/compile"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
@@ -45,6 +46,26 @@ func TestValidatePluginTypes(t *testing.T) {
 		t.Error(err)
 		return
 	}
+
+	t.Run("TypeErrors", func(subT *testing.T) {
+		err := afero.WriteFile(fs, "invalid.gql", []byte(`interface I { a: Int } type A implements I { b: Int }`), 0644)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		cmd := &gqlcCmd{
+			Command: &cobra.Command{},
+			cfg: &gqlcConfig{
+				ipaths: []string{"."},
+			},
+		}
+		cmd.Flags().StringSlice("types", []string{"invalid.gql"}, "")
+
+		err = cmd.validatePluginTypes(fs)(cmd.Command, nil)
+		if _, ok := err.(compile.Errors); !ok {
+			subT.Errorf("expected type errors, but got: %v", err)
+		}
+	})
 }
 
 var (
@@ -2,22 +2,15 @@ package cmd
 
 import (
 	"context"
-	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
-	"path/filepath"
 	"strings"
 	"text/scanner"
 
-	"github.com/gqlc/compiler"
-	"github.com/gqlc/compiler/spec"
+	"github.com/gqlc/gqlc/compile"
 	"github.com/gqlc/gqlc/gen"
-	"github.com/gqlc/graphql/ast"
-	"github.com/gqlc/graphql/parser"
-	"github.com/gqlc/graphql/token"
+	"github.com/gqlc/gqlc/operation"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -27,6 +20,10 @@ import (
 type gqlcConfig struct {
 	ipaths []string
 	geners []generator
+	clean  bool
+	prune  compile.PruneOptions
+	order  compile.Order
+	mws    []gen.Middleware
 
 	logger  *zap.Logger
 	client  *fetchClient
@@ -45,6 +42,7 @@ func (c *CommandLine) newGqlcCmd(cfgs []genConfig, fs afero.Fs, pluginPrefix str
 	cc := &gqlcCmd{
 		cfg: &gqlcConfig{
 			geners:  make([]generator, 0, len(cfgs)),
+			mws:     c.mws,
 			client:  defaultClient,
 			headers: make(http.Header),
 		},
@@ -90,6 +88,36 @@ func (c *CommandLine) newGqlcCmd(cfgs []genConfig, fs afero.Fs, pluginPrefix str
 			},
 			func(cmd *cobra.Command, args []string) (err error) {
 				cc.cfg.ipaths, err = cmd.Flags().GetStringSlice("import_path")
+				if err != nil {
+					return
+				}
+
+				cc.cfg.clean, err = cmd.Flags().GetBool("clean")
+				if err != nil {
+					return
+				}
+
+				cc.cfg.prune.Exclude, err = cmd.Flags().GetStringSlice("exclude_directive")
+				if err != nil {
+					return
+				}
+
+				cc.cfg.prune.Unreachable, err = cmd.Flags().GetBool("prune_unreachable")
+				if err != nil {
+					return
+				}
+
+				cc.cfg.prune.Keep, err = cmd.Flags().GetStringSlice("keep_type")
+				if err != nil {
+					return
+				}
+
+				order, err := cmd.Flags().GetString("order")
+				if err != nil {
+					return
+				}
+
+				cc.cfg.order, err = compile.ParseOrder(order)
 				return
 			},
 			cc.validatePluginTypes(c.fs),
@@ -112,8 +140,13 @@ imports.  May be specified multiple times;
 directories will be searched in order.  If not
 given, the current working directory is used.`)
 	cc.Flags().BoolP("verbose", "v", false, "Output logging")
+	cc.Flags().Bool("clean", false, "Delete files generated by a previous run, which are no longer generated.")
+	cc.Flags().StringSlice("exclude_directive", nil, "Remove types, fields, arguments and enum values marked by the given directive e.g. internal.")
+	cc.Flags().Bool("prune_unreachable", false, "Remove types, which are unreachable from the root operation types.")
+	cc.Flags().StringSlice("keep_type", nil, "Only keep the given types and the types reachable from them.")
+	cc.Flags().String("order", string(compile.KindOrder), "Order of the types handed to generators: source, kind, alpha or topological. The doc generator keeps its sections by kind and only orders the types within them.")
 	cc.Flags().StringSliceP("types", "t", nil, "Provide .gql files containing types you wish to register with the compiler.")
-	cc.Flags().VarP(&headerFlag{value: &cc.cfg.headers}, "headers", "H", "Provide HTTP headers to fetching. Format: a=1,b=2")
+	cc.PersistentFlags().VarP(&headerFlag{value: &cc.cfg.headers}, "headers", "H", "Provide HTTP headers to fetching. Format: a=1,b=2")
 
 	fp := &fparser{
 		Scanner: new(scanner.Scanner),
@@ -122,6 +155,7 @@ given, the current working directory is used.`)
 	for _, cfg := range cfgs {
 		f := genFlag{
 			g:       cfg.g,
+			name:    strings.TrimSuffix(cfg.name, "_out"),
 			opts:    make(map[string]interface{}),
 			geners:  &cc.cfg.geners,
 			outDirs: &outDirs,
@@ -139,255 +173,60 @@ given, the current working directory is used.`)
 	return cc
 }
 
-type genCtx struct {
-	fs  afero.Fs
-	dir string
-}
-
-func (ctx *genCtx) Open(name string) (io.WriteCloser, error) {
-	f, err := ctx.fs.OpenFile(filepath.Join(ctx.dir, name), os.O_WRONLY|os.O_CREATE, 0755)
-	if err != nil {
-		return nil, err
-	}
-
-	return f, f.Truncate(0)
-}
-
 type generator struct {
 	gen.Generator
 
+	name   string
 	opts   map[string]interface{}
 	outDir string
 }
 
 func (c *gqlcCmd) run(fs afero.Fs, args ...string) (err error) {
-	// Parse files
-	zap.S().Info("parsing input files")
-	docMap := make(map[string]*ast.Document, len(args))
-	err = c.parseInputFiles(fs, token.NewDocSet(), docMap, args...)
-	if err != nil {
-		return
-	}
-
-	zap.S().Info("resolving import paths")
-	docs := make([]*ast.Document, 0, len(docMap))
-	for _, doc := range docMap {
-		docs = append(docs, doc)
-	}
-	resolveImportPaths(docs)
-
-	docsIR := compiler.ToIR(docs)
-
-	// Resolve imports (this must occur before type checking)
-	zap.S().Info("reducing imports")
-	docsIR, err = compiler.ReduceImports(docsIR)
-	if err != nil {
-		return err
-	}
-
-	// Add any missing fields to objects that implement interfaces
-	zap.S().Info("implementing interfaces")
-	docsIR = implInterfaces(docsIR)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Perform type checking
-	zap.S().Info("type checking")
-	errs := compiler.CheckTypes(docsIR, spec.Validator, compiler.ImportValidator)
-	if len(errs) > 0 {
+	res, err := compile.Compile(ctx, compile.Options{
+		Inputs:      args,
+		ImportPaths: c.cfg.ipaths,
+		FS:          fs,
+		Fetcher:     c.fetcher(),
+		Prune:       c.cfg.prune,
+		Order:       c.cfg.order,
+	})
+	if errs, ok := err.(compile.Errors); ok {
 		for _, err = range errs {
 			log.Println(err)
 		}
 		return
 	}
-
-	// Merge type extensions with the original type definitions
-	zap.S().Info("merging type extensions")
-	for d, types := range docsIR {
-		docsIR[d] = compiler.MergeExtensions(types)
-	}
-
-	// Convert types from IR to []*ast.TypeDecl
-	docs = compiler.FromIR(docsIR)
-	for _, doc := range docs {
-		doc.Types = sortTypeDecls(doc.Types)
+	if err != nil {
+		return
 	}
 
 	// Run code generators
 	zap.S().Info("generating documents")
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx = gen.WithSources(gen.WithDocSet(ctx, res.DocSet), res.Sources)
+	ctx = operation.WithDocuments(ctx, res.Operations)
+	files := make(map[string]*genFile)
 	for _, g := range c.cfg.geners {
-		ctx = gen.WithContext(ctx, &genCtx{dir: g.outDir, fs: fs})
-
-		for _, doc := range docs {
-			err = g.Generate(ctx, doc, g.opts)
-			if err != nil {
-				return
-			}
-		}
-	}
-	return
-}
+		gCtx := &genCtx{dir: g.outDir, fs: fs, gen: g.name, files: files}
+		defer gCtx.discard()
 
-// resolveImportPaths makes sure import paths and doc names are consistent.
-func resolveImportPaths(docs []*ast.Document) {
-	for _, d := range docs {
-		filename := filepath.Base(d.Name)
-		d.Name = filename[:len(filename)-len(filepath.Ext(filename))]
+		ctx = gen.WithContext(ctx, gCtx)
 
-		for _, direc := range d.Directives {
-			if direc.Name != "import" {
-				continue
-			}
-
-			for _, arg := range direc.Args.Args {
-
-				compLit := arg.Value.(*ast.Arg_CompositeLit).CompositeLit
-				listLit := compLit.Value.(*ast.CompositeLit_ListLit).ListLit.List
-
-				var paths []*ast.BasicLit
-				switch v := listLit.(type) {
-				case *ast.ListLit_BasicList:
-					paths = append(paths, v.BasicList.Values...)
-				case *ast.ListLit_CompositeList:
-					cpaths := v.CompositeList.Values
-					paths = make([]*ast.BasicLit, len(cpaths))
-					for i, c := range cpaths {
-						paths[i] = c.Value.(*ast.CompositeLit_BasicLit).BasicLit
-					}
-				}
-
-				for _, p := range paths {
-					iPath := strings.Trim(p.Value, "\"")
-					iName := filepath.Base(iPath)
-
-					p.Value = fmt.Sprintf(`"%s"`, iName[:len(iName)-len(filepath.Ext(iName))])
-				}
-			}
-		}
-	}
-}
-
-// parseInputFiles parses all input files from the command line args, as well as any imported files.
-func (c *gqlcCmd) parseInputFiles(fs afero.Fs, dset *token.DocSet, docs map[string]*ast.Document, filenames ...string) error {
-	for _, filename := range filenames {
-		name := filepath.Base(filename)
-		if _, exists := docs[name]; exists {
-			continue
-		}
-
-		zap.L().Info("opening input", zap.String("name", filename))
-		f, err := c.openFile(filename, fs)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-
-		doc, err := parser.ParseDoc(dset, name, f, parser.ParseComments)
-		if err != nil {
-			return err
-		}
-
-		docs[name] = doc
-	}
-
-	for _, doc := range docs {
-		imports := getImports(doc)
-		imports = filter(imports, docs, fs, c.cfg.ipaths)
-		if len(imports) == 0 {
-			continue
-		}
-
-		err := c.parseInputFiles(fs, dset, docs, imports...)
+		mws := append([]gen.Middleware{gCtx.track}, c.cfg.mws...)
+		err = gen.Run(ctx, g.name, g.Generator, res.Documents, g.opts, mws...)
 		if err != nil {
-			return err
+			return
 		}
 	}
 
-	return nil
+	// Update output manifests and handle stale files
+	zap.S().Info("updating manifests")
+	return updateManifests(fs, c.cfg.geners, files, c.cfg.clean)
 }
 
-func getImports(doc *ast.Document) (names []string) {
-	for _, direc := range doc.Directives {
-		if direc.Name != "import" {
-			continue
-		}
-
-		for _, arg := range direc.Args.Args {
-
-			compLit := arg.Value.(*ast.Arg_CompositeLit).CompositeLit
-			listLit := compLit.Value.(*ast.CompositeLit_ListLit).ListLit.List
-
-			var paths []*ast.BasicLit
-			switch v := listLit.(type) {
-			case *ast.ListLit_BasicList:
-				paths = append(paths, v.BasicList.Values...)
-			case *ast.ListLit_CompositeList:
-				cpaths := v.CompositeList.Values
-				paths = make([]*ast.BasicLit, len(cpaths))
-				for i, c := range cpaths {
-					paths[i] = c.Value.(*ast.CompositeLit_BasicLit).BasicLit
-				}
-			}
-
-			for _, p := range paths {
-				names = append(names, strings.Trim(p.Value, "\""))
-			}
-		}
-	}
-
-	return
-}
-
-func (c *gqlcCmd) openFile(name string, fs afero.Fs) (io.ReadCloser, error) {
-	endpoint, err := url.Parse(name)
-	if err != nil {
-		return nil, err
-	}
-	if endpoint.Scheme != "" && endpoint.Opaque == "" {
-		return fetch(c.cfg.client, endpoint, c.cfg.headers)
-	}
-
-	fname, err := normFilePath(fs, c.cfg.ipaths, name)
-	if err != nil {
-		return nil, err
-	}
-	if fname == "" {
-		return nil, fmt.Errorf("could not resolve file path: %s", name)
-	}
-
-	return fs.Open(fname)
-}
-
-// normFilePath converts any path to absolute path
-func normFilePath(fs afero.Fs, iPaths []string, filename string) (string, error) {
-	if filepath.IsAbs(filename) {
-		return filename, nil
-	}
-
-	for _, iPath := range iPaths {
-		fname := filepath.Join(iPath, filename)
-		exists, err := afero.Exists(fs, fname)
-		if err != nil {
-			return "", err
-		}
-
-		if exists {
-			return fname, nil
-		}
-	}
-	return "", nil
-}
-
-// filter filters the strings in b from a
-func filter(a []string, b map[string]*ast.Document, fs afero.Fs, iPaths []string) []string {
-	n := 0
-	for _, x := range a {
-		name := filepath.Base(x)
-
-		if _, exists := b[name]; !exists {
-			a[n] = x
-			n++
-		}
-	}
-	return a[:n]
+// fetcher returns a compile.Fetcher for remote documents.
+func (c *gqlcCmd) fetcher() compile.Fetcher {
+	return newFetcher(c.cfg.client, c.cfg.headers)
 }
@@ -1,18 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
-	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/gqlc/gqlc/compile"
 	"github.com/gqlc/gqlc/gen"
 	"github.com/gqlc/graphql/ast"
-	"github.com/gqlc/graphql/token"
 	"github.com/spf13/afero"
 )
 
@@ -61,113 +61,6 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-func TestParseInputFiles(t *testing.T) {
-	// Create test cases
-	testCases := []struct {
-		Name        string
-		ImportPaths []string
-		Args        []string
-		Len         int
-	}{
-		{
-			Name: "SingleWithAbs",
-			Len:  1,
-			Args: []string{"/home/graphql/imports/thr.gql"},
-		},
-		{
-			Name:        "SingleWithRel",
-			Len:         1,
-			Args:        []string{"./thr.gql"},
-			ImportPaths: []string{"/home/graphql/imports"},
-		},
-		{
-			Name:        "SingleWithIpath",
-			Len:         1,
-			Args:        []string{"thr.gql"},
-			ImportPaths: []string{"/home/graphql/imports"},
-		},
-		{
-			Name:        "MSingle",
-			Len:         2,
-			Args:        []string{"thr.gql", "four.gql"},
-			ImportPaths: []string{"/home/graphql/imports", "/home"},
-		},
-		{
-			Name:        "TreeIPath",
-			Len:         5,
-			Args:        []string{"one.gql"},
-			ImportPaths: []string{"/home", "/home/graphql", "/home/graphql/imports", "/usr/imports"},
-		},
-		{
-			Name: "TreeAllArgs",
-			Len:  5,
-			Args: []string{"/home/graphql/one.gql", "/home/graphql/imports/two.gql", "/home/graphql/imports/thr.gql", "/home/four.gql", "/usr/imports/six.gql"},
-		},
-		{
-			Name:        "MTreeIPaths",
-			Len:         6,
-			Args:        []string{"one.gql", "five.gql"},
-			ImportPaths: []string{"/home", "/home/graphql", "/home/graphql/imports", "/usr/imports"},
-		},
-		{
-			Name: "MTreeAllArgs",
-			Len:  6,
-			Args: []string{"/home/graphql/one.gql", "/home/graphql/imports/two.gql", "/home/graphql/imports/thr.gql", "/home/four.gql", "/usr/imports/five.gql", "/usr/imports/six.gql"},
-		},
-	}
-
-	// Run test cases
-	for _, testCase := range testCases {
-		t.Run(testCase.Name, func(subT *testing.T) {
-			cmd := &gqlcCmd{
-				cfg: &gqlcConfig{ipaths: testCase.ImportPaths},
-			}
-
-			docMap := make(map[string]*ast.Document, len(testCase.Args))
-			err := cmd.parseInputFiles(testFs, token.NewDocSet(), docMap, testCase.Args...)
-			if err != nil {
-				subT.Error(err)
-				return
-			}
-
-			if len(docMap) != testCase.Len {
-				subT.Fail()
-				return
-			}
-
-			for _, doc := range docMap {
-				for _, direc := range doc.Directives {
-					for _, arg := range direc.Args.Args {
-						compLit := arg.Value.(*ast.Arg_CompositeLit).CompositeLit
-						listLit := compLit.Value.(*ast.CompositeLit_ListLit).ListLit.List
-
-						var paths []*ast.BasicLit
-						switch v := listLit.(type) {
-						case *ast.ListLit_BasicList:
-							paths = append(paths, v.BasicList.Values...)
-						case *ast.ListLit_CompositeList:
-							cpaths := v.CompositeList.Values
-							paths = make([]*ast.BasicLit, len(cpaths))
-							for i, c := range cpaths {
-								paths[i] = c.Value.(*ast.CompositeLit_BasicLit).BasicLit
-							}
-						}
-
-						for _, p := range paths {
-							iPath := strings.Trim(p.Value, "\"")
-							iName := filepath.Base(iPath)
-							if _, exists := docMap[iName]; !exists {
-								subT.Fail()
-								return
-							}
-						}
-					}
-				}
-			}
-		})
-	}
-}
-
 func TestRun(t *testing.T) {
 	testCases := []struct {
 		Name   string
@@ -265,6 +158,108 @@ type Bytes implements Iterator {
 	}
 }
 
+func TestRun_DocsGenerator(t *testing.T) {
+	g := gen.NewMockDocsGenerator(gomock.NewController(t))
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	g.EXPECT().GenerateDocs(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+		if len(docs) != 2 {
+			t.Errorf("expected both documents, but got: %d", len(docs))
+		}
+		return nil
+	})
+
+	geners := []generator{{
+		Generator: g,
+	}}
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: geners,
+			ipaths: []string{"/usr/imports", "/home", "/home/graphql", "/home/graphql/imports"},
+		},
+	}
+
+	err := cmd.run(testFs, "five.gql", "four.gql")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestRun_Prune(t *testing.T) {
+	g := newMockGenerator(t)
+	g.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+		if len(doc.Types) != 2 {
+			t.Errorf("expected only Obj and Version, but got: %d types", len(doc.Types))
+		}
+		return nil
+	})
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g}},
+			ipaths: []string{"/usr/imports", "/home/graphql/imports"},
+			prune:  compile.PruneOptions{Keep: []string{"Obj"}},
+		},
+	}
+
+	err := cmd.run(testFs, "five.gql")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+type hookGenerator struct {
+	calls []string
+}
+
+func (g *hookGenerator) Init(ctx context.Context, opts map[string]interface{}) error {
+	g.calls = append(g.calls, "init")
+	return nil
+}
+
+func (g *hookGenerator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	g.calls = append(g.calls, "generate")
+	return nil
+}
+
+func (g *hookGenerator) Finish(ctx context.Context) error {
+	g.calls = append(g.calls, "finish")
+	return nil
+}
+
+func TestRun_HooksAndMiddleware(t *testing.T) {
+	g := new(hookGenerator)
+
+	var wrapped []string
+	mw := func(name string, next gen.GenerateFunc) gen.GenerateFunc {
+		return func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+			wrapped = append(wrapped, name)
+			return next(ctx, docs, opts)
+		}
+	}
+
+	cmd := &gqlcCmd{
+		cfg: &gqlcConfig{
+			geners: []generator{{Generator: g, name: "hook"}},
+			ipaths: []string{"/home", "/home/graphql/imports"},
+			mws:    []gen.Middleware{mw},
+		},
+	}
+
+	err := cmd.run(testFs, "thr.gql", "four.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := strings.Join(g.calls, ","); calls != "init,generate,generate,finish" {
+		t.Errorf("unexpected calls: %s", calls)
+	}
+	if len(wrapped) != 2 || wrapped[0] != "hook" {
+		t.Errorf("expected middleware to wrap every call, but got: %v", wrapped)
+	}
+}
+
 var testIntroResp = []byte(`{
 	"data": {
 		"__schema": {
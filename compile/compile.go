@@ -0,0 +1,236 @@
+// Package compile provides a programmatic API for the gqlc compiler,
+// which can be used to embed it e.g. in build tooling.
+//
+package compile
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/compiler/spec"
+	"github.com/gqlc/gqlc/operation"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+	"go.uber.org/zap"
+)
+
+// Fetcher fetches remote documents.
+type Fetcher interface {
+	// Fetch fetches the document at the given url.
+	Fetch(ctx context.Context, url *url.URL) (io.ReadCloser, error)
+}
+
+// FetcherFunc is an adapter to allow the use of ordinary functions as Fetchers.
+type FetcherFunc func(ctx context.Context, url *url.URL) (io.ReadCloser, error)
+
+// Fetch calls f(ctx, url).
+func (f FetcherFunc) Fetch(ctx context.Context, url *url.URL) (io.ReadCloser, error) {
+	return f(ctx, url)
+}
+
+// Options configures a compilation.
+type Options struct {
+	// Inputs are the documents to compile. They are either file
+	// paths or URLs of remote documents. Executable documents, i.e.
+	// those containing operations and fragments, are validated
+	// against the compiled schema.
+	//
+	Inputs []string
+
+	// ImportPaths are the directories, which are searched in order
+	// for inputs and imports given by a relative path.
+	//
+	ImportPaths []string
+
+	// Types are documents declaring additional types e.g. the
+	// directives used by plugins. They are only validated and
+	// not included in the Result.
+	//
+	Types []string
+
+	// FS is the file system documents are read from.
+	// It defaults to the OS file system.
+	//
+	FS afero.Fs
+
+	// Fetcher fetches any remote documents. Without
+	// it, remote documents can not be compiled.
+	//
+	Fetcher Fetcher
+
+	// Resolver resolves and opens documents. It defaults to a
+	// FileResolver using the FS, ImportPaths and Fetcher above.
+	//
+	Resolver ImportResolver
+
+	// Prune removes types from the compiled documents.
+	Prune PruneOptions
+
+	// Order is the order of the type declarations in the
+	// compiled documents. It defaults to KindOrder.
+	//
+	Order Order
+}
+
+func (opts *Options) setDefaults() {
+	if opts.FS == nil {
+		opts.FS = afero.NewOsFs()
+	}
+	if len(opts.ImportPaths) == 0 {
+		opts.ImportPaths = []string{"."}
+	}
+	if opts.Order == "" {
+		opts.Order = KindOrder
+	}
+	if opts.Resolver == nil {
+		opts.Resolver = &FileResolver{FS: opts.FS, ImportPaths: opts.ImportPaths, Fetcher: opts.Fetcher}
+	}
+}
+
+// Result is the result of a compilation.
+type Result struct {
+	// DocSet is the token.DocSet the documents were parsed with.
+	DocSet *token.DocSet
+
+	// Documents are the resolved documents i.e. imports are reduced, type
+	// checked and type extensions are merged. They are the same documents
+	// gqlc hands to its generators.
+	//
+	Documents []*ast.Document
+
+	// IDs maps the names of all compiled documents, including
+	// imported ones, to their canonical identities.
+	//
+	IDs map[string]string
+
+	// Sources maps the canonical identities of all compiled documents,
+	// including imported ones, to the documents as they were parsed
+	// e.g. to read the directives of an imported document.
+	//
+	Sources map[string]*ast.Document
+
+	// Operations are the executable documents of the inputs,
+	// which were validated against the Documents.
+	//
+	Operations []*operation.Document
+}
+
+// Errors is a list of type errors.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Compile parses, resolves and type checks the given documents. Type
+// errors and invalid operations are returned as Errors.
+//
+func Compile(ctx context.Context, opts Options) (*Result, error) {
+	opts.setDefaults()
+
+	// Validate any additional types
+	if len(opts.Types) > 0 {
+		zap.S().Info("validating types")
+		_, _, err := compile(newParser(ctx, opts.Resolver), token.NewDocSet(), opts.Types, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p := newParser(ctx, opts.Resolver)
+	dset := token.NewDocSet()
+	docs, srcs, err := compile(p, dset, opts.Inputs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Prune.enabled() {
+		zap.S().Info("pruning types")
+		err = Prune(docs, opts.Prune)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, doc := range docs {
+		doc.Types = sortTypeDecls(doc.Types, opts.Order)
+	}
+
+	ops := p.operations()
+	if len(ops) > 0 {
+		zap.S().Info("validating operations")
+		schema := operation.NewSchema(docs)
+
+		var errs Errors
+		for _, op := range ops {
+			errs = append(errs, operation.Validate(op, schema)...)
+		}
+		if len(errs) > 0 {
+			return nil, errs
+		}
+	}
+
+	ids := make(map[string]string, len(p.docs))
+	for id, doc := range p.docs {
+		ids[doc.Name] = id
+	}
+	return &Result{DocSet: dset, Documents: docs, IDs: ids, Sources: srcs, Operations: ops}, nil
+}
+
+func compile(p *parser, dset *token.DocSet, inputs []string, resolve bool) ([]*ast.Document, map[string]*ast.Document, error) {
+	// Parse files
+	zap.S().Info("parsing input files")
+	err := p.parseInputFiles(dset, inputs...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zap.S().Info("resolving import paths")
+	err = p.resolveImportPaths()
+	if err != nil {
+		return nil, nil, err
+	}
+	srcs := p.sources()
+
+	docsIR := compiler.ToIR(p.documents())
+
+	// Resolve imports (this must occur before type checking)
+	zap.S().Info("reducing imports")
+	docsIR, err = compiler.ReduceImports(docsIR)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Add any missing fields to objects that implement interfaces
+	if resolve {
+		zap.S().Info("implementing interfaces")
+		docsIR = implInterfaces(docsIR)
+	}
+
+	// Perform type checking
+	zap.S().Info("type checking")
+	errs := compiler.CheckTypes(docsIR, spec.Validator, compiler.ImportValidator)
+	if len(errs) > 0 {
+		return nil, nil, Errors(errs)
+	}
+	if !resolve {
+		return nil, nil, nil
+	}
+
+	// Merge type extensions with the original type definitions
+	zap.S().Info("merging type extensions")
+	for d, types := range docsIR {
+		docsIR[d] = compiler.MergeExtensions(types)
+	}
+
+	// Convert types from IR to []*ast.TypeDecl
+	return compiler.FromIR(docsIR), srcs, nil
+}
@@ -0,0 +1,345 @@
+package compile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+	"github.com/spf13/afero"
+)
+
+var (
+	testFs afero.Fs
+	oneGql = `@import(paths: ["two.gql", "/usr/imports/six.gql", "four.gql"])
+
+type Service implements Doc {
+	t: Time
+	obj: Obj
+	v: Version
+}`
+	twoGql = `@import(paths: ["./thr.gql"])
+
+interface Doc {
+	v: Version
+}`
+	thrGql  = `scalar Version`
+	fourGql = `scalar Time`
+	fiveGql = `@import(paths: ["six.gql", "/home/graphql/imports/two.gql"])
+
+type T implements Doc {
+	v: Version
+	obj: Obj
+}`
+	sixGql = `@import(paths: ["/home/graphql/imports/thr.gql"])
+
+type Obj {
+	v: Version
+}`
+)
+
+func TestMain(m *testing.M) {
+	// Set up test fs
+	testFs = afero.NewMemMapFs()
+	testFs.MkdirAll("/home/graphql/imports", 0755)
+	testFs.MkdirAll("/usr/imports", 0755)
+
+	afero.WriteFile(testFs, "/home/graphql/one.gql", []byte(oneGql), 0644)
+	afero.WriteFile(testFs, "/home/graphql/imports/two.gql", []byte(twoGql), 0644)
+	afero.WriteFile(testFs, "/home/graphql/imports/thr.gql", []byte(thrGql), 0644)
+	afero.WriteFile(testFs, "/home/four.gql", []byte(fourGql), 0644)
+	afero.WriteFile(testFs, "/usr/imports/five.gql", []byte(fiveGql), 0644)
+	afero.WriteFile(testFs, "/usr/imports/six.gql", []byte(sixGql), 0644)
+
+	os.Exit(m.Run())
+}
+
+func TestParseInputFiles(t *testing.T) {
+	// Create test cases
+	testCases := []struct {
+		Name        string
+		ImportPaths []string
+		Args        []string
+		Len         int
+	}{
+		{
+			Name: "SingleWithAbs",
+			Len:  1,
+			Args: []string{"/home/graphql/imports/thr.gql"},
+		},
+		{
+			Name:        "SingleWithRel",
+			Len:         1,
+			Args:        []string{"./thr.gql"},
+			ImportPaths: []string{"/home/graphql/imports"},
+		},
+		{
+			Name:        "SingleWithIpath",
+			Len:         1,
+			Args:        []string{"thr.gql"},
+			ImportPaths: []string{"/home/graphql/imports"},
+		},
+		{
+			Name:        "MSingle",
+			Len:         2,
+			Args:        []string{"thr.gql", "four.gql"},
+			ImportPaths: []string{"/home/graphql/imports", "/home"},
+		},
+		{
+			Name:        "TreeIPath",
+			Len:         5,
+			Args:        []string{"one.gql"},
+			ImportPaths: []string{"/home", "/home/graphql", "/home/graphql/imports", "/usr/imports"},
+		},
+		{
+			Name:        "TreeAllArgs",
+			Len:         5,
+			Args:        []string{"/home/graphql/one.gql", "/home/graphql/imports/two.gql", "/home/graphql/imports/thr.gql", "/home/four.gql", "/usr/imports/six.gql"},
+			ImportPaths: []string{"/home/graphql/imports", "/home"},
+		},
+		{
+			Name:        "MTreeIPaths",
+			Len:         6,
+			Args:        []string{"one.gql", "five.gql"},
+			ImportPaths: []string{"/home", "/home/graphql", "/home/graphql/imports", "/usr/imports"},
+		},
+		{
+			Name:        "MTreeAllArgs",
+			Len:         6,
+			Args:        []string{"/home/graphql/one.gql", "/home/graphql/imports/two.gql", "/home/graphql/imports/thr.gql", "/home/four.gql", "/usr/imports/five.gql", "/usr/imports/six.gql"},
+			ImportPaths: []string{"/home/graphql/imports", "/home"},
+		},
+	}
+
+	// Run test cases
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			p := newParser(context.Background(), &FileResolver{FS: testFs, ImportPaths: testCase.ImportPaths})
+
+			err := p.parseInputFiles(token.NewDocSet(), testCase.Args...)
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			err = p.resolveImportPaths()
+			if err != nil {
+				subT.Error(err)
+				return
+			}
+
+			if len(p.docs) != testCase.Len {
+				subT.Fail()
+				return
+			}
+
+			names := make(map[string]bool, len(p.docs))
+			for _, doc := range p.docs {
+				names[doc.Name] = true
+			}
+
+			for _, doc := range p.docs {
+				for _, lit := range getImports(doc) {
+					if !names[strings.Trim(lit.Value, "\"")] {
+						subT.Errorf("unresolved import in %s: %s", doc.Name, lit.Value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	res, err := Compile(context.Background(), Options{
+		Inputs:      []string{"one.gql", "five.gql"},
+		ImportPaths: []string{"/home", "/home/graphql", "/home/graphql/imports", "/usr/imports"},
+		FS:          testFs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Documents) != 2 {
+		t.Fatalf("expected 2 documents, but got: %d", len(res.Documents))
+	}
+	for _, doc := range res.Documents {
+		if doc.Name != "one" && doc.Name != "five" {
+			t.Errorf("unexpected document: %s", doc.Name)
+		}
+	}
+	if res.DocSet == nil {
+		t.Error("expected doc set")
+	}
+
+	if len(res.Sources) != 6 {
+		t.Fatalf("expected 6 sources, but got: %d", len(res.Sources))
+	}
+	for _, name := range []string{"one", "two", "six"} {
+		src := res.Sources[res.IDs[name]]
+		if src == nil || src.Name != name || len(src.Directives) != 1 || src.Directives[0].Name != "import" {
+			t.Errorf("expected source of %s with its own directives, but got: %v", name, src)
+		}
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "bad.gql", []byte(`extend type Bad { a: Int }`), 0644)
+	afero.WriteFile(fs, "types.gql", []byte(`directive @a on FIELD_DEFINITION`), 0644)
+
+	t.Run("TypeErrors", func(subT *testing.T) {
+		_, err := Compile(context.Background(), Options{Inputs: []string{"bad.gql"}, FS: fs})
+		if errs, ok := err.(Errors); !ok || len(errs) == 0 {
+			subT.Errorf("expected type errors, but got: %v", err)
+		}
+	})
+
+	t.Run("InvalidTypes", func(subT *testing.T) {
+		_, err := Compile(context.Background(), Options{Types: []string{"bad.gql"}, FS: fs})
+		if _, ok := err.(Errors); !ok {
+			subT.Errorf("expected type errors, but got: %v", err)
+		}
+	})
+
+	t.Run("ValidTypes", func(subT *testing.T) {
+		res, err := Compile(context.Background(), Options{Types: []string{"types.gql"}, FS: fs})
+		if err != nil {
+			subT.Fatal(err)
+		}
+		if len(res.Documents) != 0 {
+			subT.Errorf("expected types to not be included in the result, but got: %d documents", len(res.Documents))
+		}
+	})
+
+	t.Run("NoFetcher", func(subT *testing.T) {
+		_, err := Compile(context.Background(), Options{Inputs: []string{"http://example.com/schema.gql"}, FS: fs})
+		if err == nil || !strings.Contains(err.Error(), "no fetcher") {
+			subT.Errorf("expected missing fetcher error, but got: %v", err)
+		}
+	})
+}
+
+func TestCompile_Fetcher(t *testing.T) {
+	var fetched string
+	fetcher := FetcherFunc(func(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+		fetched = u.String()
+		return ioutil.NopCloser(strings.NewReader(`scalar Remote`)), nil
+	})
+
+	res, err := Compile(context.Background(), Options{
+		Inputs:  []string{"http://example.com/remote.gql"},
+		FS:      afero.NewMemMapFs(),
+		Fetcher: fetcher,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fetched != "http://example.com/remote.gql" {
+		t.Errorf("unexpected fetched url: %s", fetched)
+	}
+	if len(res.Documents) != 1 || res.Documents[0].Name != "remote" {
+		t.Errorf("unexpected documents: %v", res.Documents)
+	}
+}
+
+func TestCompile_Operations(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "schema.gql", []byte(`type Query {
+	user(id: ID!): User
+}
+
+type User {
+	id: ID!
+	name: String
+}`), 0644)
+	afero.WriteFile(fs, "ops.graphql", []byte(`query GetUser($id: ID!) {
+	user(id: $id) { ...UserFields }
+}
+
+fragment UserFields on User { id name }`), 0644)
+	afero.WriteFile(fs, "bad.graphql", []byte(`{ user(id: 1) { email } }`), 0644)
+	afero.WriteFile(fs, "imports.gql", []byte(`@import(paths: ["ops.graphql"])
+
+scalar Time`), 0644)
+
+	t.Run("Valid", func(subT *testing.T) {
+		res, err := Compile(context.Background(), Options{Inputs: []string{"ops.graphql", "schema.gql"}, FS: fs})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if len(res.Documents) != 1 || res.Documents[0].Name != "schema" {
+			subT.Errorf("unexpected documents: %v", res.Documents)
+		}
+		if len(res.Operations) != 1 || res.Operations[0].Name != "ops" {
+			subT.Fatalf("unexpected operations: %v", res.Operations)
+		}
+		if op := res.Operations[0].Operations[0]; op.Name != "GetUser" {
+			subT.Errorf("unexpected operation: %s", op.Name)
+		}
+	})
+
+	t.Run("Invalid", func(subT *testing.T) {
+		_, err := Compile(context.Background(), Options{Inputs: []string{"schema.gql", "bad.graphql"}, FS: fs})
+		errs, ok := err.(Errors)
+		if !ok || len(errs) != 1 || !strings.Contains(errs[0].Error(), "field email is not defined by User") {
+			subT.Errorf("expected validation errors, but got: %v", err)
+		}
+	})
+
+	t.Run("Imported", func(subT *testing.T) {
+		_, err := Compile(context.Background(), Options{Inputs: []string{"imports.gql"}, FS: fs})
+		if err == nil || !strings.Contains(err.Error(), "imports executable document") {
+			subT.Errorf("expected import error, but got: %v", err)
+		}
+	})
+}
+
+type testGenerator struct{}
+
+func (testGenerator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	w, err := gen.Context(ctx).Open("out/" + doc.Name + ".txt")
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	for _, decl := range doc.Types {
+		fmt.Fprintln(w, decl.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Name.Name)
+	}
+	return nil
+}
+
+func TestResult_Generate(t *testing.T) {
+	res, err := Compile(context.Background(), Options{
+		Inputs:      []string{"thr.gql", "four.gql"},
+		ImportPaths: []string{"/home", "/home/graphql/imports"},
+		FS:          testFs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := res.Generate(context.Background(), "test", testGenerator{}, nil, gen.Header("*.txt", "# header\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, but got: %d", len(files))
+	}
+	if files[0].Name != "out/four.txt" || string(files[0].Content) != "# header\nTime\n" {
+		t.Errorf("unexpected file: %s: %q", files[0].Name, files[0].Content)
+	}
+	if files[1].Name != "out/thr.txt" || string(files[1].Content) != "# header\nVersion\n" {
+		t.Errorf("unexpected file: %s: %q", files[1].Name, files[1].Content)
+	}
+}
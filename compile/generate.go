@@ -0,0 +1,127 @@
+// generate.go runs generators over a Result in memory
+
+package compile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/operation"
+)
+
+// File is a file generated in memory.
+type File struct {
+	// Name is the slash separated file name.
+	Name string
+
+	// Mode contains the permission bits of the file.
+	Mode os.FileMode
+
+	// Content is the content of the file.
+	Content []byte
+}
+
+// Generate runs a generator, wrapped by the given middlewares, over
+// every document of the result, the same way gqlc does, and returns
+// the generated files sorted by name.
+//
+func (r *Result) Generate(ctx context.Context, name string, g gen.Generator, opts map[string]interface{}, mws ...gen.Middleware) ([]*File, error) {
+	gCtx := &memCtx{files: make(map[string]*File)}
+	ctx = gen.WithContext(gen.WithSources(gen.WithDocSet(ctx, r.DocSet), r.Sources), gCtx)
+	ctx = operation.WithDocuments(ctx, r.Operations)
+
+	err := gen.Run(ctx, name, g, r.Documents, opts, mws...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*File, 0, len(gCtx.files))
+	for _, f := range gCtx.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// memCtx is a GeneratorContext which keeps all files in memory.
+type memCtx struct {
+	files map[string]*File
+}
+
+func (ctx *memCtx) Open(name string) (io.WriteCloser, error) {
+	return ctx.OpenFile(name, 0644)
+}
+
+func (ctx *memCtx) OpenFile(name string, perm os.FileMode) (io.WriteCloser, error) {
+	name, err := clean(name)
+	if err != nil {
+		return nil, err
+	}
+	return &memFile{ctx: ctx, name: name, perm: perm}, nil
+}
+
+// Create opens a file, unless it was already generated.
+func (ctx *memCtx) Create(name string) (io.WriteCloser, error) {
+	cname, err := clean(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := ctx.files[cname]; ok {
+		return nil, &os.PathError{Op: "create", Path: cname, Err: os.ErrExist}
+	}
+	return ctx.OpenFile(name, 0644)
+}
+
+func (ctx *memCtx) ReadFile(name string) ([]byte, error) {
+	name, err := clean(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := ctx.files[name]
+	if !ok {
+		return nil, fmt.Errorf("compile: file was not generated: %s", name)
+	}
+	return f.Content, nil
+}
+
+func (ctx *memCtx) Remove(name string) error {
+	name, err := clean(name)
+	if err != nil {
+		return err
+	}
+
+	delete(ctx.files, name)
+	return nil
+}
+
+// clean validates and normalizes a file name.
+func clean(name string) (string, error) {
+	name = path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("compile: invalid file name: %s", name)
+	}
+	return name, nil
+}
+
+// memFile is only added to its memCtx once it is closed.
+type memFile struct {
+	bytes.Buffer
+
+	ctx  *memCtx
+	name string
+	perm os.FileMode
+}
+
+func (f *memFile) Close() error {
+	f.ctx.files[f.name] = &File{Name: f.name, Mode: f.perm, Content: f.Bytes()}
+	return nil
+}
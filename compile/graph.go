@@ -0,0 +1,57 @@
+// graph.go builds the import graph of documents
+
+package compile
+
+import (
+	"context"
+
+	"github.com/gqlc/graphql/token"
+)
+
+// ImportGraph is the import graph of a set of documents.
+type ImportGraph struct {
+	// Documents contains the names of all documents in the order they were parsed.
+	Documents []string `json:"documents"`
+
+	// IDs maps document names to their canonical identities.
+	IDs map[string]string `json:"ids"`
+
+	// Imports maps document names to the names of the documents they import.
+	Imports map[string][]string `json:"imports"`
+}
+
+// Imports parses the given documents and returns their import graph.
+// The documents are not type checked and any Types are ignored.
+//
+func Imports(ctx context.Context, opts Options) (*ImportGraph, error) {
+	opts.setDefaults()
+
+	p := newParser(ctx, opts.Resolver)
+	err := p.parseInputFiles(token.NewDocSet(), opts.Inputs...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.resolveImportPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &ImportGraph{
+		Documents: make([]string, len(p.order)),
+		IDs:       make(map[string]string, len(p.order)),
+		Imports:   make(map[string][]string, len(p.order)),
+	}
+	for i, id := range p.order {
+		name := p.docs[id].Name
+		g.Documents[i] = name
+		g.IDs[name] = id
+
+		imports := make([]string, len(p.graph[id]))
+		for j, iid := range p.graph[id] {
+			imports[j] = p.docs[iid].Name
+		}
+		g.Imports[name] = imports
+	}
+	return g, nil
+}
@@ -1,6 +1,6 @@
 // impl.go adds missings fields to objects the implement interfaces
 
-package cmd
+package compile
 
 import (
 	"github.com/gqlc/compiler"
@@ -0,0 +1,306 @@
+// parse.go parses input files and their imports
+
+package compile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gqlc/gqlc/operation"
+	"github.com/gqlc/graphql/ast"
+	gqlparser "github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+	"go.uber.org/zap"
+)
+
+type parser struct {
+	ctx      context.Context
+	resolver ImportResolver
+
+	// docs maps canonical identities to their documents.
+	docs map[string]*ast.Document
+
+	// order contains the canonical identities in the order they were parsed.
+	order []string
+
+	// imports maps import paths to the canonical identities they were resolved to.
+	imports map[*ast.BasicLit]string
+
+	// graph maps canonical identities to the canonical identities they import.
+	graph map[string][]string
+
+	// stack contains the canonical identities of the documents being parsed.
+	stack []string
+
+	// ops maps canonical identities to executable documents, which
+	// are given as inputs, and opOrder contains them in order.
+	//
+	ops     map[string]*operation.Document
+	opOrder []string
+}
+
+func newParser(ctx context.Context, resolver ImportResolver) *parser {
+	return &parser{
+		ctx:      ctx,
+		resolver: resolver,
+		docs:     make(map[string]*ast.Document),
+		imports:  make(map[*ast.BasicLit]string),
+		graph:    make(map[string][]string),
+		ops:      make(map[string]*operation.Document),
+	}
+}
+
+// parseInputFiles parses all input files, as well as any imported files.
+func (p *parser) parseInputFiles(dset *token.DocSet, filenames ...string) error {
+	for _, filename := range filenames {
+		_, err := p.parseFile(dset, "", filename)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFile resolves, parses and returns the canonical
+// identity of a file, as well as any of its imports.
+//
+func (p *parser) parseFile(dset *token.DocSet, from, filename string) (string, error) {
+	id, err := p.resolver.Resolve(p.ctx, from, filename)
+	if err != nil {
+		return "", err
+	}
+	for i, sid := range p.stack {
+		if sid == id {
+			return "", &ImportCycleError{Cycle: append(p.stack[i:len(p.stack):len(p.stack)], id)}
+		}
+	}
+	if _, exists := p.docs[id]; exists {
+		return id, nil
+	}
+	if _, exists := p.ops[id]; exists {
+		return id, nil
+	}
+
+	zap.L().Info("opening input", zap.String("name", filename), zap.String("id", id))
+	f, err := p.resolver.Open(p.ctx, id)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	src, err := ioutil.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+
+	// Executable documents are only validated against the schema
+	if operation.IsExecutable(src) {
+		if from != "" {
+			return "", fmt.Errorf("compile: %s imports executable document: %s", from, id)
+		}
+
+		op, err := operation.Parse(id, src)
+		if err != nil {
+			return "", err
+		}
+		p.ops[id] = op
+		p.opOrder = append(p.opOrder, id)
+		return id, nil
+	}
+
+	doc, err := gqlparser.ParseDoc(dset, id, bytes.NewReader(src), gqlparser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	p.docs[id] = doc
+	p.order = append(p.order, id)
+	p.graph[id] = nil
+
+	p.stack = append(p.stack, id)
+	defer func() { p.stack = p.stack[:len(p.stack)-1] }()
+
+	for _, lit := range getImports(doc) {
+		iid, err := p.parseFile(dset, id, strings.Trim(lit.Value, "\""))
+		if err != nil {
+			return "", err
+		}
+		p.imports[lit] = iid
+		p.graph[id] = append(p.graph[id], iid)
+	}
+	return id, nil
+}
+
+// ImportCycleError is returned when documents import each other.
+type ImportCycleError struct {
+	// Cycle contains the canonical identities of the documents
+	// in the cycle. The first and last identity are the same.
+	//
+	Cycle []string
+}
+
+func (e *ImportCycleError) Error() string {
+	return fmt.Sprintf("compile: import cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// resolveImportPaths names every document after its canonical identity and
+// rewrites every import path to the name of the document it resolved to.
+//
+func (p *parser) resolveImportPaths() error {
+	names, err := docNames(p.order)
+	if err != nil {
+		return err
+	}
+	for id, name := range names {
+		p.docs[id].Name = name
+	}
+
+	for lit, id := range p.imports {
+		lit.Value = fmt.Sprintf(`"%s"`, p.docs[id].Name)
+	}
+
+	names, err = docNames(p.opOrder)
+	if err != nil {
+		return err
+	}
+	for id, name := range names {
+		p.ops[id].Name = name
+	}
+	return nil
+}
+
+// documents returns the parsed documents in the order they were parsed.
+func (p *parser) documents() []*ast.Document {
+	docs := make([]*ast.Document, len(p.order))
+	for i, id := range p.order {
+		docs[i] = p.docs[id]
+	}
+	return docs
+}
+
+// operations returns the executable documents in the order they were parsed.
+func (p *parser) operations() []*operation.Document {
+	ops := make([]*operation.Document, len(p.opOrder))
+	for i, id := range p.opOrder {
+		ops[i] = p.ops[id]
+	}
+	return ops
+}
+
+// sources returns the parsed documents by their canonical identities. Their
+// directives are copied, since reducing imports merges them.
+//
+func (p *parser) sources() map[string]*ast.Document {
+	srcs := make(map[string]*ast.Document, len(p.docs))
+	for id, doc := range p.docs {
+		srcs[id] = &ast.Document{
+			Name:       doc.Name,
+			Directives: append([]*ast.DirectiveLit(nil), doc.Directives...),
+			Types:      doc.Types,
+			Schema:     doc.Schema,
+		}
+	}
+	return srcs
+}
+
+// docNames names documents by their canonical identities i.e. after their
+// base names without an extension. Documents sharing a base name are named
+// after as many of their trailing path elements as tell them apart, e.g.
+// a/types and b/types.
+//
+func docNames(ids []string) (map[string]string, error) {
+	elems := make(map[string][]string, len(ids))
+	n := make(map[string]int, len(ids))
+	for _, id := range ids {
+		elems[id] = nameElems(id)
+		n[id] = 1
+	}
+
+	for {
+		names := make(map[string]string, len(ids))
+		byName := make(map[string][]string, len(ids))
+		for _, id := range ids {
+			e := elems[id]
+			name := path.Join(e[len(e)-n[id]:]...)
+			names[id] = name
+			byName[name] = append(byName[name], id)
+		}
+
+		grown := false
+		var same []string
+		for _, id := range ids {
+			if len(byName[names[id]]) == 1 {
+				continue
+			}
+			if same == nil {
+				same = byName[names[id]]
+			}
+			if n[id] < len(elems[id]) {
+				n[id]++
+				grown = true
+			}
+		}
+		switch {
+		case same == nil:
+			return names, nil
+		case !grown:
+			return nil, fmt.Errorf("compile: documents %s and %s can not be told apart by name", same[0], same[1])
+		}
+	}
+}
+
+// nameElems returns the path elements of a canonical identity without its
+// extension, where the host of a URL is its first element.
+//
+func nameElems(id string) []string {
+	var host string
+	p := filepath.ToSlash(id)
+	if u, ok := parseURL(id); ok {
+		host, p = u.Host, u.Path
+	}
+	p = p[:len(p)-len(path.Ext(p))]
+
+	var elems []string
+	if host != "" {
+		elems = append(elems, host)
+	}
+	for _, e := range strings.Split(p, "/") {
+		if e != "" {
+			elems = append(elems, e)
+		}
+	}
+	if len(elems) == 0 {
+		elems = append(elems, p)
+	}
+	return elems
+}
+
+func getImports(doc *ast.Document) (lits []*ast.BasicLit) {
+	for _, direc := range doc.Directives {
+		if direc.Name != "import" {
+			continue
+		}
+
+		for _, arg := range direc.Args.Args {
+
+			compLit := arg.Value.(*ast.Arg_CompositeLit).CompositeLit
+			listLit := compLit.Value.(*ast.CompositeLit_ListLit).ListLit.List
+
+			switch v := listLit.(type) {
+			case *ast.ListLit_BasicList:
+				lits = append(lits, v.BasicList.Values...)
+			case *ast.ListLit_CompositeList:
+				for _, c := range v.CompositeList.Values {
+					lits = append(lits, c.Value.(*ast.CompositeLit_BasicLit).BasicLit)
+				}
+			}
+		}
+	}
+
+	return
+}
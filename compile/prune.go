@@ -0,0 +1,390 @@
+// prune.go removes excluded and unreachable types from documents
+
+package compile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/compiler/spec"
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+)
+
+// PruneOptions configures the removal of types from compiled documents.
+// Excluded types are removed from each document, while reachability is
+// walked across all of them, since they may share types e.g. of an
+// imported library document.
+//
+type PruneOptions struct {
+	// Exclude are the names of directives, which mark types, fields,
+	// arguments and enum values for removal e.g. "internal". Any
+	// references to removed types are removed as well, as are the
+	// types left empty by that e.g. an object without fields.
+	//
+	Exclude []string
+
+	// Unreachable removes all types, which are unreachable
+	// from the root operation types of the schemas.
+	//
+	Unreachable bool
+
+	// Keep only keeps the named types and the types reachable from them.
+	Keep []string
+}
+
+func (opts PruneOptions) enabled() bool {
+	return len(opts.Exclude) > 0 || opts.Unreachable || len(opts.Keep) > 0
+}
+
+// Prune removes types from the given documents as configured by opts.
+// Since removing types may break the documents, e.g. an object may no
+// longer implement the fields of its interfaces, they are type checked
+// again, where type errors are returned as Errors.
+//
+func Prune(docs []*ast.Document, opts PruneOptions) error {
+	if len(opts.Exclude) > 0 {
+		for _, doc := range docs {
+			exclude(doc, opts.Exclude)
+		}
+	}
+
+	if opts.Unreachable || len(opts.Keep) > 0 {
+		err := removeUnreachable(docs, opts.Keep)
+		if err != nil {
+			return err
+		}
+	}
+
+	errs := compiler.CheckTypes(compiler.ToIR(docs), spec.Validator)
+	if len(errs) > 0 {
+		return Errors(errs)
+	}
+	return nil
+}
+
+// exclude removes everything marked by one of the given directives, as
+// well as everything referencing removed types. Since that may leave types
+// empty, which are removed in turn, it's repeated until nothing is removed.
+//
+func exclude(doc *ast.Document, directives []string) {
+	marks := make(map[string]bool, len(directives))
+	for _, d := range directives {
+		marks[d] = true
+	}
+	marked := func(dirs []*ast.DirectiveLit) bool {
+		for _, d := range dirs {
+			if marks[d.Name] {
+				return true
+			}
+		}
+		return false
+	}
+
+	removed := make(map[string]bool)
+	keepField := func(f *ast.Field) bool {
+		return !marked(f.Directives) && !removed[graph.BaseName(graph.FieldType(f))]
+	}
+	keepInput := func(iv *ast.InputValue) bool {
+		return !marked(iv.Directives) && !removed[graph.BaseName(graph.InputValueType(iv))]
+	}
+	keepIdent := func(id *ast.Ident) bool { return !removed[id.Name] }
+
+	// The directive declarations are removed along with the types they mark
+	removeDecls := func() {
+		doc.Types = filterDecls(doc.Types, func(ts *ast.TypeSpec) bool {
+			if ts.Name == nil {
+				s, ok := ts.Type.(*ast.TypeSpec_Schema)
+				return !ok || fieldLen(s.Schema.RootOps) > 0
+			}
+
+			_, isDirective := ts.Type.(*ast.TypeSpec_Directive)
+			if removed[ts.Name.Name] || isDirective && marks[ts.Name.Name] || !isDirective && marked(ts.Directives) {
+				removed[ts.Name.Name] = true
+				return false
+			}
+			return true
+		})
+	}
+
+	removeDecls()
+	for emptied := true; emptied; {
+		emptied = false
+		for _, decl := range doc.Types {
+			ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+			if !ok {
+				continue
+			}
+
+			var n, left int
+			switch v := ts.TypeSpec.Type.(type) {
+			case *ast.TypeSpec_Schema:
+				filterFields(v.Schema.RootOps, keepField, keepInput)
+			case *ast.TypeSpec_Object:
+				v.Object.Interfaces = filterIdents(v.Object.Interfaces, keepIdent)
+				n = fieldLen(v.Object.Fields)
+				filterFields(v.Object.Fields, keepField, keepInput)
+				left = fieldLen(v.Object.Fields)
+			case *ast.TypeSpec_Interface:
+				n = fieldLen(v.Interface.Fields)
+				filterFields(v.Interface.Fields, keepField, keepInput)
+				left = fieldLen(v.Interface.Fields)
+			case *ast.TypeSpec_Union:
+				n = len(v.Union.Members)
+				v.Union.Members = filterIdents(v.Union.Members, keepIdent)
+				left = len(v.Union.Members)
+			case *ast.TypeSpec_Enum:
+				n = fieldLen(v.Enum.Values)
+				filterFields(v.Enum.Values, keepField, keepInput)
+				left = fieldLen(v.Enum.Values)
+			case *ast.TypeSpec_Input:
+				n = inputLen(v.Input.Fields)
+				filterInputs(v.Input.Fields, keepInput)
+				left = inputLen(v.Input.Fields)
+			case *ast.TypeSpec_Directive:
+				filterInputs(v.Directive.Args, keepInput)
+			}
+
+			if n > 0 && left == 0 {
+				removed[ts.TypeSpec.Name.Name] = true
+				emptied = true
+			}
+		}
+		removeDecls()
+	}
+	dropSchema(doc)
+}
+
+// removeUnreachable removes all types unreachable from the given types or,
+// if none are given, from the root operation types of the documents.
+//
+func removeUnreachable(docs []*ast.Document, keep []string) error {
+	types := make(map[string][]*ast.TypeSpec)
+	impls := make(map[string][]string)
+	var roots []string
+	var directives []*ast.DirectiveType
+	for _, doc := range docs {
+		docTypes := make(map[string]*ast.TypeSpec)
+		var schema *ast.SchemaType
+		for _, decl := range doc.Types {
+			ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+			if !ok {
+				continue
+			}
+
+			switch v := ts.TypeSpec.Type.(type) {
+			case *ast.TypeSpec_Schema:
+				schema = v.Schema
+				continue
+			case *ast.TypeSpec_Directive:
+				directives = append(directives, v.Directive)
+				continue
+			case *ast.TypeSpec_Object:
+				for _, i := range v.Object.Interfaces {
+					impls[i.Name] = append(impls[i.Name], ts.TypeSpec.Name.Name)
+				}
+			}
+			name := ts.TypeSpec.Name.Name
+			types[name] = append(types[name], ts.TypeSpec)
+			docTypes[name] = ts.TypeSpec
+		}
+		roots = append(roots, rootTypes(schema, docTypes)...)
+	}
+
+	// Collect the roots
+	if len(keep) > 0 {
+		roots = keep
+	}
+	for _, name := range keep {
+		if _, ok := types[name]; !ok {
+			return fmt.Errorf("compile: unknown type to keep: %s", name)
+		}
+	}
+	if len(roots) == 0 {
+		return errors.New("compile: no root operation types to find the reachable types from")
+	}
+
+	// Walk all references, including the implementations of reachable
+	// interfaces, since they may be returned in their place
+	//
+	reachable := make(map[string]bool)
+	var stack []string
+	visit := func(name string) {
+		if _, ok := types[name]; ok && !reachable[name] {
+			reachable[name] = true
+			stack = append(stack, name)
+		}
+	}
+	for _, name := range roots {
+		visit(name)
+	}
+	for _, d := range directives {
+		visitInputs(d.Args, visit)
+	}
+	for len(stack) > 0 {
+		name := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, ts := range types[name] {
+			switch v := ts.Type.(type) {
+			case *ast.TypeSpec_Object:
+				for _, i := range v.Object.Interfaces {
+					visit(i.Name)
+				}
+				visitFields(v.Object.Fields, visit)
+			case *ast.TypeSpec_Interface:
+				visitFields(v.Interface.Fields, visit)
+				for _, impl := range impls[name] {
+					visit(impl)
+				}
+			case *ast.TypeSpec_Union:
+				for _, m := range v.Union.Members {
+					visit(m.Name)
+				}
+			case *ast.TypeSpec_Input:
+				visitInputs(v.Input.Fields, visit)
+			}
+		}
+	}
+
+	// The schema declaration is dropped, once it has no root operations left
+	for _, doc := range docs {
+		doc.Types = filterDecls(doc.Types, func(ts *ast.TypeSpec) bool {
+			switch v := ts.Type.(type) {
+			case *ast.TypeSpec_Schema:
+				filterFields(v.Schema.RootOps, func(f *ast.Field) bool {
+					return reachable[graph.BaseName(graph.FieldType(f))]
+				}, nil)
+				return fieldLen(v.Schema.RootOps) > 0
+			case *ast.TypeSpec_Directive:
+				return true
+			}
+			return reachable[ts.Name.Name]
+		})
+		dropSchema(doc)
+	}
+	return nil
+}
+
+// dropSchema unsets the schema of a document, once its declaration is removed.
+func dropSchema(doc *ast.Document) {
+	if doc.Schema == nil {
+		return
+	}
+
+	for _, decl := range doc.Types {
+		if decl == doc.Schema {
+			return
+		}
+	}
+	doc.Schema = nil
+}
+
+// rootTypes returns the root operation types of a schema. Without a schema
+// declaration, the types named Query, Mutation and Subscription are used.
+//
+func rootTypes(schema *ast.SchemaType, types map[string]*ast.TypeSpec) (roots []string) {
+	if schema != nil && schema.RootOps != nil {
+		for _, f := range schema.RootOps.List {
+			roots = append(roots, graph.BaseName(graph.FieldType(f)))
+		}
+		return
+	}
+
+	for _, name := range []string{"Query", "Mutation", "Subscription"} {
+		if _, ok := types[name]; ok {
+			roots = append(roots, name)
+		}
+	}
+	return
+}
+
+func visitFields(fields *ast.FieldList, visit func(string)) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		visit(graph.BaseName(graph.FieldType(f)))
+		visitInputs(f.Args, visit)
+	}
+}
+
+func visitInputs(args *ast.InputValueList, visit func(string)) {
+	if args == nil {
+		return
+	}
+
+	for _, a := range args.List {
+		visit(graph.BaseName(graph.InputValueType(a)))
+	}
+}
+
+func fieldLen(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+	return len(fields.List)
+}
+
+func inputLen(args *ast.InputValueList) int {
+	if args == nil {
+		return 0
+	}
+	return len(args.List)
+}
+
+func filterDecls(decls []*ast.TypeDecl, keep func(*ast.TypeSpec) bool) []*ast.TypeDecl {
+	kept := decls[:0]
+	for _, decl := range decls {
+		ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || keep(ts.TypeSpec) {
+			kept = append(kept, decl)
+		}
+	}
+	return kept
+}
+
+func filterFields(fields *ast.FieldList, keep func(*ast.Field) bool, keepArg func(*ast.InputValue) bool) {
+	if fields == nil {
+		return
+	}
+
+	kept := fields.List[:0]
+	for _, f := range fields.List {
+		if !keep(f) {
+			continue
+		}
+		if keepArg != nil {
+			filterInputs(f.Args, keepArg)
+		}
+		kept = append(kept, f)
+	}
+	fields.List = kept
+}
+
+func filterInputs(args *ast.InputValueList, keep func(*ast.InputValue) bool) {
+	if args == nil {
+		return
+	}
+
+	kept := args.List[:0]
+	for _, a := range args.List {
+		if keep(a) {
+			kept = append(kept, a)
+		}
+	}
+	args.List = kept
+}
+
+func filterIdents(ids []*ast.Ident, keep func(*ast.Ident) bool) []*ast.Ident {
+	kept := ids[:0]
+	for _, id := range ids {
+		if keep(id) {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
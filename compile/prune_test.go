@@ -0,0 +1,239 @@
+package compile
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/graphql/ast"
+	gqlparser "github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+const pruneGql = `directive @internal on OBJECT | FIELD_DEFINITION | ARGUMENT_DEFINITION | ENUM_VALUE
+
+type Query {
+	user(id: ID!, debug: Boolean @internal): User
+	node: Node
+	admin: Admin @internal
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	role: Role
+	secret: String @internal
+}
+
+type Admin @internal {
+	users: [User]
+}
+
+enum Role {
+	USER
+	ADMIN @internal
+}
+
+type Post implements Node {
+	id: ID!
+}
+
+type Orphan {
+	a: Int
+}
+
+scalar Unused`
+
+func TestPrune(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Opts  PruneOptions
+		Types string
+		Err   string
+	}{
+		{
+			Name:  "None",
+			Types: "Unused,Admin,Orphan,Post,Query,User,Node,Role,internal",
+		},
+		{
+			Name:  "Exclude",
+			Opts:  PruneOptions{Exclude: []string{"internal"}},
+			Types: "Unused,Orphan,Post,Query{user(id),node},User{id,role},Node,Role{USER}",
+		},
+		{
+			Name:  "Unreachable",
+			Opts:  PruneOptions{Unreachable: true},
+			Types: "Admin,Post,Query,User,Node,Role,internal",
+		},
+		{
+			Name:  "ExcludeUnreachable",
+			Opts:  PruneOptions{Exclude: []string{"internal"}, Unreachable: true},
+			Types: "Post,Query{user(id),node},User{id,role},Node,Role{USER}",
+		},
+		{
+			Name:  "Keep",
+			Opts:  PruneOptions{Keep: []string{"Admin"}},
+			Types: "Admin,Post,User,Node,Role,internal",
+		},
+		{
+			Name: "KeepUnknown",
+			Opts: PruneOptions{Keep: []string{"Unknown"}},
+			Err:  "compile: unknown type to keep: Unknown",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			res, err := Compile(context.Background(), Options{
+				Inputs:   []string{"api.gql"},
+				Resolver: mapResolver{"mem:api": pruneGql},
+				Prune:    testCase.Opts,
+			})
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Fatalf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			types := typeNames(res.Documents[0], testCase.Opts.Exclude != nil)
+			if types != testCase.Types {
+				subT.Errorf("expected types: %s, but got: %s", testCase.Types, types)
+			}
+		})
+	}
+}
+
+func TestPrune_Cascade(t *testing.T) {
+	res, err := Compile(context.Background(), Options{
+		Inputs: []string{"api.gql"},
+		Resolver: mapResolver{"mem:api": `directive @internal on OBJECT | FIELD_DEFINITION | ENUM_VALUE
+
+type Query {
+	user: User
+	secrets: Secrets
+	result: Result
+	level: Level
+}
+
+type User {
+	id: ID!
+}
+
+type Audit @internal {
+	id: ID!
+}
+
+type Secrets {
+	audit: Audit
+	log: [Audit!]!
+}
+
+union Result = Secrets
+
+enum Level {
+	DEBUG @internal
+	TRACE @internal
+}`},
+		Prune: PruneOptions{Exclude: []string{"internal"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if types := typeNames(res.Documents[0], true); types != "Query{user},User{id}" {
+		t.Errorf("expected types: Query{user},User{id}, but got: %s", types)
+	}
+}
+
+func TestPrune_Revalidate(t *testing.T) {
+	_, err := Compile(context.Background(), Options{
+		Inputs: []string{"api.gql"},
+		Resolver: mapResolver{"mem:api": `directive @internal on FIELD_DEFINITION
+
+type Query {
+	node: Node
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID! @internal
+	name: String
+}`},
+		Prune: PruneOptions{Exclude: []string{"internal"}},
+	})
+	if _, ok := err.(Errors); !ok {
+		t.Errorf("expected type errors, but got: %v", err)
+	}
+}
+
+func TestPrune_Documents(t *testing.T) {
+	dset := token.NewDocSet()
+	api, err := gqlparser.ParseDoc(dset, "api", strings.NewReader(`type Query { user: User }
+type User { id: ID! }`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lib, err := gqlparser.ParseDoc(dset, "lib", strings.NewReader(`type User { id: ID! }
+scalar Unused`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Prune([]*ast.Document{api, lib}, PruneOptions{Unreachable: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types := typeNames(lib, false); types != "User" {
+		t.Errorf("expected the types of lib: User, but got: %s", types)
+	}
+
+	err = Prune([]*ast.Document{lib}, PruneOptions{Unreachable: true})
+	if err == nil || err.Error() != "compile: no root operation types to find the reachable types from" {
+		t.Errorf("expected no root operation types error, but got: %v", err)
+	}
+}
+
+// typeNames lists the names of the declared types and,
+// if fields is set, the fields of pruned types.
+//
+func typeNames(doc *ast.Document, fields bool) string {
+	var names []string
+	for _, decl := range doc.Types {
+		ts := decl.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		name := ts.Name.Name
+
+		var list *ast.FieldList
+		switch v := ts.Type.(type) {
+		case *ast.TypeSpec_Object:
+			list = v.Object.Fields
+		case *ast.TypeSpec_Enum:
+			list = v.Enum.Values
+		}
+		if fields && list != nil && (name == "Query" || name == "User" || name == "Role") {
+			fs := make([]string, len(list.List))
+			for i, f := range list.List {
+				fs[i] = f.Name.Name
+				if f.Args != nil && len(f.Args.List) > 0 {
+					args := make([]string, len(f.Args.List))
+					for j, a := range f.Args.List {
+						args[j] = a.Name.Name
+					}
+					fs[i] += "(" + strings.Join(args, ",") + ")"
+				}
+			}
+			name += "{" + strings.Join(fs, ",") + "}"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,157 @@
+// resolve.go contains the resolution of documents to their canonical identities
+
+package compile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ImportResolver resolves documents and their imports to canonical
+// identities, e.g. full file paths, module paths or URLs, and opens them.
+// A document is only ever parsed once per identity.
+//
+type ImportResolver interface {
+	// Resolve returns the canonical identity of the document at path,
+	// as imported by the document with the identity from. from is
+	// empty for the inputs of a compilation.
+	//
+	Resolve(ctx context.Context, from, path string) (string, error)
+
+	// Open opens the document with the given canonical identity.
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+}
+
+// AmbiguousImportError is returned when an import matches more than one document.
+type AmbiguousImportError struct {
+	// From is the identity of the importing document.
+	From string
+
+	// Path is the imported path.
+	Path string
+
+	// Candidates are the identities of the matching documents.
+	Candidates []string
+}
+
+func (e *AmbiguousImportError) Error() string {
+	from := e.From
+	if from == "" {
+		from = "inputs"
+	}
+	return fmt.Sprintf("compile: ambiguous import %q in %s: matches %s", e.Path, from, strings.Join(e.Candidates, ", "))
+}
+
+// FileResolver is the default ImportResolver. It resolves documents
+// to cleaned file paths and remote documents to their URLs.
+//
+// A relative path is first resolved relative to the importing
+// document and then searched for in the import paths, in order,
+// where the first match wins. A bare path, i.e. one without an
+// extension, matches a .gql or .graphql document, so it is an
+// error if both of them exist.
+//
+type FileResolver struct {
+	// FS is the file system documents are read from.
+	FS afero.Fs
+
+	// ImportPaths are the directories relative paths are searched in.
+	ImportPaths []string
+
+	// Fetcher fetches remote documents.
+	Fetcher Fetcher
+}
+
+// Resolve resolves path to a cleaned file path or URL.
+func (r *FileResolver) Resolve(ctx context.Context, from, path string) (string, error) {
+	if u, ok := parseURL(path); ok {
+		return u.String(), nil
+	}
+	if u, ok := parseURL(from); ok {
+		ref, err := url.Parse(filepath.ToSlash(path))
+		if err != nil {
+			return "", err
+		}
+		return u.ResolveReference(ref).String(), nil
+	}
+
+	if filepath.IsAbs(path) {
+		fname, err := r.lookup(from, path, filepath.Clean(path))
+		if err != nil || fname != "" {
+			return fname, err
+		}
+		return filepath.Clean(path), nil
+	}
+
+	if from != "" {
+		fname, err := r.lookup(from, path, filepath.Join(filepath.Dir(from), path))
+		if err != nil || fname != "" {
+			return fname, err
+		}
+	}
+
+	for _, iPath := range r.ImportPaths {
+		fname, err := r.lookup(from, path, filepath.Join(iPath, path))
+		if err != nil || fname != "" {
+			return fname, err
+		}
+	}
+	return "", fmt.Errorf("could not resolve file path: %s", path)
+}
+
+// lookup returns the existing file the file name of an import refers to
+// or an empty string, if there's none.
+//
+func (r *FileResolver) lookup(from, path, fname string) (string, error) {
+	candidates := []string{fname}
+	if filepath.Ext(fname) == "" {
+		candidates = []string{fname + ".gql", fname + ".graphql"}
+	}
+
+	var found []string
+	for _, c := range candidates {
+		exists, err := afero.Exists(r.FS, c)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			found = append(found, c)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", nil
+	case 1:
+		return found[0], nil
+	default:
+		return "", &AmbiguousImportError{From: from, Path: path, Candidates: found}
+	}
+}
+
+// Open opens a file or fetches a remote document.
+func (r *FileResolver) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	if u, ok := parseURL(id); ok {
+		if r.Fetcher == nil {
+			return nil, fmt.Errorf("compile: no fetcher configured for remote document: %s", id)
+		}
+		return r.Fetcher.Fetch(ctx, u)
+	}
+
+	return r.FS.Open(id)
+}
+
+// parseURL parses s as the URL of a remote document.
+func parseURL(s string) (*url.URL, bool) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Opaque != "" {
+		return nil, false
+	}
+	return u, true
+}
@@ -0,0 +1,225 @@
+package compile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestFileResolver_Resolve(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/a/types.gql", []byte(""), 0644)
+	afero.WriteFile(fs, "/b/types.gql", []byte(""), 0644)
+	afero.WriteFile(fs, "/b/only.gql", []byte(""), 0644)
+	afero.WriteFile(fs, "/b/both.gql", []byte(""), 0644)
+	afero.WriteFile(fs, "/b/both.graphql", []byte(""), 0644)
+
+	r := &FileResolver{FS: fs, ImportPaths: []string{"/a", "/b"}}
+
+	testCases := []struct {
+		Name string
+		From string
+		Path string
+		ID   string
+		Err  string
+	}{
+		{
+			Name: "Abs",
+			Path: "/a/../b/only.gql",
+			ID:   "/b/only.gql",
+		},
+		{
+			Name: "ImportPath",
+			Path: "only.gql",
+			ID:   "/b/only.gql",
+		},
+		{
+			Name: "RelativeToImporter",
+			From: "/b/only.gql",
+			Path: "./types.gql",
+			ID:   "/b/types.gql",
+		},
+		{
+			Name: "FirstImportPath",
+			Path: "types.gql",
+			ID:   "/a/types.gql",
+		},
+		{
+			Name: "Bare",
+			Path: "only",
+			ID:   "/b/only.gql",
+		},
+		{
+			Name: "Ambiguous",
+			Path: "both",
+			Err:  `compile: ambiguous import "both" in inputs: matches /b/both.gql, /b/both.graphql`,
+		},
+		{
+			Name: "Unknown",
+			Path: "unknown.gql",
+			Err:  "could not resolve file path: unknown.gql",
+		},
+		{
+			Name: "URL",
+			Path: "https://example.com/schema/api.gql",
+			ID:   "https://example.com/schema/api.gql",
+		},
+		{
+			Name: "RelativeToURL",
+			From: "https://example.com/schema/api.gql",
+			Path: "../types.gql",
+			ID:   "https://example.com/types.gql",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			id, err := r.Resolve(context.Background(), testCase.From, testCase.Path)
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Fatalf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if id != testCase.ID {
+				subT.Errorf("expected id: %s, but got: %s", testCase.ID, id)
+			}
+		})
+	}
+}
+
+// mapResolver resolves documents from memory by their name.
+type mapResolver map[string]string
+
+func (r mapResolver) Resolve(ctx context.Context, from, path string) (string, error) {
+	id := "mem:" + strings.TrimSuffix(path, ".gql")
+	if _, ok := r[id]; !ok {
+		return "", fmt.Errorf("unknown document: %s", path)
+	}
+	return id, nil
+}
+
+func (r mapResolver) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader(r[id])), nil
+}
+
+func TestCompile_Resolver(t *testing.T) {
+	r := mapResolver{
+		"mem:api":   `@import(paths: ["types.gql"]) type Query { t: T }`,
+		"mem:types": `type T { a: Int }`,
+	}
+
+	res, err := Compile(context.Background(), Options{Inputs: []string{"api.gql"}, Resolver: r})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Documents) != 1 || res.Documents[0].Name != "mem:api" {
+		t.Fatalf("unexpected documents: %v", res.Documents)
+	}
+	if res.IDs["mem:types"] != "mem:types" {
+		t.Errorf("unexpected ids: %v", res.IDs)
+	}
+}
+
+func TestCompile_SameBaseName(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/api.gql", []byte(`@import(paths: ["a/types.gql", "b/types.gql"]) type Query { a: A, b: B }`), 0644)
+	afero.WriteFile(fs, "/x/a/types.gql", []byte(`scalar A`), 0644)
+	afero.WriteFile(fs, "/x/b/types.gql", []byte(`scalar B`), 0644)
+	afero.WriteFile(fs, "/x/b/api.gql", []byte(`scalar C`), 0644)
+
+	res, err := Compile(context.Background(), Options{
+		Inputs:      []string{"/api.gql", "/x/a/types.gql", "/x/b/types.gql", "/x/b/api.gql"},
+		ImportPaths: []string{"/x"},
+		FS:          fs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, doc := range res.Documents {
+		names = append(names, doc.Name)
+	}
+	sort.Strings(names)
+	if ex := "api b/api"; strings.Join(names, " ") != ex {
+		t.Errorf("expected documents: %s, but got: %v", ex, names)
+	}
+	if res.IDs["api"] != "/api.gql" || res.IDs["a/types"] != "/x/a/types.gql" || res.IDs["b/types"] != "/x/b/types.gql" {
+		t.Errorf("unexpected ids: %v", res.IDs)
+	}
+}
+
+func TestDocNames(t *testing.T) {
+	names, err := docNames([]string{"/x/a/types.gql", "/y/a/types.gql", "/x/b/types.gql", "https://example.com/types.gql", "/api.gql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := map[string]string{
+		"/x/a/types.gql":                "x/a/types",
+		"/y/a/types.gql":                "y/a/types",
+		"/x/b/types.gql":                "b/types",
+		"https://example.com/types.gql": "example.com/types",
+		"/api.gql":                      "api",
+	}
+	for id, name := range ex {
+		if names[id] != name {
+			t.Errorf("expected %s to be named %s, but got: %s", id, name, names[id])
+		}
+	}
+
+	_, err = docNames([]string{"/a/types.gql", "/a/types.graphql"})
+	if err == nil || err.Error() != "compile: documents /a/types.gql and /a/types.graphql can not be told apart by name" {
+		t.Errorf("expected indistinguishable documents error, but got: %v", err)
+	}
+}
+
+func TestCompile_ImportCycle(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	afero.WriteFile(fs, "/a.gql", []byte(`@import(paths: ["b.gql"]) scalar A`), 0644)
+	afero.WriteFile(fs, "/b.gql", []byte(`@import(paths: ["c.gql"]) scalar B`), 0644)
+	afero.WriteFile(fs, "/c.gql", []byte(`@import(paths: ["b.gql"]) scalar C`), 0644)
+
+	_, err := Compile(context.Background(), Options{Inputs: []string{"a.gql"}, ImportPaths: []string{"/"}, FS: fs})
+
+	cerr, ok := err.(*ImportCycleError)
+	if !ok {
+		t.Fatalf("expected import cycle error, but got: %v", err)
+	}
+	if cerr.Error() != "compile: import cycle: /b.gql -> /c.gql -> /b.gql" {
+		t.Errorf("unexpected error: %s", cerr)
+	}
+}
+
+func TestImports(t *testing.T) {
+	g, err := Imports(context.Background(), Options{
+		Inputs:      []string{"one.gql"},
+		ImportPaths: []string{"/home", "/home/graphql", "/home/graphql/imports", "/usr/imports"},
+		FS:          testFs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(g.Documents, ",") != "one,two,thr,six,four" {
+		t.Errorf("unexpected documents: %v", g.Documents)
+	}
+	if strings.Join(g.Imports["one"], ",") != "two,six,four" {
+		t.Errorf("unexpected imports: %v", g.Imports["one"])
+	}
+	if g.IDs["six"] != "/usr/imports/six.gql" {
+		t.Errorf("unexpected id: %s", g.IDs["six"])
+	}
+}
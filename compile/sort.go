@@ -0,0 +1,203 @@
+// sort.go sorts type decls by type and name
+
+package compile
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// Order is the order of the type declarations in compiled documents.
+type Order string
+
+// Supported orders of type declarations.
+const (
+	// SourceOrder keeps the order the types are declared in.
+	SourceOrder Order = "source"
+
+	// KindOrder sorts types by their kind and then by their name.
+	KindOrder Order = "kind"
+
+	// AlphaOrder sorts types by their name.
+	AlphaOrder Order = "alpha"
+
+	// TopologicalOrder declares types before the types referencing them.
+	// Types referencing each other are kept in source order.
+	//
+	TopologicalOrder Order = "topological"
+)
+
+// ParseOrder parses the name of an Order.
+func ParseOrder(s string) (Order, error) {
+	switch o := Order(s); o {
+	case SourceOrder, KindOrder, AlphaOrder, TopologicalOrder:
+		return o, nil
+	default:
+		return "", fmt.Errorf("compile: unknown order: %s", s)
+	}
+}
+
+// sortTypeDecls sorts type declarations by the given order.
+func sortTypeDecls(decls []*ast.TypeDecl, order Order) []*ast.TypeDecl {
+	// Source order is the base for all others
+	sort.SliceStable(decls, func(i, j int) bool { return decls[i].TokPos < decls[j].TokPos })
+
+	switch order {
+	case KindOrder:
+		sort.Stable(typeSlice(decls))
+	case AlphaOrder:
+		sort.SliceStable(decls, func(i, j int) bool {
+			its, iok := decls[i].Spec.(*ast.TypeDecl_TypeSpec)
+			jts, jok := decls[j].Spec.(*ast.TypeDecl_TypeSpec)
+			if !iok || !jok {
+				return false
+			}
+			return declName(its.TypeSpec) < declName(jts.TypeSpec)
+		})
+	case TopologicalOrder:
+		decls = sortTopological(decls)
+	}
+	return decls
+}
+
+// declName returns the name of a type, where the
+// schema, which has none, precedes all others.
+//
+func declName(ts *ast.TypeSpec) string {
+	if ts.Name == nil {
+		return ""
+	}
+	return ts.Name.Name
+}
+
+// sortTopological sorts type declarations, which are in source order,
+// such that types are declared before the types referencing them.
+//
+func sortTopological(decls []*ast.TypeDecl) []*ast.TypeDecl {
+	byName := make(map[string]*ast.TypeDecl, len(decls))
+	for _, decl := range decls {
+		if ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec); ok && ts.TypeSpec.Name != nil {
+			byName[ts.TypeSpec.Name.Name] = decl
+		}
+	}
+
+	sorted := make([]*ast.TypeDecl, 0, len(decls))
+	visited := make(map[*ast.TypeDecl]bool, len(decls))
+	var visit func(decl *ast.TypeDecl)
+	visit = func(decl *ast.TypeDecl) {
+		if visited[decl] {
+			return
+		}
+		visited[decl] = true
+
+		if ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec); ok {
+			for _, name := range references(ts.TypeSpec) {
+				if dep, ok := byName[name]; ok {
+					visit(dep)
+				}
+			}
+		}
+		sorted = append(sorted, decl)
+	}
+	for _, decl := range decls {
+		visit(decl)
+	}
+	return sorted
+}
+
+// references returns the names of all types referenced by a type.
+func references(ts *ast.TypeSpec) (names []string) {
+	visit := func(name string) { names = append(names, name) }
+	switch v := ts.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		visitFields(v.Schema.RootOps, visit)
+	case *ast.TypeSpec_Object:
+		for _, i := range v.Object.Interfaces {
+			visit(i.Name)
+		}
+		visitFields(v.Object.Fields, visit)
+	case *ast.TypeSpec_Interface:
+		visitFields(v.Interface.Fields, visit)
+	case *ast.TypeSpec_Union:
+		for _, m := range v.Union.Members {
+			visit(m.Name)
+		}
+	case *ast.TypeSpec_Input:
+		visitInputs(v.Input.Fields, visit)
+	case *ast.TypeSpec_Directive:
+		visitInputs(v.Directive.Args, visit)
+	}
+	return
+}
+
+// declType defines the order of types in the .md file
+type declType uint16
+
+// Top-Level type declarations in GraphQL IDL.
+const (
+	schemaType declType = 1 << iota
+	scalarType
+	objectType
+	interType
+	unionType
+	enumType
+	inputType
+	directiveType
+	extendType
+)
+
+// typeSlice represents a list of GraphQL type declarations
+type typeSlice []*ast.TypeDecl
+
+func (s typeSlice) Len() int { return len(s) }
+func (s typeSlice) Less(i, j int) bool {
+	it, jt := s[i], s[j]
+
+	var its, jts *ast.TypeSpec
+	itd, iok := it.Spec.(*ast.TypeDecl_TypeSpec)
+	jtd, jok := jt.Spec.(*ast.TypeDecl_TypeSpec)
+
+	if iok != jok { // TypeSpec < TypeExt
+		return !iok && jok
+	}
+
+	its, jts = itd.TypeSpec, jtd.TypeSpec
+	if !iok && !jok {
+		its, jts = it.Spec.(*ast.TypeDecl_TypeExtSpec).TypeExtSpec.Type, jt.Spec.(*ast.TypeDecl_TypeExtSpec).TypeExtSpec.Type
+	}
+
+	// Schema < Scalar < Object < Interface < Union < Enum < Input < Directive
+	iType, jType := getDeclType(its.Type), getDeclType(jts.Type)
+
+	if iType != jType {
+		return iType < jType
+	}
+
+	return its.Name.Name < jts.Name.Name
+}
+
+func getDeclType(ts interface{}) (iType declType) {
+	switch ts.(type) {
+	case *ast.TypeSpec_Schema:
+		iType = schemaType
+	case *ast.TypeSpec_Scalar:
+		iType = scalarType
+	case *ast.TypeSpec_Object:
+		iType = objectType
+	case *ast.TypeSpec_Interface:
+		iType = interType
+	case *ast.TypeSpec_Union:
+		iType = unionType
+	case *ast.TypeSpec_Enum:
+		iType = enumType
+	case *ast.TypeSpec_Input:
+		iType = inputType
+	case *ast.TypeSpec_Directive:
+		iType = directiveType
+	}
+	return
+}
+
+func (s typeSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
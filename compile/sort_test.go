@@ -1,7 +1,8 @@
-package cmd
+package compile
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -188,7 +189,7 @@ func TestSortTypeDecls(t *testing.T) {
 				types = append(types, &ast.TypeDecl{Spec: &ast.TypeDecl_TypeSpec{TypeSpec: spec}})
 			}
 
-			stypes := sortTypeDecls(types)
+			stypes := sortTypeDecls(types, KindOrder)
 
 			for i, st := range stypes {
 				cmpSpec := st.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
@@ -212,3 +213,45 @@ func TestSortTypeDecls(t *testing.T) {
 		})
 	}
 }
+
+func TestCompile_Order(t *testing.T) {
+	testCases := []struct {
+		Order Order
+		Types string
+	}{
+		{Order: SourceOrder, Types: "T,Obj,Version,Doc"},
+		{Order: KindOrder, Types: "Version,Obj,T,Doc"},
+		{Order: AlphaOrder, Types: "Doc,Obj,T,Version"},
+		{Order: TopologicalOrder, Types: "Version,Doc,Obj,T"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.Order), func(subT *testing.T) {
+			res, err := Compile(context.Background(), Options{
+				Inputs:      []string{"five.gql"},
+				ImportPaths: []string{"/usr/imports", "/home/graphql/imports"},
+				FS:          testFs,
+				Order:       testCase.Order,
+			})
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if types := typeNames(res.Documents[0], false); types != testCase.Types {
+				subT.Errorf("expected types: %s, but got: %s", testCase.Types, types)
+			}
+		})
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	o, err := ParseOrder("topological")
+	if err != nil || o != TopologicalOrder {
+		t.Errorf("unexpected order: %s, %v", o, err)
+	}
+
+	_, err = ParseOrder("random")
+	if err == nil {
+		t.Error("expected error for unknown order")
+	}
+}
@@ -8,9 +8,11 @@ import (
 
 	"io"
 	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/graph"
 	"github.com/gqlc/gqlc/types"
 	"github.com/gqlc/graphql/ast"
 	"github.com/yuin/goldmark"
@@ -22,6 +24,9 @@ type Options struct {
 	Title string
 	HTML  bool
 
+	// Diagram embeds a Mermaid class diagram of the types.
+	Diagram bool
+
 	toc *[]string
 }
 
@@ -79,6 +84,10 @@ func (o *Options) addContent(name string, typ, mask declType) declType {
 }
 
 // Generator generates CommonMark documentation for GraphQL Documents.
+// Its sections always group the types by kind, so the order of the
+// types, e.g. given by the --order flag of gqlc, only orders the
+// types within each section.
+//
 type Generator struct {
 	sync.Mutex
 	bytes.Buffer
@@ -127,7 +136,7 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 
 	// Generate types
 	g.log.Info("generating types")
-	g.generateTypes(doc.Types, gOpts)
+	g.generateTypes(groupTypes(doc.Types), gOpts)
 
 	// Extract generator context
 	gCtx := gen.Context(ctx)
@@ -146,6 +155,20 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		return
 	}
 
+	// Write type diagram
+	var diagram bytes.Buffer
+	if gOpts.Diagram {
+		err = writeDiagram(&diagram, doc)
+		if err != nil {
+			return
+		}
+
+		_, err = docFile.Write(diagram.Bytes())
+		if err != nil {
+			return
+		}
+	}
+
 	// Write markdown
 	b := g.Bytes()
 	_, err = docFile.Write(b)
@@ -164,12 +187,72 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	}
 	defer htmlFile.Close()
 
-	err = goldmark.Convert(b, htmlFile)
+	// The diagram is rendered by Mermaid, so its script is only included along with it
+	if !gOpts.Diagram {
+		err = goldmark.Convert(b, htmlFile)
+		return
+	}
+
+	diagram.Write(b)
+	err = goldmark.Convert(diagram.Bytes(), htmlFile)
+	if err != nil {
+		return
+	}
+
+	_, err = io.WriteString(htmlFile, mermaidScript)
 	return
 }
 
+// mermaidScript renders the diagram of an HTML document, whose
+// mermaid code block goldmark renders as code.language-mermaid.
+//
+const mermaidScript = `<script type="module">
+import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.esm.min.mjs";
+mermaid.run({ querySelector: "code.language-mermaid" });
+</script>
+`
+
 func noopGen(*ast.TypeSpec) {}
 
+// groupTypes groups types into their sections, while keeping
+// the order of the types within each section as given.
+//
+func groupTypes(decls []*ast.TypeDecl) []*ast.TypeDecl {
+	types := make([]*ast.TypeDecl, len(decls))
+	copy(types, decls)
+
+	sort.SliceStable(types, func(i, j int) bool {
+		return getDeclType(types[i]) < getDeclType(types[j])
+	})
+	return types
+}
+
+func getDeclType(decl *ast.TypeDecl) declType {
+	ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+	if !ok {
+		return extendType
+	}
+
+	switch ts.TypeSpec.Type.(type) {
+	case *ast.TypeSpec_Schema:
+		return schemaType
+	case *ast.TypeSpec_Scalar:
+		return scalarType
+	case *ast.TypeSpec_Object:
+		return objectType
+	case *ast.TypeSpec_Interface:
+		return interType
+	case *ast.TypeSpec_Union:
+		return unionType
+	case *ast.TypeSpec_Enum:
+		return enumType
+	case *ast.TypeSpec_Input:
+		return inputType
+	default:
+		return directiveType
+	}
+}
+
 func (g *Generator) generateTypes(types []*ast.TypeDecl, opts *Options) {
 	var fieldsBuf bytes.Buffer
 	var typ declType
@@ -429,6 +512,19 @@ func writeToC(w io.Writer, opts *Options) (int64, error) {
 	return b.WriteTo(w)
 }
 
+func writeDiagram(w io.Writer, doc *ast.Document) error {
+	var b bytes.Buffer
+	b.WriteString("## Diagram\n\n```mermaid\n")
+	err := graph.Types(doc).WriteMermaid(&b)
+	if err != nil {
+		return err
+	}
+	b.WriteString("```\n\n")
+
+	_, err = b.WriteTo(w)
+	return err
+}
+
 func writeContentLink(b *bytes.Buffer, name, link []byte, addS bool) {
 	b.Write([]byte("- ["))
 	b.Write(name)
@@ -849,6 +945,11 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 				if v == "true" {
 					gOpts.HTML = true
 				}
+			case "diagram":
+				v := arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				if v == "true" {
+					gOpts.Diagram = true
+				}
 			}
 		}
 	}
@@ -868,5 +969,8 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 	if h, ok := opts["html"]; ok {
 		gOpts.HTML, _ = h.(bool)
 	}
+	if d, ok := opts["diagram"]; ok {
+		gOpts.Diagram, _ = d.(bool)
+	}
 	return
 }
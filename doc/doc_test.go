@@ -513,6 +513,23 @@ func (ctx *testCtx) Open(name string) (io.WriteCloser, error) {
 	return &noopCloser{ctx.html}, nil
 }
 
+func TestGroupTypes(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`type B { a: A }
+scalar Time
+type A { t: Time }`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, decl := range groupTypes(doc.Types) {
+		names = append(names, decl.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Name.Name)
+	}
+	if strings.Join(names, ",") != "Time,B,A" {
+		t.Errorf("expected types grouped by section in given order, but got: %v", names)
+	}
+}
+
 func TestGenerator_Generate(t *testing.T) {
 	t.Run("Markdown", func(subT *testing.T) {
 		var b bytes.Buffer
@@ -553,6 +570,75 @@ func TestGenerator_Generate(t *testing.T) {
 
 		gen.CompareBytes(subT, ex, b.Bytes())
 	})
+
+	t.Run("WithDiagram", func(subT *testing.T) {
+		var b bytes.Buffer
+		g := new(Generator)
+		ctx := gen.WithContext(context.Background(), &testCtx{md: &b})
+		err := g.Generate(ctx, testDoc, map[string]interface{}{"diagram": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !bytes.Contains(b.Bytes(), []byte("## Diagram\n\n```mermaid\nclassDiagram\n")) {
+			subT.Errorf("expected mermaid diagram, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("WithHTMLDiagram", func(subT *testing.T) {
+		var b bytes.Buffer
+		g := new(Generator)
+		ctx := gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), html: &b})
+		err := g.Generate(ctx, testDoc, map[string]interface{}{"html": true, "diagram": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !bytes.Contains(b.Bytes(), []byte("<h2>Diagram</h2>\n<pre><code class=\"language-mermaid\">classDiagram\n")) {
+			subT.Errorf("expected mermaid diagram, but got:\n%s", b.String())
+		}
+		if !bytes.HasSuffix(b.Bytes(), []byte(mermaidScript)) {
+			subT.Errorf("expected mermaid script, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("WithoutDiagram", func(subT *testing.T) {
+		var b bytes.Buffer
+		g := new(Generator)
+		ctx := gen.WithContext(context.Background(), &testCtx{md: new(bytes.Buffer), html: &b})
+		err := g.Generate(ctx, testDoc, map[string]interface{}{"html": true})
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if bytes.Contains(b.Bytes(), []byte("mermaid")) {
+			subT.Errorf("expected no mermaid script without a diagram, but got:\n%s", b.String())
+		}
+	})
+
+	t.Run("DiagramOption", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`@doc(options: {diagram: true})
+type A { a: Int }`), 0)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		var b bytes.Buffer
+		g := new(Generator)
+		ctx := gen.WithContext(context.Background(), &testCtx{md: &b})
+		err = g.Generate(ctx, doc, nil)
+		if err != nil {
+			subT.Error(err)
+			return
+		}
+
+		if !bytes.Contains(b.Bytes(), []byte("```mermaid\nclassDiagram\n")) {
+			subT.Errorf("expected mermaid diagram, but got:\n%s", b.String())
+		}
+	})
 }
 
 func BenchmarkGenerator_Generate(b *testing.B) {
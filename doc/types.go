@@ -57,6 +57,16 @@ var docTypes = []*ast.TypeDecl{
 								Value: "false",
 							}},
 						},
+						{
+							Name: &ast.Ident{Name: "diagram"},
+							Type: &ast.InputValue_Ident{
+								Ident: &ast.Ident{Name: "Boolean"},
+							},
+							Default: &ast.InputValue_BasicLit{BasicLit: &ast.BasicLit{
+								Kind:  token.Token_BOOL,
+								Value: "false",
+							}},
+						},
 					},
 				},
 			}},
@@ -1,7 +1,7 @@
 // Package gen contains and utils for working with generators.
 package gen
 
-//go:generate mockgen -write_package_comment=false -package=gen -destination=./mock.go github.com/gqlc/gqlc/gen Generator
+//go:generate mockgen -write_package_comment=false -package=gen -destination=./mock.go github.com/gqlc/gqlc/gen Generator,DocsGenerator
 
 import (
 	"context"
@@ -9,6 +9,7 @@ import (
 	"io"
 
 	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
 )
 
 // Generator provides a simple API for creating a code generator for
@@ -19,6 +20,21 @@ type Generator interface {
 	Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error
 }
 
+// DocsGenerator is an optional interface a Generator may implement to
+// generate code for all documents at once, rather than one at a time.
+// This allows generators to emit artifacts spanning multiple documents,
+// e.g. a single schema or an index page.
+//
+// If a Generator implements DocsGenerator, GenerateDocs is called
+// instead of Generate with every document of the compilation.
+//
+type DocsGenerator interface {
+	Generator
+
+	// GenerateDocs handles converting a set of GraphQL Documents to scaffolded source code.
+	GenerateDocs(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error
+}
+
 // GeneratorContext represents the directory to which
 // the Generator is to write to.
 //
@@ -27,9 +43,27 @@ type GeneratorContext interface {
 	Open(filename string) (io.WriteCloser, error)
 }
 
+// Create opens a new file in the GeneratorContext, which belongs to the user
+// once it's written, e.g. stubs to implement. If the file already exists, it's
+// left as is and an error satisfying os.IsExist is returned. Created files
+// aren't tracked as generated, so they're never stale. GeneratorContexts,
+// which can't create files, open them instead.
+//
+func Create(gCtx GeneratorContext, filename string) (io.WriteCloser, error) {
+	fc, ok := gCtx.(fileCreator)
+	if !ok {
+		return gCtx.Open(filename)
+	}
+	return fc.Create(filename)
+}
+
 type genCtx string
 
-var genCtxKey = genCtx("genCtx")
+var (
+	genCtxKey  = genCtx("genCtx")
+	dsetKey    = genCtx("docSet")
+	sourcesKey = genCtx("sources")
+)
 
 // WithContext returns a prepared context.Context
 // with the given GeneratorContext.
@@ -43,6 +77,37 @@ func Context(ctx context.Context) GeneratorContext {
 	return ctx.Value(genCtxKey).(GeneratorContext)
 }
 
+// WithDocSet returns a context.Context carrying the
+// token.DocSet which the documents were parsed with.
+//
+func WithDocSet(ctx context.Context, dset *token.DocSet) context.Context {
+	return context.WithValue(ctx, dsetKey, dset)
+}
+
+// DocSet returns the token.DocSet the documents were
+// parsed with or nil, if there is none.
+//
+func DocSet(ctx context.Context) *token.DocSet {
+	dset, _ := ctx.Value(dsetKey).(*token.DocSet)
+	return dset
+}
+
+// WithSources returns a context.Context carrying all compiled documents,
+// including imported ones, as they were parsed by their canonical identities.
+// The source of a type is found by the name of its document in the DocSet.
+//
+func WithSources(ctx context.Context, docs map[string]*ast.Document) context.Context {
+	return context.WithValue(ctx, sourcesKey, docs)
+}
+
+// Sources returns the documents as they were parsed by
+// their canonical identities or nil, if there are none.
+//
+func Sources(ctx context.Context) map[string]*ast.Document {
+	docs, _ := ctx.Value(sourcesKey).(map[string]*ast.Document)
+	return docs
+}
+
 // GeneratorError represents an error from a generator.
 type GeneratorError struct {
 	// DocName is the document being worked on when error was encountered.
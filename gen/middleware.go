@@ -0,0 +1,321 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// Initializer is an optional interface a Generator may implement
+// to set up any state before it generates any documents.
+//
+type Initializer interface {
+	// Init is called once before any documents are generated.
+	Init(ctx context.Context, opts map[string]interface{}) error
+}
+
+// Finisher is an optional interface a Generator may implement
+// to flush any state after it has generated every document.
+//
+type Finisher interface {
+	// Finish is called once after every document was generated successfully.
+	Finish(ctx context.Context) error
+}
+
+// GenerateFunc generates code for the given documents. It is called
+// with a single document for a Generator and with every document
+// for a DocsGenerator.
+//
+type GenerateFunc func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error
+
+// Middleware wraps every call of a generator, in order to handle
+// cross-cutting concerns e.g. license headers, formatting or timing.
+// name is the name of the wrapped generator.
+//
+type Middleware func(name string, next GenerateFunc) GenerateFunc
+
+// Wrap returns a GenerateFunc, which calls g wrapped by the
+// given middlewares. The first middleware is the outermost one.
+//
+func Wrap(name string, g Generator, mws ...Middleware) GenerateFunc {
+	next := func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+		if dg, ok := g.(DocsGenerator); ok {
+			return dg.GenerateDocs(ctx, docs, opts)
+		}
+
+		for _, doc := range docs {
+			err := g.Generate(ctx, doc, opts)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i := len(mws) - 1; i >= 0; i-- {
+		next = mws[i](name, next)
+	}
+	return next
+}
+
+// Run runs a generator, wrapped by the given middlewares, over the given
+// documents. A DocsGenerator is called once with every document and any
+// other Generator once per document. Init and Finish hooks are called
+// before and after, respectively.
+//
+func Run(ctx context.Context, name string, g Generator, docs []*ast.Document, opts map[string]interface{}, mws ...Middleware) error {
+	if i, ok := g.(Initializer); ok {
+		err := i.Init(ctx, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	generate := Wrap(name, g, mws...)
+	if _, ok := g.(DocsGenerator); ok {
+		err := generate(ctx, docs, opts)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, doc := range docs {
+			err := generate(ctx, []*ast.Document{doc}, opts)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if f, ok := g.(Finisher); ok {
+		return f.Finish(ctx)
+	}
+	return nil
+}
+
+// Timing returns a Middleware, which reports how long every call of a generator took.
+func Timing(report func(name string, docs []*ast.Document, d time.Duration)) Middleware {
+	return func(name string, next GenerateFunc) GenerateFunc {
+		return func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+			start := time.Now()
+			err := next(ctx, docs, opts)
+			report(name, docs, time.Since(start))
+			return err
+		}
+	}
+}
+
+// Transform returns a Middleware, which passes the contents of every file
+// whose base name matches pattern through fn before it is written. The
+// pattern syntax is the same as for path.Match.
+//
+func Transform(pattern string, fn func(name string, src []byte) ([]byte, error)) Middleware {
+	return func(name string, next GenerateFunc) GenerateFunc {
+		return func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+			tCtx := &transformCtx{GeneratorContext: Context(ctx), pattern: pattern, fn: fn}
+
+			err := next(WithContext(ctx, tCtx), docs, opts)
+			if err != nil {
+				return err
+			}
+			return tCtx.err
+		}
+	}
+}
+
+// Header returns a Middleware, which prepends header to every file
+// whose base name matches pattern. Files already starting with
+// header are left as is.
+//
+func Header(pattern, header string) Middleware {
+	return Transform(pattern, func(name string, src []byte) ([]byte, error) {
+		if bytes.HasPrefix(src, []byte(header)) {
+			return src, nil
+		}
+		return append([]byte(header), src...), nil
+	})
+}
+
+// Pipe returns a Middleware, which formats every file whose base name
+// matches pattern by piping its contents through the given command,
+// e.g. gofmt or prettier.
+//
+func Pipe(pattern, command string, args ...string) Middleware {
+	return Transform(pattern, func(name string, src []byte) ([]byte, error) {
+		var out, stderr bytes.Buffer
+		cmd := exec.Command(command, args...)
+		cmd.Stdin = bytes.NewReader(src)
+		cmd.Stdout = &out
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		if err != nil {
+			return nil, fmt.Errorf("gen: %s: %s: %w %s", command, name, err, strings.TrimSpace(stderr.String()))
+		}
+		return out.Bytes(), nil
+	})
+}
+
+// MaxSize returns a Middleware, which fails a generator
+// if any file it writes is larger than n bytes.
+//
+func MaxSize(n int) Middleware {
+	return Transform("*", func(name string, src []byte) ([]byte, error) {
+		if len(src) > n {
+			return nil, fmt.Errorf("gen: %s is larger than the maximum size of %d bytes", name, n)
+		}
+		return src, nil
+	})
+}
+
+// The optional file operations a GeneratorContext may support.
+type (
+	fileOpener interface {
+		OpenFile(filename string, perm os.FileMode) (io.WriteCloser, error)
+	}
+
+	fileReader interface {
+		ReadFile(filename string) ([]byte, error)
+	}
+
+	fileRemover interface {
+		Remove(filename string) error
+	}
+
+	fileCreator interface {
+		Create(filename string) (io.WriteCloser, error)
+	}
+
+	dirContext interface {
+		Dir() string
+	}
+)
+
+// transformCtx buffers the files opened in its GeneratorContext
+// and transforms them, before they are written.
+//
+type transformCtx struct {
+	GeneratorContext
+
+	pattern string
+	fn      func(name string, src []byte) ([]byte, error)
+	err     error
+}
+
+func (ctx *transformCtx) Open(filename string) (io.WriteCloser, error) {
+	return ctx.open(filename, func() (io.WriteCloser, error) {
+		return ctx.GeneratorContext.Open(filename)
+	})
+}
+
+func (ctx *transformCtx) OpenFile(filename string, perm os.FileMode) (io.WriteCloser, error) {
+	fo, ok := ctx.GeneratorContext.(fileOpener)
+	if !ok {
+		return nil, fmt.Errorf("gen: generator context does not support setting the mode of files: %s", filename)
+	}
+
+	return ctx.open(filename, func() (io.WriteCloser, error) {
+		return fo.OpenFile(filename, perm)
+	})
+}
+
+func (ctx *transformCtx) ReadFile(filename string) ([]byte, error) {
+	fr, ok := ctx.GeneratorContext.(fileReader)
+	if !ok {
+		return nil, fmt.Errorf("gen: generator context does not support reading files: %s", filename)
+	}
+	return fr.ReadFile(filename)
+}
+
+func (ctx *transformCtx) Remove(filename string) error {
+	fr, ok := ctx.GeneratorContext.(fileRemover)
+	if !ok {
+		return fmt.Errorf("gen: generator context does not support removing files: %s", filename)
+	}
+	return fr.Remove(filename)
+}
+
+func (ctx *transformCtx) Create(filename string) (io.WriteCloser, error) {
+	fc, ok := ctx.GeneratorContext.(fileCreator)
+	if !ok {
+		return ctx.Open(filename)
+	}
+
+	// Check first, so existing files aren't reported once the transformed file is closed
+	w, err := fc.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ctx.open(filename, func() (io.WriteCloser, error) {
+		return w, nil
+	})
+}
+
+func (ctx *transformCtx) Dir() string {
+	dc, ok := ctx.GeneratorContext.(dirContext)
+	if !ok {
+		return ""
+	}
+	return dc.Dir()
+}
+
+func (ctx *transformCtx) open(filename string, open func() (io.WriteCloser, error)) (io.WriteCloser, error) {
+	ok, err := path.Match(ctx.pattern, path.Base(filename))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return open()
+	}
+
+	return &transformFile{ctx: ctx, name: filename, open: open}, nil
+}
+
+// transformFile buffers the contents of a file until it is closed.
+type transformFile struct {
+	bytes.Buffer
+
+	ctx    *transformCtx
+	name   string
+	open   func() (io.WriteCloser, error)
+	closed bool
+}
+
+// Close transforms and writes the file. Any error is also
+// recorded, so it fails the generator, even if it ignores it.
+//
+func (f *transformFile) Close() (err error) {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+
+	defer func() {
+		if err != nil && f.ctx.err == nil {
+			f.ctx.err = err
+		}
+	}()
+
+	src, err := f.ctx.fn(f.name, f.Bytes())
+	if err != nil {
+		return
+	}
+
+	w, err := f.open()
+	if err != nil {
+		return
+	}
+
+	_, err = w.Write(src)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	return
+}
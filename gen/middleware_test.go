@@ -0,0 +1,235 @@
+package gen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+type memCtx map[string]*bytes.Buffer
+
+func (ctx memCtx) Open(name string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx[name] = b
+	return nopCloser{b}, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+type fileGenerator map[string]string
+
+func (g fileGenerator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	for name, content := range g {
+		w, err := Context(ctx).Open(name)
+		if err != nil {
+			return err
+		}
+		io.WriteString(w, content)
+		w.Close()
+	}
+	return nil
+}
+
+func TestWrap(t *testing.T) {
+	var calls []string
+	mw := func(id string) Middleware {
+		return func(name string, next GenerateFunc) GenerateFunc {
+			return func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+				calls = append(calls, id+":"+name)
+				return next(ctx, docs, opts)
+			}
+		}
+	}
+
+	g := fileGenerator{}
+	err := Wrap("test", g, mw("a"), mw("b"))(WithContext(context.Background(), memCtx{}), []*ast.Document{{Name: "one"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(calls, ",") != "a:test,b:test" {
+		t.Errorf("unexpected calls: %v", calls)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	testCases := []struct {
+		Name  string
+		Files fileGenerator
+		MWs   []Middleware
+		Ex    map[string]string
+		Err   string
+	}{
+		{
+			Name:  "Header",
+			Files: fileGenerator{"a.go": "package a", "b.go": "// License\npackage b", "c.md": "# C"},
+			MWs:   []Middleware{Header("*.go", "// License\n")},
+			Ex:    map[string]string{"a.go": "// License\npackage a", "b.go": "// License\npackage b", "c.md": "# C"},
+		},
+		{
+			Name:  "Chained",
+			Files: fileGenerator{"a.js": "a"},
+			MWs: []Middleware{
+				Transform("*.js", func(name string, src []byte) ([]byte, error) { return append(src, '2'), nil }),
+				Transform("*.js", func(name string, src []byte) ([]byte, error) { return append(src, '1'), nil }),
+			},
+			Ex: map[string]string{"a.js": "a12"},
+		},
+		{
+			Name:  "MaxSize",
+			Files: fileGenerator{"a.txt": "small", "b.txt": "way too large"},
+			MWs:   []Middleware{MaxSize(5)},
+			Err:   "gen: b.txt is larger than the maximum size of 5 bytes",
+		},
+		{
+			Name:  "BadPattern",
+			Files: fileGenerator{"a.txt": ""},
+			MWs:   []Middleware{Header("[", "")},
+			Err:   "syntax error in pattern",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			gCtx := memCtx{}
+			ctx := WithContext(context.Background(), gCtx)
+
+			err := Wrap("test", testCase.Files, testCase.MWs...)(ctx, []*ast.Document{{Name: "one"}}, nil)
+			if testCase.Err != "" {
+				if err == nil || err.Error() != testCase.Err {
+					subT.Fatalf("expected error: %s, but got: %v", testCase.Err, err)
+				}
+				if _, exists := gCtx["b.txt"]; exists {
+					subT.Error("expected file which failed to be transformed to not be written")
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if len(gCtx) != len(testCase.Ex) {
+				subT.Fatalf("expected %d files, but got: %d", len(testCase.Ex), len(gCtx))
+			}
+			for name, ex := range testCase.Ex {
+				if gCtx[name].String() != ex {
+					subT.Errorf("expected %s to be: %q, but got: %q", name, ex, gCtx[name])
+				}
+			}
+		})
+	}
+}
+
+func TestTransform_UnsupportedContext(t *testing.T) {
+	ctx := WithContext(context.Background(), memCtx{})
+
+	err := Header("*", "")("test", func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+		gCtx := Context(ctx)
+
+		if _, err := gCtx.(fileReader).ReadFile("a"); err == nil {
+			return errors.New("expected error when reading files")
+		}
+		if err := gCtx.(fileRemover).Remove("a"); err == nil {
+			return errors.New("expected error when removing files")
+		}
+		if gCtx.(dirContext).Dir() != "" {
+			return errors.New("expected empty dir")
+		}
+		return nil
+	})(ctx, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// createCtx is a memCtx, which creates files only if they don't exist.
+type createCtx struct{ memCtx }
+
+func (ctx createCtx) Create(name string) (io.WriteCloser, error) {
+	if _, ok := ctx.memCtx[name]; ok {
+		return nil, &os.PathError{Op: "create", Path: name, Err: os.ErrExist}
+	}
+	return ctx.Open(name)
+}
+
+func TestCreate(t *testing.T) {
+	files := memCtx{"a.go": bytes.NewBufferString("package a")}
+	ctx := WithContext(context.Background(), createCtx{files})
+
+	err := Header("*.go", "// License\n")("test", func(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+		gCtx := Context(ctx)
+
+		if _, err := Create(gCtx, "a.go"); !os.IsExist(err) {
+			return fmt.Errorf("expected a.go to exist, but got: %v", err)
+		}
+
+		w, err := Create(gCtx, "b.go")
+		if err != nil {
+			return err
+		}
+		io.WriteString(w, "package b")
+		return w.Close()
+	})(ctx, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a := files["a.go"].String(); a != "package a" {
+		t.Errorf("expected a.go to be kept, but got: %q", a)
+	}
+	if b := files["b.go"].String(); b != "// License\npackage b" {
+		t.Errorf("expected b.go to be transformed, but got: %q", b)
+	}
+
+	// Contexts, which can't create files, open them
+	plain := memCtx{"a.go": bytes.NewBufferString("package a")}
+	w, err := Create(plain, "a.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "package b")
+	if a := plain["a.go"].String(); a != "package b" {
+		t.Errorf("expected a.go to be overwritten, but got: %q", a)
+	}
+}
+
+func TestTiming(t *testing.T) {
+	var reported string
+	mw := Timing(func(name string, docs []*ast.Document, d time.Duration) {
+		reported = name
+	})
+
+	err := Wrap("test", fileGenerator{}, mw)(WithContext(context.Background(), memCtx{}), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reported != "test" {
+		t.Errorf("expected timing to be reported for test, but got: %q", reported)
+	}
+}
+
+func TestPipe(t *testing.T) {
+	gCtx := memCtx{}
+	ctx := WithContext(context.Background(), gCtx)
+
+	err := Wrap("test", fileGenerator{"a.txt": "hello"}, Pipe("*.txt", "tr", "a-z", "A-Z"))(ctx, []*ast.Document{{Name: "one"}}, nil)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	b, _ := ioutil.ReadAll(gCtx["a.txt"])
+	if string(b) != "HELLO" {
+		t.Errorf("unexpected content: %s", b)
+	}
+}
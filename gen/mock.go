@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/gqlc/gqlc/gen (interfaces: Generator)
+// Source: github.com/gqlc/gqlc/gen (interfaces: Generator,DocsGenerator)
 
 package gen
 
@@ -44,3 +44,50 @@ func (m *MockGenerator) Generate(arg0 context.Context, arg1 *ast.Document, arg2
 func (mr *MockGeneratorMockRecorder) Generate(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockGenerator)(nil).Generate), arg0, arg1, arg2)
 }
+
+// MockDocsGenerator is a mock of DocsGenerator interface
+type MockDocsGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockDocsGeneratorMockRecorder
+}
+
+// MockDocsGeneratorMockRecorder is the mock recorder for MockDocsGenerator
+type MockDocsGeneratorMockRecorder struct {
+	mock *MockDocsGenerator
+}
+
+// NewMockDocsGenerator creates a new mock instance
+func NewMockDocsGenerator(ctrl *gomock.Controller) *MockDocsGenerator {
+	mock := &MockDocsGenerator{ctrl: ctrl}
+	mock.recorder = &MockDocsGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDocsGenerator) EXPECT() *MockDocsGeneratorMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method
+func (m *MockDocsGenerator) Generate(arg0 context.Context, arg1 *ast.Document, arg2 map[string]interface{}) error {
+	ret := m.ctrl.Call(m, "Generate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Generate indicates an expected call of Generate
+func (mr *MockDocsGeneratorMockRecorder) Generate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockDocsGenerator)(nil).Generate), arg0, arg1, arg2)
+}
+
+// GenerateDocs mocks base method
+func (m *MockDocsGenerator) GenerateDocs(arg0 context.Context, arg1 []*ast.Document, arg2 map[string]interface{}) error {
+	ret := m.ctrl.Call(m, "GenerateDocs", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GenerateDocs indicates an expected call of GenerateDocs
+func (mr *MockDocsGeneratorMockRecorder) GenerateDocs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateDocs", reflect.TypeOf((*MockDocsGenerator)(nil).GenerateDocs), arg0, arg1, arg2)
+}
@@ -0,0 +1,128 @@
+// backend.go contains what the schema-first backends have in common
+
+package golang
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/graphql/ast"
+)
+
+// Backends the Go generator targets. Unlike graphql-go, which builds its
+// schema in Go, gqlgen and graph-gophers build theirs from SDL.
+//
+const (
+	backendGraphQLGo = "graphql-go"
+	backendGqlgen    = "gqlgen"
+	backendGophers   = "graph-gophers"
+)
+
+// writeRaw writes data to the named file as is.
+func writeRaw(gCtx gen.GeneratorContext, name string, data []byte) (err error) {
+	f, err := gCtx.Open(name)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return
+}
+
+// goIdent returns a GraphQL name as a Go identifier, which
+// only differs from the name, if the name is a Go keyword.
+//
+func goIdent(name string) string {
+	if token.Lookup(name).IsKeyword() {
+		return name + "_"
+	}
+	return name
+}
+
+// unexportName returns the unexported Go name of a GraphQL name.
+func unexportName(name string) string {
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// bindingPackages returns the packages of the Go types scalars are bound to.
+func bindingPackages(bs map[string]*binding) (imports []string) {
+	for _, b := range bs {
+		if b.pkg != "" {
+			imports = append(imports, b.pkg)
+		}
+	}
+	return
+}
+
+// generateEnumType generates a string type for an enum, whose constants
+// are the values given by @as. Its String method returns the GraphQL
+// name of a value and the named method sets a value from its
+// GraphQL name, so the values never leave Go.
+//
+func (g *Generator) generateEnumType(name, unmarshal string, descr bool, doc *ast.DocGroup, enum *ast.EnumType) {
+	g.P()
+	g.printComment(descr, doc)
+	g.P("type ", name, " string")
+	if enum.Values == nil {
+		return
+	}
+
+	g.P()
+	g.P("const (")
+	g.In()
+	for _, ev := range enum.Values.List {
+		val := getValue(ev.Directives)
+		if val == "" {
+			val = ev.Name.Name
+		}
+		g.printDeprecated(ev.Directives)
+		g.P(name, enumName(ev.Name.Name), " ", name, " = ", strconv.Quote(val))
+	}
+	g.Out()
+	g.P(")")
+
+	g.P()
+	g.P("// String returns the GraphQL name of e.")
+	g.P("func (e ", name, ") String() string {")
+	g.In()
+	g.P("switch e {")
+	for _, ev := range enum.Values.List {
+		g.P("case ", name, enumName(ev.Name.Name), ":")
+		g.In()
+		g.P("return \"", ev.Name.Name, "\"")
+		g.Out()
+	}
+	g.P("}")
+	g.P("return string(e)")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// ", unmarshal, " sets e to the value of the given GraphQL name.")
+	g.P("func (e *", name, ") ", unmarshal, "(v interface{}) error {")
+	g.In()
+	g.P("s, ok := v.(string)")
+	g.P("if !ok {")
+	g.In()
+	g.P("return fmt.Errorf(\"", name, " must be a string\")")
+	g.Out()
+	g.P("}")
+	g.P("switch s {")
+	for _, ev := range enum.Values.List {
+		g.P("case \"", ev.Name.Name, "\":")
+		g.In()
+		g.P("*e = ", name, enumName(ev.Name.Name))
+		g.Out()
+	}
+	g.P("default:")
+	g.In()
+	g.P("return fmt.Errorf(\"%s is not a valid ", name, "\", s)")
+	g.Out()
+	g.P("}")
+	g.P("return nil")
+	g.Out()
+	g.P("}")
+}
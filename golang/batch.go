@@ -0,0 +1,350 @@
+// batch.go generates dataloaders for the fields batched with @batch
+
+package golang
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+)
+
+// batchField is a field of an object, which is loaded for many
+// objects at once, keyed by another field of the object.
+//
+type batchField struct {
+	typeName string
+	field    *ast.Field
+
+	// key is the field of the object the values are loaded by
+	key *ast.Field
+}
+
+// name returns the name the loader of the field is generated under, e.g. UserFriends.
+func (b batchField) name() string {
+	return b.typeName + exportName(b.field.Name.Name)
+}
+
+// batchedFields returns the fields of objects with a @batch directive. Since
+// their values are loaded by key, they can't have arguments or a @resolver
+// and their key must be a scalar or enum field of the same object.
+//
+func batchedFields(decls []*ast.TypeDecl) (batches []batchField, err error) {
+	ks := typeKinds(decls)
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object)
+		if !ok || obj.Object.Fields == nil {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		for _, f := range obj.Object.Fields.List {
+			key := getBatch(f.Directives)
+			if key == "" {
+				continue
+			}
+
+			if getResolver(f.Directives) != "" {
+				return nil, fmt.Errorf("%s.%s can not have both @batch and @resolver", name, f.Name.Name)
+			}
+			if f.Args != nil && len(f.Args.List) > 0 {
+				return nil, fmt.Errorf("%s.%s can not be batched, since it has arguments", name, f.Name.Name)
+			}
+
+			b := batchField{typeName: name, field: f}
+			for _, kf := range obj.Object.Fields.List {
+				if kf.Name.Name == key {
+					b.key = kf
+				}
+			}
+			if b.key == nil {
+				return nil, fmt.Errorf("@batch key %s of %s.%s is not a field of %s", key, name, f.Name.Name, name)
+			}
+			if id, _ := keyIdent(b.key); id == nil || !ks.isLeaf(id.Name) {
+				return nil, fmt.Errorf("@batch key %s of %s.%s must be a scalar or enum", key, name, f.Name.Name)
+			}
+			batches = append(batches, b)
+		}
+	}
+	return
+}
+
+// keyIdent returns the named type of a key field and whether it's
+// non-null, or nil, if the field is a list.
+//
+func keyIdent(f *ast.Field) (id *ast.Ident, nonNull bool) {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident, false
+	case *ast.Field_NonNull:
+		if i, ok := v.NonNull.Type.(*ast.NonNull_Ident); ok {
+			return i.Ident, true
+		}
+	}
+	return nil, false
+}
+
+// isLeaf reports whether the named type is a scalar or enum.
+func (ks kinds) isLeaf(name string) bool {
+	if _, ok := builtinTypes[name]; ok {
+		return true
+	}
+
+	switch ks[name].(type) {
+	case *binding, *ast.TypeSpec_Scalar, *ast.TypeSpec_Enum:
+		return true
+	}
+	return false
+}
+
+// generateLoaders generates a dataloader for each batched field, which
+// collects the keys of a request and loads them with one call of its
+// batch function, the Batchers interface providing the batch functions
+// and the Loaders of a request, which are carried by its context.
+// With models, keys and values are typed by the models.
+//
+func (g *Generator) generateLoaders(decls []*ast.TypeDecl, batches []batchField, models bool) {
+	if len(batches) == 0 {
+		return
+	}
+
+	var ks kinds
+	if models {
+		ks = g.kinds(decls)
+	}
+
+	for _, b := range batches {
+		g.generateLoader(b, batchKey(ks, b, models), batchValue(ks, b, models), models)
+	}
+
+	g.P()
+	g.P("// loadResult is a value loaded by a dataloader.")
+	g.P("type loadResult struct {")
+	g.In()
+	g.P("value interface{}")
+	g.P("err error")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// Batchers provides the batch functions of the batched fields.")
+	g.P("type Batchers interface {")
+	g.In()
+	for _, b := range batches {
+		g.P(b.name(), "(ctx context.Context, keys []", batchKey(ks, b, models), ") ([]", batchValue(ks, b, models), ", []error)")
+	}
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// Loaders are the dataloaders of a request.")
+	g.P("type Loaders struct {")
+	g.In()
+	for _, b := range batches {
+		g.P(b.name(), " *", b.name(), "Loader")
+	}
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// NewLoaders returns new dataloaders, which load with the given batch functions.")
+	g.P("func NewLoaders(b Batchers) *Loaders {")
+	g.In()
+	g.P("return &Loaders{")
+	g.In()
+	for _, b := range batches {
+		g.P(b.name(), ": New", b.name(), "Loader(b.", b.name(), "),")
+	}
+	g.Out()
+	g.P("}")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("type loadersKey struct{}")
+	g.P()
+	g.P("// WithLoaders returns a copy of ctx carrying new dataloaders, which load")
+	g.P("// with the given batch functions. Use it for the Context of each request")
+	g.P("// passed to graphql.Do, so loaded values are only cached per request.")
+	g.P("//")
+	g.P("func WithLoaders(ctx context.Context, b Batchers) context.Context {")
+	g.In()
+	g.P("return context.WithValue(ctx, loadersKey{}, NewLoaders(b))")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// LoadersFrom returns the dataloaders carried by ctx or nil, if there are none.")
+	g.P("func LoadersFrom(ctx context.Context) *Loaders {")
+	g.In()
+	g.P("l, _ := ctx.Value(loadersKey{}).(*Loaders)")
+	g.P("return l")
+	g.Out()
+	g.P("}")
+}
+
+// batchKey returns the Go type of the keys of a batched field.
+func batchKey(ks kinds, b batchField, models bool) string {
+	if !models {
+		return "interface{}"
+	}
+	id, _ := keyIdent(b.key)
+	return ks.goType(id, true)
+}
+
+// batchValue returns the Go type of the values of a batched field.
+func batchValue(ks kinds, b batchField, models bool) string {
+	if !models {
+		return "interface{}"
+	}
+	return ks.goType(graph.FieldType(b.field), false)
+}
+
+// generateLoader generates the batch function type, loader and resolver of a batched field.
+func (g *Generator) generateLoader(b batchField, key, value string, models bool) {
+	name, field, keyName := b.name(), b.typeName+"."+b.field.Name.Name, b.key.Name.Name
+
+	g.P()
+	g.P("// ", name, "Batch loads ", field, " for the ", keyName, " of many ", b.typeName, " at once.")
+	g.P("// It returns a value and an error, which may be nil, for each key,")
+	g.P("// in the order of the keys.")
+	g.P("//")
+	g.P("type ", name, "Batch func(ctx context.Context, keys []", key, ") ([]", value, ", []error)")
+
+	g.P()
+	g.P("// ", name, "Loader loads ", field, " in batches and caches the loaded values.")
+	g.P("type ", name, "Loader struct {")
+	g.In()
+	g.P("batch ", name, "Batch")
+	g.P()
+	g.P("mu sync.Mutex")
+	g.P("cache map[", key, "]*loadResult")
+	g.P("pending []", key)
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// New", name, "Loader returns a loader, which loads with the given batch function.")
+	g.P("func New", name, "Loader(batch ", name, "Batch) *", name, "Loader {")
+	g.In()
+	g.P("return &", name, "Loader{batch: batch, cache: make(map[", key, "]*loadResult)}")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// Load adds the key to the next batch and returns a thunk returning its value.")
+	g.P("// Since graphql-go calls thunks once the other fields of their level are")
+	g.P("// resolved, the keys of all of them are loaded at once.")
+	g.P("//")
+	g.P("func (l *", name, "Loader) Load(ctx context.Context, key ", key, ") func() (interface{}, error) {")
+	g.In()
+	g.P("l.mu.Lock()")
+	g.P("if _, ok := l.cache[key]; !ok {")
+	g.In()
+	g.P("l.cache[key] = nil")
+	g.P("l.pending = append(l.pending, key)")
+	g.Out()
+	g.P("}")
+	g.P("l.mu.Unlock()")
+	g.P()
+	g.P("return func() (interface{}, error) {")
+	g.In()
+	g.P("l.mu.Lock()")
+	g.P("defer l.mu.Unlock()")
+	g.P("if l.cache[key] == nil {")
+	g.In()
+	g.P("l.dispatch(ctx)")
+	g.Out()
+	g.P("}")
+	g.P("r := l.cache[key]")
+	g.P("return r.value, r.err")
+	g.Out()
+	g.P("}")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// dispatch loads the pending keys with one call of the batch function.")
+	g.P("func (l *", name, "Loader) dispatch(ctx context.Context) {")
+	g.In()
+	g.P("keys := l.pending")
+	g.P("l.pending = nil")
+	g.P("values, errs := l.batch(ctx, keys)")
+	g.P("for i, key := range keys {")
+	g.In()
+	g.P("r := &loadResult{err: fmt.Errorf(", strconv.Quote("no value loaded for "+field+" of %v"), ", key)}")
+	g.P("if i < len(values) {")
+	g.In()
+	g.P("r.value, r.err = values[i], nil")
+	g.Out()
+	g.P("}")
+	g.P("if i < len(errs) && errs[i] != nil {")
+	g.In()
+	g.P("r.err = errs[i]")
+	g.Out()
+	g.P("}")
+	g.P("l.cache[key] = r")
+	g.Out()
+	g.P("}")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// ", batchResolver(b), " loads ", field, " with the dataloaders of the request.")
+	g.P("func ", batchResolver(b), "(p graphql.ResolveParams) (interface{}, error) {")
+	g.In()
+	g.P("l := LoadersFrom(p.Context)")
+	g.P("if l == nil {")
+	g.In()
+	g.P("return nil, errors.New(", strconv.Quote("no dataloaders to load "+field+" with, see WithLoaders"), ")")
+	g.Out()
+	g.P("}")
+	if !models {
+		g.P("key, err := graphql.DefaultResolveFn(graphql.ResolveParams{")
+		g.In()
+		g.P("Source: p.Source,")
+		g.P("Context: p.Context,")
+		g.P("Info: graphql.ResolveInfo{FieldName: ", strconv.Quote(keyName), "},")
+		g.Out()
+		g.P("})")
+		g.P("if err != nil || key == nil {")
+		g.In()
+		g.P("return nil, err")
+		g.Out()
+		g.P("}")
+		g.P("return l.", name, ".Load(p.Context, key), nil")
+		g.Out()
+		g.P("}")
+		return
+	}
+
+	g.P("src, ok := p.Source.(*", b.typeName, ")")
+	g.P("if !ok {")
+	g.In()
+	g.P("return nil, fmt.Errorf(", strconv.Quote("can not load "+field+" of %T"), ", p.Source)")
+	g.Out()
+	g.P("}")
+	keyField := "src." + exportName(keyName)
+	if _, nonNull := keyIdent(b.key); !nonNull {
+		g.P("if ", keyField, " == nil {")
+		g.In()
+		g.P("return nil, nil")
+		g.Out()
+		g.P("}")
+		keyField = "*" + keyField
+	}
+	g.P("return l.", name, ".Load(p.Context, ", keyField, "), nil")
+	g.Out()
+	g.P("}")
+}
+
+// batchResolver returns the name of the resolver loading a batched field.
+func batchResolver(b batchField) string {
+	return "resolve" + b.name()
+}
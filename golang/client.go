@@ -0,0 +1,617 @@
+// client.go generates a typed Go client for the operations of executable documents
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/gqlc/operation"
+	"github.com/gqlc/graphql/ast"
+	"go.uber.org/zap"
+)
+
+// ClientOptions contains the options for the Go client generator.
+type ClientOptions struct {
+	// Package is the go package the client belongs to. (default: client)
+	Package string
+
+	// Copy descriptions to Go
+	Descriptions bool
+}
+
+// ClientGenerator generates a typed Go client for the operations compiled
+// along with a schema. Every operation gets a method on the Client, a struct
+// of its variables and structs mirroring its selection sets.
+//
+type ClientGenerator struct {
+	Generator
+}
+
+var clientImports = []string{
+	"bytes",
+	"context",
+	"encoding/json",
+	"fmt",
+	"net/http",
+	"strings",
+}
+
+// Generate generates a client for the operations against the given document.
+func (g *ClientGenerator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	return g.GenerateDocs(ctx, []*ast.Document{doc}, opts)
+}
+
+// GenerateDocs generates a client for the operations against the given documents.
+// It writes the Client to client.go, a file per executable document, e.g.
+// users.go for users.graphql, and the enums and inputs used to types.go.
+//
+func (g *ClientGenerator) GenerateDocs(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) (err error) {
+	g.Lock()
+	defer g.Unlock()
+
+	docName := "client"
+	defer func() {
+		if err != nil {
+			err = gen.GeneratorError{
+				DocName: docName,
+				GenName: "goclient",
+				Msg:     err.Error(),
+			}
+		}
+	}()
+
+	if g.log == nil {
+		g.log = zap.L().Named("goclient")
+	}
+
+	ops := operation.Documents(ctx)
+	if len(ops) == 0 {
+		g.log.Info("no operations to generate a client for")
+		return nil
+	}
+	cOpts := getClientOptions(opts)
+
+	// Compiled documents include their imports, so types may be repeated
+	var decls []*ast.TypeDecl
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		for _, d := range doc.Types {
+			ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+			if !ok || ts.TypeSpec.Name == nil || seen[ts.TypeSpec.Name.Name] {
+				continue
+			}
+			seen[ts.TypeSpec.Name.Name] = true
+			decls = append(decls, d)
+		}
+	}
+
+	c := &clientGen{
+		g:      &g.Generator,
+		schema: operation.NewSchema(docs),
+		ks:     typeKinds(decls),
+		used:   make(map[string]bool),
+	}
+	imports := append(append([]string(nil), clientImports...), bindingPackages(bindings(decls))...)
+	gCtx := gen.Context(ctx)
+
+	g.log.Info("generating client")
+	g.Reset()
+	g.generateClient()
+	err = g.writeFile(gCtx, "client.go", cOpts.Package, imports, g.Bytes())
+	if err != nil {
+		return
+	}
+
+	for _, doc := range ops {
+		docName = doc.Name
+		g.log.Info("generating operations", zap.String("doc", doc.Name))
+
+		g.Reset()
+		for _, op := range doc.Operations {
+			err = c.generateOperation(doc, op)
+			if err != nil {
+				return
+			}
+		}
+		err = g.writeFile(gCtx, doc.Name+".go", cOpts.Package, imports, g.Bytes())
+		if err != nil {
+			return
+		}
+	}
+	docName = "client"
+
+	if len(c.used) == 0 {
+		return nil
+	}
+
+	g.log.Info("generating types")
+	g.Reset()
+	for _, d := range decls {
+		ts := d.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec
+		name := ts.Name.Name
+		if !c.used[name] {
+			continue
+		}
+
+		switch v := ts.Type.(type) {
+		case *ast.TypeSpec_Enum:
+			g.generateEnumType(name, "UnmarshalGraphQL", cOpts.Descriptions, d.Doc, v.Enum)
+			g.generateEnumJSON(name)
+		case *ast.TypeSpec_Input:
+			g.P()
+			g.printComment(cOpts.Descriptions, d.Doc)
+			g.P("type ", name, " struct {")
+			g.In()
+			if v.Input.Fields != nil {
+				g.generateStructFields(c.ks, v.Input.Fields.List)
+			}
+			g.Out()
+			g.P("}")
+		}
+	}
+	return g.writeFile(gCtx, "types.go", cOpts.Package, imports, g.Bytes())
+}
+
+// getClientOptions returns the options of the client generator given by the CLI.
+func getClientOptions(opts map[string]interface{}) *ClientOptions {
+	cOpts := &ClientOptions{Package: "client"}
+	if p, ok := opts["package"].(string); ok && p != "" {
+		cOpts.Package = strings.Trim(p, "\"")
+	}
+	if d, ok := opts["descriptions"].(bool); ok {
+		cOpts.Descriptions = d
+	}
+	return cOpts
+}
+
+// generateClient generates the Client, which sends the requests
+// of every operation, and the errors it returns.
+//
+func (g *Generator) generateClient() {
+	g.P("// Client sends GraphQL requests over HTTP.")
+	g.P("type Client struct {")
+	g.In()
+	g.P("// URL is the URL of the GraphQL endpoint.")
+	g.P("URL string")
+	g.P()
+	g.P("// HTTPClient sends the requests. (default: http.DefaultClient)")
+	g.P("HTTPClient *http.Client")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// Error is an error reported by the GraphQL server.")
+	g.P("type Error struct {")
+	g.In()
+	g.P("Message string `json:\"message\"`")
+	g.P("Path []interface{} `json:\"path,omitempty\"`")
+	g.Out()
+	g.P("}")
+	g.P()
+	g.P("func (e *Error) Error() string {")
+	g.In()
+	g.P("return \"graphql: \" + e.Message")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// Errors are the errors of a response.")
+	g.P("type Errors []*Error")
+	g.P()
+	g.P("func (e Errors) Error() string {")
+	g.In()
+	g.P("msgs := make([]string, len(e))")
+	g.P("for i, err := range e {")
+	g.In()
+	g.P("msgs[i] = err.Error()")
+	g.Out()
+	g.P("}")
+	g.P("return strings.Join(msgs, \"\\n\")")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// Do sends an operation with its variables and decodes the data of the")
+	g.P("// response into data. Any errors of the response are returned as Errors.")
+	g.P("//")
+	g.P("func (c *Client) Do(ctx context.Context, query, operationName string, variables, data interface{}) error {")
+	g.In()
+	g.P("body, err := json.Marshal(struct {")
+	g.In()
+	g.P("Query string `json:\"query\"`")
+	g.P("OperationName string `json:\"operationName\"`")
+	g.P("Variables interface{} `json:\"variables,omitempty\"`")
+	g.Out()
+	g.P("}{query, operationName, variables})")
+	g.P("if err != nil {")
+	g.In()
+	g.P("return err")
+	g.Out()
+	g.P("}")
+	g.P()
+	g.P("req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))")
+	g.P("if err != nil {")
+	g.In()
+	g.P("return err")
+	g.Out()
+	g.P("}")
+	g.P("req.Header.Set(\"Content-Type\", \"application/json\")")
+	g.P("req.Header.Set(\"Accept\", \"application/json\")")
+	g.P()
+	g.P("hc := c.HTTPClient")
+	g.P("if hc == nil {")
+	g.In()
+	g.P("hc = http.DefaultClient")
+	g.Out()
+	g.P("}")
+	g.P("resp, err := hc.Do(req)")
+	g.P("if err != nil {")
+	g.In()
+	g.P("return err")
+	g.Out()
+	g.P("}")
+	g.P("defer resp.Body.Close()")
+	g.P()
+	g.P("var out struct {")
+	g.In()
+	g.P("Data json.RawMessage `json:\"data\"`")
+	g.P("Errors Errors `json:\"errors\"`")
+	g.Out()
+	g.P("}")
+	g.P("err = json.NewDecoder(resp.Body).Decode(&out)")
+	g.P("if err != nil && resp.StatusCode != http.StatusOK {")
+	g.In()
+	g.P("return fmt.Errorf(\"graphql: unexpected status: %s\", resp.Status)")
+	g.Out()
+	g.P("}")
+	g.P("if err != nil {")
+	g.In()
+	g.P("return err")
+	g.Out()
+	g.P("}")
+	g.P("if len(out.Errors) > 0 {")
+	g.In()
+	g.P("return out.Errors")
+	g.Out()
+	g.P("}")
+	g.P("return json.Unmarshal(out.Data, data)")
+	g.Out()
+	g.P("}")
+}
+
+// generateEnumJSON generates the JSON methods of an enum, which use
+// its GraphQL names, as the variables and responses do.
+//
+func (g *Generator) generateEnumJSON(name string) {
+	g.P()
+	g.P("// MarshalJSON marshals the GraphQL name of e.")
+	g.P("func (e ", name, ") MarshalJSON() ([]byte, error) {")
+	g.In()
+	g.P("return json.Marshal(e.String())")
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// UnmarshalJSON sets e to the value of a GraphQL name.")
+	g.P("func (e *", name, ") UnmarshalJSON(b []byte) error {")
+	g.In()
+	g.P("var s string")
+	g.P("if err := json.Unmarshal(b, &s); err != nil {")
+	g.In()
+	g.P("return err")
+	g.Out()
+	g.P("}")
+	g.P("return e.UnmarshalGraphQL(s)")
+	g.Out()
+	g.P("}")
+}
+
+// clientGen holds the schema the operations of a client are generated against.
+type clientGen struct {
+	g      *Generator
+	schema *operation.Schema
+	ks     kinds
+
+	// used are the enums and inputs used by operations.
+	used map[string]bool
+}
+
+// generateOperation generates the document, variables and
+// response structs and the Client method of an operation.
+//
+func (c *clientGen) generateOperation(doc *operation.Document, op *operation.Operation) error {
+	if op.Name == "" {
+		return fmt.Errorf("%s: the Go client requires named operations", op.Pos)
+	}
+	if op.Type == operation.Subscription {
+		return fmt.Errorf("%s: the Go client does not support subscriptions", op.Pos)
+	}
+	g := c.g
+	name := exportName(op.Name)
+	kind := exportName(op.Type)
+
+	// The document holds the operation along with the fragments it spreads
+	src := op.Source
+	for _, f := range spreadFragments(doc, op.SelectionSet) {
+		src += "\n\n" + f.Source
+	}
+	g.P()
+	g.P("// ", name, kind, " is the ", op.Name, " ", op.Type, " and the fragments it spreads.")
+	if strings.Contains(src, "`") {
+		g.P("const ", name, kind, " = ", strconv.Quote(src))
+	} else {
+		g.P("const ", name, kind, " = `", src, "`")
+	}
+
+	if len(op.Variables) > 0 {
+		g.P()
+		g.P("// ", name, "Variables are the variables of the ", op.Name, " ", op.Type, ".")
+		g.P("type ", name, "Variables struct {")
+		g.In()
+		for _, v := range op.Variables {
+			tag := v.Name
+			if _, nonNull := v.Type.(*ast.NonNull); !nonNull {
+				tag += ",omitempty"
+			}
+			g.P(exportName(v.Name), " ", c.ks.goType(v.Type, false), " `json:\"", tag, "\"`")
+			c.useInput(operation.TypeName(v.Type))
+		}
+		g.Out()
+		g.P("}")
+	}
+
+	root := c.schema.Roots[op.Type]
+	err := c.generateSelection(doc, name+"Response", "is the data of the "+op.Name+" "+op.Type+".", root, op.SelectionSet)
+	if err != nil {
+		return err
+	}
+
+	g.P()
+	g.P("// ", name, " sends the ", op.Name, " ", op.Type, ".")
+	if len(op.Variables) > 0 {
+		g.P("func (c *Client) ", name, "(ctx context.Context, vars ", name, "Variables) (*", name, "Response, error) {")
+	} else {
+		g.P("func (c *Client) ", name, "(ctx context.Context) (*", name, "Response, error) {")
+	}
+	g.In()
+	g.P("var data ", name, "Response")
+	if len(op.Variables) > 0 {
+		g.P("err := c.Do(ctx, ", name, kind, ", \"", op.Name, "\", vars, &data)")
+	} else {
+		g.P("err := c.Do(ctx, ", name, kind, ", \"", op.Name, "\", nil, &data)")
+	}
+	g.P("if err != nil {")
+	g.In()
+	g.P("return nil, err")
+	g.Out()
+	g.P("}")
+	g.P("return &data, nil")
+	g.Out()
+	g.P("}")
+	return nil
+}
+
+// spreadFragments returns the fragments spread by a selection
+// set, directly or indirectly, in the order they're declared.
+//
+func spreadFragments(doc *operation.Document, sels []operation.Selection) []*operation.Fragment {
+	spread := make(map[string]bool)
+
+	var visit func(sels []operation.Selection)
+	visit = func(sels []operation.Selection) {
+		for _, sel := range sels {
+			switch v := sel.(type) {
+			case *operation.Field:
+				visit(v.SelectionSet)
+			case *operation.InlineFragment:
+				visit(v.SelectionSet)
+			case *operation.FragmentSpread:
+				f := doc.Fragment(v.Name)
+				if f == nil || spread[f.Name] {
+					continue
+				}
+				spread[f.Name] = true
+				visit(f.SelectionSet)
+			}
+		}
+	}
+	visit(sels)
+
+	var frags []*operation.Fragment
+	for _, f := range doc.Fragments {
+		if spread[f.Name] {
+			frags = append(frags, f)
+		}
+	}
+	return frags
+}
+
+// useInput marks an enum or input as used, as well as any used by its fields.
+func (c *clientGen) useInput(name string) {
+	if c.used[name] {
+		return
+	}
+
+	switch v := c.schema.Types[name].GetType().(type) {
+	case *ast.TypeSpec_Enum:
+		c.used[name] = true
+	case *ast.TypeSpec_Input:
+		c.used[name] = true
+		if v.Input.Fields == nil {
+			return
+		}
+		for _, f := range v.Input.Fields.List {
+			c.useInput(operation.TypeName(graph.InputValueType(f)))
+		}
+	}
+}
+
+// selected is a field of a response struct, which is merged from
+// every selection of its response key, including those of fragments.
+//
+type selected struct {
+	key  string
+	def  *ast.Field
+	sels []operation.Selection
+
+	// optional is whether the field is only selected conditionally i.e.
+	// by fragments on other types or with @skip or @include.
+	//
+	optional bool
+}
+
+// collectFields merges the fields selected on a value of typ by their response keys.
+func (c *clientGen) collectFields(doc *operation.Document, typ, parent string, sels []operation.Selection, optional bool, fields []*selected) ([]*selected, error) {
+	var err error
+	for _, sel := range sels {
+		switch v := sel.(type) {
+		case *operation.Field:
+			def := c.schema.Field(parent, v.Name)
+			if def == nil {
+				return nil, fmt.Errorf("%s: field %s is not defined by %s", v.Pos, v.Name, parent)
+			}
+			opt := optional || isConditional(v.Directives)
+
+			var f *selected
+			for _, other := range fields {
+				if other.key == v.Key() {
+					f = other
+				}
+			}
+			if f == nil {
+				fields = append(fields, &selected{key: v.Key(), def: def, sels: v.SelectionSet, optional: opt})
+				continue
+			}
+			if f.def.Name.Name != v.Name {
+				return nil, fmt.Errorf("%s: %s selects both %s and %s", v.Pos, v.Key(), f.def.Name.Name, v.Name)
+			}
+			f.sels = append(f.sels, v.SelectionSet...)
+			f.optional = f.optional && opt
+		case *operation.FragmentSpread:
+			frag := doc.Fragment(v.Name)
+			if frag == nil {
+				return nil, fmt.Errorf("%s: unknown fragment %s", v.Pos, v.Name)
+			}
+			opt := optional || frag.TypeCondition != typ || isConditional(v.Directives)
+			fields, err = c.collectFields(doc, typ, frag.TypeCondition, frag.SelectionSet, opt, fields)
+		case *operation.InlineFragment:
+			cond := v.TypeCondition
+			if cond == "" {
+				cond = parent
+			}
+			opt := optional || cond != typ || isConditional(v.Directives)
+			fields, err = c.collectFields(doc, typ, cond, v.SelectionSet, opt, fields)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+func isConditional(dirs []*ast.DirectiveLit) bool {
+	for _, d := range dirs {
+		if d.Name == "skip" || d.Name == "include" {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSelection generates the struct of a selection set on typ,
+// followed by the structs of the selection sets of its fields, which
+// are named after it and their response keys.
+//
+func (c *clientGen) generateSelection(doc *operation.Document, name, descr, typ string, sels []operation.Selection) error {
+	fields, err := c.collectFields(doc, typ, typ, sels, false, nil)
+	if err != nil {
+		return err
+	}
+
+	g := c.g
+	g.P()
+	g.P("// ", name, " ", descr)
+	g.P("type ", name, " struct {")
+	g.In()
+	nested := make([]string, len(fields))
+	for i, f := range fields {
+		ftyp := graph.FieldType(f.def)
+		if nn, ok := ftyp.(*ast.NonNull); ok && f.optional {
+			ftyp = nonNullElem(nn)
+		}
+
+		goTyp := ""
+		named := operation.TypeName(ftyp)
+		if c.schema.IsLeaf(named) {
+			goTyp = c.ks.goType(ftyp, false)
+			c.useInput(named)
+		} else {
+			nested[i] = name + responseName(f.key)
+			goTyp = selectionType(ftyp, false, nested[i])
+		}
+
+		g.printDeprecated(f.def.Directives)
+		g.P(responseName(f.key), " ", goTyp, " `json:\"", f.key, "\"`")
+	}
+	g.Out()
+	g.P("}")
+
+	for i, f := range fields {
+		if nested[i] == "" {
+			continue
+		}
+
+		err = c.generateSelection(doc, nested[i], "is the selection of "+name+"."+responseName(f.key)+".", operation.TypeName(graph.FieldType(f.def)), f.sels)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// responseName returns the Go name of a response key, where
+// leading underscores are dropped e.g. __typename to Typename.
+//
+func responseName(key string) string {
+	return exportName(strings.TrimLeft(key, "_"))
+}
+
+// selectionType returns the Go type of a field selecting an object,
+// interface or union, whose selection set is the named struct. Since
+// selections can not reference themselves, only nullable fields
+// are pointers.
+//
+func selectionType(typ interface{}, nonNull bool, name string) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		if nonNull {
+			return name
+		}
+		return "*" + name
+	case *ast.List:
+		return "[]" + selectionType(listElem(v), false, name)
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return selectionType(w.Ident, true, name)
+		case *ast.NonNull_List:
+			return selectionType(w.List, true, name)
+		}
+	}
+	return "interface{}"
+}
+
+func nonNullElem(nn *ast.NonNull) interface{} {
+	switch v := nn.Type.(type) {
+	case *ast.NonNull_Ident:
+		return v.Ident
+	case *ast.NonNull_List:
+		return v.List
+	}
+	return nil
+}
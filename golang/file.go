@@ -0,0 +1,145 @@
+// file.go formats and writes the generated Go files
+
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"go.uber.org/zap"
+)
+
+// chunk is the span of the generated output declaring a type.
+type chunk struct {
+	name       string
+	start, end int
+}
+
+// splitFileName is the file split output writes everything, but types, to.
+const splitFileName = "schema.go"
+
+// chunkFileNames returns the file name of each chunk, which is the lower
+// cased type name. Since file names must be unique, types whose names only
+// differ in case or which are named after the schema file can't be split.
+//
+func chunkFileNames(chunks []chunk) ([]string, error) {
+	names := make([]string, len(chunks))
+	byName := map[string]string{splitFileName: ""}
+	for i, c := range chunks {
+		name := strings.ToLower(c.name) + ".go"
+		if other, ok := byName[name]; ok {
+			if other == "" {
+				return nil, fmt.Errorf("type %s can not be split into %s, since it contains everything else", c.name, name)
+			}
+			return nil, fmt.Errorf("types %s and %s can not both be split into %s", other, c.name, name)
+		}
+		byName[name] = c.name
+		names[i] = name
+	}
+	return names, nil
+}
+
+// writeFile formats the given body along with its header and writes
+// it to the named file. Only the imports used by body are kept.
+//
+func (g *Generator) writeFile(gCtx gen.GeneratorContext, name, pkg string, imports []string, body []byte) (err error) {
+	out, err := g.formatFile(name, generatedComment, pkg, imports, body)
+	if err != nil {
+		return
+	}
+
+	f, err := gCtx.Open(name)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = f.Write(out)
+	return
+}
+
+// writeScaffold writes a file like writeFile, which belongs to the user
+// once written, e.g. resolver stubs. An existing file is kept as is.
+//
+func (g *Generator) writeScaffold(gCtx gen.GeneratorContext, name, pkg string, imports []string, body []byte) (err error) {
+	out, err := g.formatFile(name, scaffoldComment, pkg, imports, body)
+	if err != nil {
+		return
+	}
+
+	f, err := gen.Create(gCtx, name)
+	if os.IsExist(err) {
+		g.log.Info("keeping existing file", zap.String("file", name))
+		return nil
+	}
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	_, err = f.Write(out)
+	return
+}
+
+// formatFile formats the given body along with its header.
+func (g *Generator) formatFile(name string, comment []byte, pkg string, imports []string, body []byte) ([]byte, error) {
+	used, err := usedPackages(body)
+	if err != nil {
+		return nil, fmt.Errorf("formatting %s: %s", name, err)
+	}
+
+	var src bytes.Buffer
+	g.writeHeader(&src, comment, []byte(pkg), filterImports(imports, used))
+	src.Write(body)
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting %s: %s", name, err)
+	}
+	return out, nil
+}
+
+// usedPackages returns the names of the packages referenced by
+// the given declarations, i.e. any unresolved selector operands.
+//
+func usedPackages(body []byte) (map[string]bool, error) {
+	src := append([]byte("package p\n\n"), body...)
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+			used[id.Name] = true
+		}
+		return true
+	})
+	return used, nil
+}
+
+// filterImports returns the imports, whose package names are used. Unless
+// an import is named, its package name is assumed to be the last element
+// of its path.
+//
+func filterImports(imports []string, used map[string]bool) (kept []string) {
+	for _, imp := range imports {
+		if name, _ := splitImport(imp); used[name] {
+			kept = append(kept, imp)
+		}
+	}
+	return
+}
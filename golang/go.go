@@ -6,12 +6,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/graph"
 	"github.com/gqlc/graphql/ast"
 	"github.com/gqlc/graphql/token"
 	"go.uber.org/zap"
@@ -24,6 +27,28 @@ type Options struct {
 
 	// Copy descriptions to Go
 	Descriptions bool
+
+	// Models generates Go structs for objects, interfaces and inputs, string
+	// types for enums, marker interfaces for unions and structs for the
+	// arguments of fields. Resolvers then use these models.
+	//
+	Models bool
+
+	// Split writes a file per type and a schema.go file, containing
+	// everything else, into a directory named after the document.
+	//
+	Split bool
+
+	// ImportPath is the import path of the Go package. Types imported
+	// from documents with another import path are referenced from
+	// their package, instead of being generated again.
+	//
+	ImportPath string
+
+	// Backend is the GraphQL server library the code is generated for, which
+	// is one of graphql-go, gqlgen or graph-gophers. (default: graphql-go)
+	//
+	Backend string
 }
 
 // Generator generates Go code for a GraphQL schema.
@@ -33,6 +58,22 @@ type Generator struct {
 
 	indent []byte
 	log    *zap.Logger
+
+	// cyclic are the types, whose fields are added by init functions
+	cyclic map[string]bool
+
+	// bindings are the scalars bound to Go types
+	bindings map[string]*binding
+	// externals are the types generated by the packages of imported documents
+	externals map[string]goPackage
+
+	// specs are the declared types by name, which default values are typed by
+	specs map[string]*ast.TypeSpec
+	// models is whether models are generated, so enums have Go constants
+	models bool
+
+	// batched are the fields with @batch, by their type and name e.g. User.friends
+	batched map[string]batchField
 }
 
 // Reset overrides the bytes.Buffer Reset method to assist in cleaning up some Generator state.
@@ -65,19 +106,66 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		g.log = zap.L().Named("golang").With(zap.String("doc", doc.Name))
 	}
 
-	// Get generator options
+	// Get generator options from the document as it was parsed, since
+	// the directives of its imports are merged into it when compiled
+	//
 	g.log.Info("getting options")
-	gOpts, oerr := getOptions(doc, opts)
+	gOpts, oerr := getOptions(sourceDoc(doc, gen.Sources(ctx)), opts)
 	if oerr != nil {
 		return oerr
 	}
 
-	// Generate package and imports
-	g.log.Info("writing header")
-	g.writeHeader(g, []byte(gOpts.Package))
+	// Generate for the backends building their schemas from SDL
+	gCtx := gen.Context(ctx)
+	goFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
+	switch gOpts.Backend {
+	case backendGqlgen:
+		return g.generateGqlgen(gCtx, doc, goFileName, gOpts)
+	case backendGophers:
+		return g.generateGophers(gCtx, doc, goFileName, gOpts)
+	}
+
+	// Find types generated by the packages of imported documents
+	g.externals, err = externalTypes(doc, gOpts.ImportPath, gen.DocSet(ctx), gen.Sources(ctx))
+	if err != nil {
+		return
+	}
+
+	// Collect imports, of which unused ones are removed once written
+	imports := []string{graphqlImport}
+	if gOpts.Models && hasArgs(doc.Types) {
+		imports = append(imports, "encoding/json")
+	}
+	g.bindings = bindings(doc.Types)
+	imports = append(imports, bindingImports(g.bindings)...)
+	imports = append(imports, externalImports(g.externals)...)
+	for name := range g.externals {
+		delete(g.bindings, name)
+	}
+
+	// Find the fields loaded by dataloaders, except the ones of imported types
+	var batches []batchField
+	allBatches, err := batchedFields(doc.Types)
+	if err != nil {
+		return
+	}
+	g.batched = make(map[string]batchField, len(allBatches))
+	for _, b := range allBatches {
+		if _, ok := g.externals[b.typeName]; !ok {
+			batches = append(batches, b)
+			g.batched[b.typeName+"."+b.field.Name.Name] = b
+		}
+	}
+	if len(batches) > 0 {
+		imports = append(imports, "context", "errors", "fmt", "sync")
+	}
 
 	// Generate types
 	g.log.Info("generating types")
+	g.cyclic = cyclicTypes(doc.Types)
+	g.specs = typeSpecs(doc.Types)
+	g.models = gOpts.Models
+	var chunks []chunk
 	totalTypes := len(doc.Types) - 1
 	for i, d := range doc.Types {
 		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
@@ -87,9 +175,13 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
 			continue
 		}
+		if _, ok = g.externals[ts.TypeSpec.Name.Name]; ok {
+			continue
+		}
 
 		// Generate variable declaration
 		name := ts.TypeSpec.Name.Name
+		start := g.Len()
 		g.WriteString("var")
 		g.WriteByte(' ')
 		g.WriteString(name)
@@ -111,7 +203,7 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		case *ast.TypeSpec_Union:
 			g.generateUnion(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
 		case *ast.TypeSpec_Enum:
-			g.generateEnum(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
+			g.generateEnum(name, gOpts.Descriptions, gOpts.Models, d.Doc, ts.TypeSpec)
 		case *ast.TypeSpec_Input:
 			g.generateInput(name, gOpts.Descriptions, d.Doc, ts.TypeSpec)
 		case *ast.TypeSpec_Directive:
@@ -121,39 +213,122 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 		if i != totalTypes {
 			g.P()
 		}
+		chunks = append(chunks, chunk{name: name, start: start, end: g.Len()})
 	}
+	rest := g.Len()
 
-	// Extract generator context
-	gCtx := gen.Context(ctx)
+	// Generate scalar serialization
+	g.generateBindings(doc.Types, g.bindings)
 
-	// Open file to write to
-	goFileName := doc.Name[:len(doc.Name)-len(filepath.Ext(doc.Name))]
-	goFile, err := gCtx.Open(goFileName + ".go")
-	defer goFile.Close()
-	if err != nil {
-		return
+	// Generate models
+	if gOpts.Models {
+		g.log.Info("generating models")
+		g.generateModels(doc.Types, gOpts.Descriptions)
 	}
 
+	// Generate resolver interfaces and schema constructor
+	g.log.Info("generating resolvers")
+	g.generateResolvers(doc.Types, gOpts.Models)
+
+	// Generate dataloaders
+	g.generateLoaders(doc.Types, batches, gOpts.Models)
+
 	// Write generated output
-	_, err = g.WriteTo(goFile)
-	return
+	g.log.Info("writing output")
+	if !gOpts.Split {
+		return g.writeFile(gCtx, goFileName+".go", gOpts.Package, imports, g.Bytes())
+	}
+
+	// Split the output into a file per type and the schema file
+	names, err := chunkFileNames(chunks)
+	if err != nil {
+		return
+	}
+	for i, c := range chunks {
+		err = g.writeFile(gCtx, filepath.Join(goFileName, names[i]), gOpts.Package, imports, g.Bytes()[c.start:c.end])
+		if err != nil {
+			return
+		}
+	}
+	return g.writeFile(gCtx, filepath.Join(goFileName, splitFileName), gOpts.Package, imports, g.Bytes()[rest:])
 }
 
+const graphqlImport = "github.com/graphql-go/graphql"
+
 var (
-	packagePrefix = []byte("package ")
-	importStmt    = []byte(`import "github.com/graphql-go/graphql"`)
-	newLines      = []byte{'\n', '\n'}
+	generatedComment = []byte("// Code generated by gqlc. DO NOT EDIT.")
+	scaffoldComment  = []byte("// This file was scaffolded by gqlc, which won't overwrite it, so implement your resolvers here.")
+	packagePrefix    = []byte("package ")
+	newLines         = []byte{'\n', '\n'}
 )
 
-func (g *Generator) writeHeader(w io.Writer, packageName []byte) {
+// writeHeader writes the given comment, the package clause and imports.
+// Standard library imports are grouped before any other imports.
+//
+func (g *Generator) writeHeader(w io.Writer, comment, packageName []byte, imports []string) {
+	w.Write(comment)
+	w.Write(newLines)
 	w.Write(packagePrefix)
 	w.Write(packageName)
 	w.Write(newLines)
 
-	w.Write(importStmt)
+	switch len(imports) {
+	case 0:
+		return
+	case 1:
+		io.WriteString(w, "import "+formatImport(imports[0]))
+		w.Write(newLines)
+		return
+	}
+
+	std, other := groupImports(imports)
+	io.WriteString(w, "import (\n")
+	for _, imp := range std {
+		io.WriteString(w, "\t"+formatImport(imp)+"\n")
+	}
+	if len(std) > 0 && len(other) > 0 {
+		io.WriteString(w, "\n")
+	}
+	for _, imp := range other {
+		io.WriteString(w, "\t"+formatImport(imp)+"\n")
+	}
+	io.WriteString(w, ")")
 	w.Write(newLines)
 }
 
+// groupImports sorts and dedupes the given imports
+// into standard library and other imports.
+//
+func groupImports(imports []string) (std, other []string) {
+	seen := make(map[string]bool, len(imports))
+	for _, imp := range imports {
+		if seen[imp] {
+			continue
+		}
+		seen[imp] = true
+
+		_, impPath := splitImport(imp)
+		if strings.Contains(strings.SplitN(impPath, "/", 2)[0], ".") {
+			other = append(other, imp)
+			continue
+		}
+		std = append(std, imp)
+	}
+
+	sort.Strings(std)
+	sort.Strings(other)
+	return
+}
+
+// formatImport formats an import as it's written in an import declaration.
+func formatImport(imp string) string {
+	name, impPath := splitImport(imp)
+	if name == path.Base(impPath) {
+		return strconv.Quote(impPath)
+	}
+	return name + " " + strconv.Quote(impPath)
+}
+
 func (g *Generator) generateScalar(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
 	g.P("NewScalar(graphql.ScalarConfig{")
 	g.In()
@@ -162,10 +337,17 @@ func (g *Generator) generateScalar(name string, descr bool, doc *ast.DocGroup, t
 	if doc != nil && descr {
 		text := doc.Text()
 		if len(text) > 0 {
-			g.P("Description: \"", text[:len(text)-1], "\",")
+			g.P("Description: ", strconv.Quote(text[:len(text)-1]), ",")
 		}
 	}
 
+	if g.bindings[name] != nil {
+		g.generateBinding(name)
+		g.Out()
+		g.P("})")
+		return
+	}
+
 	resolver := getResolver(ts.Directives)
 	if resolver == "" {
 		resolver = "func(value interface{}) interface{} { return nil }"
@@ -188,14 +370,7 @@ func (g *Generator) generateObject(name string, descr bool, doc *ast.DocGroup, t
 	// Print interfaces
 	interLen := len(obj.Interfaces)
 	if interLen == 1 {
-		g.Write(g.indent)
-		g.WriteString("Interfaces: []*graphql.Interface{ ")
-		g.WriteString(obj.Interfaces[0].Name)
-		g.Write(typeSuffix)
-		g.WriteByte(' ')
-		g.WriteByte('}')
-		g.WriteByte(',')
-		g.WriteByte('\n')
+		g.P("Interfaces: []*graphql.Interface{ ", g.typeRef(obj.Interfaces[0].Name), " },")
 	}
 	if interLen > 1 {
 		g.Write(g.indent)
@@ -204,21 +379,17 @@ func (g *Generator) generateObject(name string, descr bool, doc *ast.DocGroup, t
 		g.In()
 
 		for _, inter := range obj.Interfaces {
-			g.P(inter.Name, typeSuffix, ",")
+			g.P(g.typeRef(inter.Name), ",")
 		}
 
 		g.Out()
 		g.P("},")
 	}
 
-	g.P("Fields: graphql.Fields{")
-	g.In()
-
-	g.generateFields(obj.Fields, descr, true)
-
-	g.Out()
-
-	g.P("},")
+	if !g.openFields(name, "graphql.Fields") {
+		g.generateFields(name, "", obj.Fields, descr, true)
+		g.closeFields()
+	}
 
 	if doc != nil && descr {
 		g.printDescr(doc)
@@ -227,6 +398,12 @@ func (g *Generator) generateObject(name string, descr bool, doc *ast.DocGroup, t
 
 	g.Out()
 	g.P("})")
+
+	if g.cyclic[name] {
+		g.generateInit(name, func(add string) {
+			g.generateFields(name, add, obj.Fields, descr, true)
+		})
+	}
 }
 
 func (g *Generator) generateInterface(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
@@ -237,28 +414,85 @@ func (g *Generator) generateInterface(name string, descr bool, doc *ast.DocGroup
 
 	g.P("Name: \"", name, "\",")
 
-	g.P("Fields: graphql.Fields{")
-	g.In()
+	if !g.openFields(name, "graphql.Fields") {
+		g.generateFields(name, "", inter.Fields, descr, false)
+		g.closeFields()
+	}
 
-	g.generateFields(inter.Fields, descr, false)
+	if doc != nil && descr {
+		g.printDescr(doc)
+		g.WriteByte('\n')
+	}
 
 	g.Out()
+	g.P("})")
+
+	if g.cyclic[name] {
+		g.generateInit(name, func(add string) {
+			g.generateFields(name, add, inter.Fields, descr, false)
+		})
+	}
+}
 
+// openFields opens the fields of the named type. Cyclic types start
+// without fields, which are added by generateInit, since referencing
+// a type from the initializer of its own package variable, even in a
+// thunk, is an initialization cycle.
+//
+func (g *Generator) openFields(name, typ string) (deferred bool) {
+	if g.cyclic[name] {
+		g.P("Fields: ", typ, "{},")
+		return true
+	}
+
+	g.P("Fields: ", typ, "{")
+	g.In()
+	return false
+}
+
+// closeFields closes the fields opened by openFields.
+func (g *Generator) closeFields() {
+	g.Out()
 	g.P("},")
+}
 
-	if doc != nil && descr {
-		g.printDescr(doc)
-		g.WriteByte('\n')
+// generateInit generates an init function adding the fields of a cyclic type,
+// which runs once all package variables are initialized.
+//
+func (g *Generator) generateInit(name string, fields func(add string)) {
+	g.P()
+	g.P("func init() {")
+	g.In()
+	fields(name + string(typeSuffix))
+	g.Out()
+	g.P("}")
+}
+
+// openField opens the config of a field, which is either an entry of the fields
+// of its type or, if add names the variable of a cyclic type, added to it.
+//
+func (g *Generator) openField(add, name, config string) {
+	if add == "" {
+		g.P('"', name, '"', ": &", config, "{")
+	} else {
+		g.P(add, ".AddFieldConfig(\"", name, "\", &", config, "{")
 	}
+	g.In()
+}
 
+// closeField closes the config opened by openField.
+func (g *Generator) closeField(add string) {
 	g.Out()
+	if add == "" {
+		g.P("},")
+		return
+	}
 	g.P("})")
 }
 
-func (g *Generator) generateFields(fields *ast.FieldList, descr, resolve bool) {
+func (g *Generator) generateFields(typeName, add string, fields *ast.FieldList, descr, resolve bool) {
 	for _, f := range fields.List {
-		g.P('"', f.Name.Name, '"', ": &graphql.Field{")
-		g.In()
+		g.openField(add, f.Name.Name, "graphql.Field")
 
 		g.Write(g.indent)
 		g.WriteString("Type: ")
@@ -281,22 +515,24 @@ func (g *Generator) generateFields(fields *ast.FieldList, descr, resolve bool) {
 			g.Write([]byte(",\n"))
 		}
 
-		if resolve {
-			resolver := getResolver(f.Directives)
-			if resolver == "" {
-				resolver = "func(p graphql.ResolveParams) (interface{}, error) { return nil, nil }"
-			}
-
+		// Fields without a custom resolver are wired up by NewSchema,
+		// except batched ones, which are resolved by their dataloader
+		//
+		if resolver := getResolver(f.Directives); resolve && resolver != "" {
 			g.P("Resolve: ", resolver, ",")
 		}
+		if b, ok := g.batched[typeName+"."+f.Name.Name]; resolve && ok {
+			g.P("Resolve: ", batchResolver(b), ",")
+		}
+
+		g.generateDeprecation(f.Directives)
 
 		if f.Doc != nil && descr {
 			g.printDescr(f.Doc)
 			g.WriteByte('\n')
 		}
 
-		g.Out()
-		g.P("},")
+		g.closeField(add)
 	}
 }
 
@@ -311,27 +547,24 @@ func (g *Generator) generateUnion(name string, descr bool, doc *ast.DocGroup, ts
 	// Print members
 	memsLen := len(union.Members)
 	if memsLen == 1 {
-		g.P("Types: []*graphql.Object{ ", union.Members[0], typeSuffix, " },")
+		g.P("Types: []*graphql.Object{ ", g.typeRef(union.Members[0].Name), " },")
 	}
 	if memsLen > 1 {
 		g.P("Types: []*graphql.Object{")
 		g.In()
 
 		for _, mem := range union.Members {
-			g.P(mem.Name, typeSuffix, ',')
+			g.P(g.typeRef(mem.Name), ',')
 		}
 
 		g.Out()
 		g.P("},")
 	}
 
-	resolver := getResolver(ts.Directives)
-	if resolver == "" {
-		resolver = "func(p graphql.ResolveTypeParams) *graphql.Object { return nil }"
+	if resolver := getResolver(ts.Directives); resolver != "" {
+		g.P("ResolveType: ", resolver, ",")
 	}
 
-	g.P("ResolveType: ", resolver, ",")
-
 	if doc != nil && descr {
 		g.printDescr(doc)
 		g.WriteByte('\n')
@@ -341,7 +574,7 @@ func (g *Generator) generateUnion(name string, descr bool, doc *ast.DocGroup, ts
 	g.P("})")
 }
 
-func (g *Generator) generateEnum(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
+func (g *Generator) generateEnum(name string, descr, models bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
 	enum := ts.Type.(*ast.TypeSpec_Enum).Enum
 
 	g.P("NewEnum(graphql.EnumConfig{")
@@ -365,7 +598,14 @@ func (g *Generator) generateEnum(name string, descr bool, doc *ast.DocGroup, ts
 		if val == "" {
 			val = v.Name.Name
 		}
-		g.P("Value: \"", val, "\",")
+		// With models, values are the constants of the enum type
+		if models {
+			g.P("Value: ", name, enumName(v.Name.Name), ",")
+		} else {
+			g.P("Value: \"", val, "\",")
+		}
+
+		g.generateDeprecation(v.Directives)
 
 		if v.Doc != nil && descr {
 			g.printDescr(v.Doc)
@@ -392,12 +632,29 @@ func (g *Generator) generateInput(name string, descr bool, doc *ast.DocGroup, ts
 
 	g.P("Name: \"", name, "\",")
 
-	g.P("Fields: graphql.InputObjectConfigFieldMap{")
-	g.In()
+	if !g.openFields(name, "graphql.InputObjectConfigFieldMap") {
+		g.generateInputFields("", input.Fields.List, descr)
+		g.closeFields()
+	}
 
-	for _, f := range input.Fields.List {
-		g.P('"', f.Name.Name, '"', ": &graphql.InputObjectFieldConfig{")
-		g.In()
+	if doc != nil && descr {
+		g.printDescr(doc)
+		g.WriteByte('\n')
+	}
+
+	g.Out()
+	g.P("})")
+
+	if g.cyclic[name] {
+		g.generateInit(name, func(add string) {
+			g.generateInputFields(add, input.Fields.List, descr)
+		})
+	}
+}
+
+func (g *Generator) generateInputFields(add string, fields []*ast.InputValue, descr bool) {
+	for _, f := range fields {
+		g.openField(add, f.Name.Name, "graphql.InputObjectFieldConfig")
 		g.Write(g.indent)
 		g.WriteString("Type: ")
 
@@ -414,42 +671,15 @@ func (g *Generator) generateInput(name string, descr bool, doc *ast.DocGroup, ts
 		g.WriteByte(',')
 		g.WriteByte('\n')
 
-		if f.Default != nil {
-			g.Write(g.indent)
-			g.WriteString("DefaultValue: ")
-
-			var defType interface{}
-			switch v := f.Default.(type) {
-			case *ast.InputValue_BasicLit:
-				defType = v.BasicLit
-			case *ast.InputValue_CompositeLit:
-				defType = v.CompositeLit
-			}
-			g.printVal(defType)
-			g.WriteByte(',')
-			g.WriteByte('\n')
-		}
-
-		g.Out()
+		g.generateDefault(fieldType, f.Default)
 
 		if f.Doc != nil && descr {
 			g.printDescr(f.Doc)
 			g.WriteByte('\n')
 		}
 
-		g.P("},")
+		g.closeField(add)
 	}
-
-	g.Out()
-	g.P("},")
-
-	if doc != nil && descr {
-		g.printDescr(doc)
-		g.WriteByte('\n')
-	}
-
-	g.Out()
-	g.P("})")
 }
 
 func (g *Generator) generateDirective(name string, descr bool, doc *ast.DocGroup, ts *ast.TypeSpec) {
@@ -463,14 +693,14 @@ func (g *Generator) generateDirective(name string, descr bool, doc *ast.DocGroup
 	if doc != nil && descr {
 		text := doc.Text()
 		if len(text) > 0 {
-			g.P("Description: \"", text[:len(text)-1], "\",")
+			g.P("Description: ", strconv.Quote(text[:len(text)-1]), ",")
 		}
 	}
 
 	// Print locations
 	locsLen := len(directive.Locs)
 	if locsLen == 1 {
-		g.P("Locations: []string{ ", directive.Locs[0].Loc.String(), " }")
+		g.P("Locations: []string{ \"", directive.Locs[0].Loc.String(), "\" },")
 	}
 	if locsLen > 1 {
 		g.P("Locations: []string{")
@@ -517,21 +747,7 @@ func (g *Generator) generateArgs(args []*ast.InputValue, descr bool) {
 		g.WriteByte(',')
 		g.WriteByte('\n')
 
-		if a.Default != nil {
-			g.Write(g.indent)
-			g.WriteString("DefaultValue: ")
-
-			var defType interface{}
-			switch v := a.Default.(type) {
-			case *ast.InputValue_BasicLit:
-				defType = v.BasicLit
-			case *ast.InputValue_CompositeLit:
-				defType = v.CompositeLit
-			}
-			g.printVal(defType)
-			g.WriteByte(',')
-			g.WriteByte('\n')
-		}
+		g.generateDefault(fieldType, a.Default)
 
 		if a.Doc != nil && descr {
 			g.printDescr(a.Doc)
@@ -552,11 +768,10 @@ func (g *Generator) printDescr(doc *ast.DocGroup) {
 	text := doc.Text()
 	if len(text) > 0 {
 		g.Write(g.indent)
-		g.WriteString("Description: \"")
+		g.WriteString("Description: ")
 
-		g.WriteString(text[:len(text)-1])
+		g.WriteString(strconv.Quote(text[:len(text)-1]))
 
-		g.WriteByte('"')
 		g.WriteByte(',')
 	}
 }
@@ -579,7 +794,7 @@ func (g *Generator) printType(typ interface{}) {
 		case "ID":
 			name = "graphql.ID"
 		default:
-			name = name + "Type"
+			name = g.typeRef(name)
 		}
 
 		g.WriteString(name)
@@ -612,35 +827,126 @@ func (g *Generator) printType(typ interface{}) {
 	}
 }
 
-// printVal prints a value
-func (g *Generator) printVal(val interface{}) {
+// generateDefault generates the default value of an argument or input field
+// of the given type, as graphql-go passes it to resolvers. Enum values become
+// their values, lists []interface{} and input objects map[string]interface{}.
+//
+func (g *Generator) generateDefault(typ interface{}, def interface{}) {
+	var val interface{}
+	switch v := def.(type) {
+	case *ast.InputValue_BasicLit:
+		val = v.BasicLit
+	case *ast.InputValue_CompositeLit:
+		val = v.CompositeLit
+	default:
+		return
+	}
+
+	g.Write(g.indent)
+	g.WriteString("DefaultValue: ")
+	g.printVal(typ, val)
+	g.WriteByte(',')
+	g.WriteByte('\n')
+}
+
+// printVal prints a value of the given type
+func (g *Generator) printVal(typ interface{}, val interface{}) {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		switch w := nn.Type.(type) {
+		case *ast.NonNull_Ident:
+			typ = w.Ident
+		case *ast.NonNull_List:
+			typ = w.List
+		}
+	}
+
 	switch v := val.(type) {
 	case *ast.BasicLit:
-		isEnum := v.Kind == token.Token_IDENT
-		if isEnum {
-			g.WriteRune('"')
-		}
-		g.WriteString(v.Value)
-		if isEnum {
-			g.WriteRune('"')
+		// A single value is coerced to a list holding it
+		if l, ok := typ.(*ast.List); ok && v.Kind != token.Token_NULL {
+			g.WriteString("[]interface{}{")
+			g.printVal(listElem(l), v)
+			g.WriteByte('}')
+			return
 		}
+		g.printLit(typ, v)
 	case *ast.ListLit:
-		g.printList(v)
+		g.printList(typ, v)
 	case *ast.ObjLit:
-		g.printObject(v)
+		g.printObject(typ, v)
 	case *ast.CompositeLit:
 		switch w := v.Value.(type) {
 		case *ast.CompositeLit_BasicLit:
-			g.printVal(w.BasicLit)
+			g.printVal(typ, w.BasicLit)
 		case *ast.CompositeLit_ListLit:
-			g.printList(w.ListLit)
+			g.printList(typ, w.ListLit)
 		case *ast.CompositeLit_ObjLit:
-			g.printObject(w.ObjLit)
+			g.printObject(typ, w.ObjLit)
 		}
 	}
 }
 
-func (g *Generator) printList(v *ast.ListLit) {
+// printLit prints a literal coerced to its type, e.g. 1 to 1.0 for Float.
+func (g *Generator) printLit(typ interface{}, v *ast.BasicLit) {
+	var name string
+	if id, ok := typ.(*ast.Ident); ok {
+		name = id.Name
+	}
+
+	switch {
+	case v.Kind == token.Token_NULL:
+		g.WriteString("nil")
+	case v.Kind == token.Token_IDENT:
+		g.WriteString(g.enumValue(name, v.Value))
+	case v.Kind == token.Token_STRING:
+		g.WriteString(strconv.Quote(stringValue(v.Value)))
+	case v.Kind == token.Token_INT && name == "Float":
+		g.WriteString(v.Value + ".0")
+	case v.Kind == token.Token_INT && name == "ID":
+		g.WriteString(strconv.Quote(v.Value))
+	default:
+		g.WriteString(v.Value)
+	}
+}
+
+// enumValue returns the Go value of an enum value, which is its constant
+// with models or else the value given by @as. Values of unknown enums
+// are their names.
+//
+func (g *Generator) enumValue(enum, name string) string {
+	ts, ok := g.specs[enum]
+	if !ok {
+		return strconv.Quote(name)
+	}
+	e, ok := ts.Type.(*ast.TypeSpec_Enum)
+	if !ok || e.Enum.Values == nil {
+		return strconv.Quote(name)
+	}
+
+	for _, v := range e.Enum.Values.List {
+		if v.Name.Name != name {
+			continue
+		}
+
+		if g.models {
+			if p := g.externals[enum]; p.name != "" {
+				return p.name + "." + enum + enumName(name)
+			}
+			return enum + enumName(name)
+		}
+		if val := getValue(v.Directives); val != "" {
+			return strconv.Quote(val)
+		}
+		break
+	}
+	return strconv.Quote(name)
+}
+
+func (g *Generator) printList(typ interface{}, v *ast.ListLit) {
+	if l, ok := typ.(*ast.List); ok {
+		typ = listElem(l)
+	}
+
 	g.WriteString("[]interface{}{")
 
 	var vals []interface{}
@@ -657,7 +963,7 @@ func (g *Generator) printList(v *ast.ListLit) {
 
 	vLen := len(vals) - 1
 	for i, iv := range vals {
-		g.printVal(iv)
+		g.printVal(typ, iv)
 		if i != vLen {
 			g.WriteByte(',')
 			g.WriteByte(' ')
@@ -667,26 +973,62 @@ func (g *Generator) printList(v *ast.ListLit) {
 	g.WriteByte('}')
 }
 
-func (g *Generator) printObject(v *ast.ObjLit) {
-	g.WriteByte('{')
-	g.WriteByte(' ')
+func (g *Generator) printObject(typ interface{}, v *ast.ObjLit) {
+	var fields []*ast.InputValue
+	if ts, ok := g.specs[graph.BaseName(typ)]; ok {
+		if in, ok := ts.Type.(*ast.TypeSpec_Input); ok && in.Input.Fields != nil {
+			fields = in.Input.Fields.List
+		}
+	}
+
+	g.WriteString("map[string]interface{}{")
 
 	pLen := len(v.Fields) - 1
 	for i, p := range v.Fields {
-		g.WriteString(p.Key.Name)
+		g.WriteString(strconv.Quote(p.Key.Name))
 		g.WriteString(": ")
 
-		g.printVal(p.Val)
+		var fieldType interface{}
+		for _, f := range fields {
+			if f.Name.Name == p.Key.Name {
+				fieldType = graph.InputValueType(f)
+			}
+		}
+		g.printVal(fieldType, p.Val)
 
 		if i != pLen {
 			g.WriteByte(',')
+			g.WriteByte(' ')
 		}
-		g.WriteByte(' ')
 	}
 
 	g.WriteByte('}')
 }
 
+// listElem returns the element type of a list type.
+func listElem(l *ast.List) interface{} {
+	switch w := l.Type.(type) {
+	case *ast.List_Ident:
+		return w.Ident
+	case *ast.List_List:
+		return w.List
+	case *ast.List_NonNull:
+		return w.NonNull
+	}
+	return nil
+}
+
+// stringValue returns the value of a string or block string literal.
+func stringValue(lit string) string {
+	if strings.HasPrefix(lit, `"""`) && len(lit) >= 6 {
+		return lit[3 : len(lit)-3]
+	}
+	if s, err := strconv.Unquote(lit); err == nil {
+		return s
+	}
+	return strings.Trim(lit, "\"")
+}
+
 // P prints the arguments to the generated output.
 func (g *Generator) P(str ...interface{}) {
 	g.Write(g.indent)
@@ -729,6 +1071,7 @@ func (g *Generator) Out() {
 func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options, err error) {
 	gOpts = &Options{
 		Package: "main",
+		Backend: backendGraphQLGo,
 	}
 
 	// Extract document directive options
@@ -745,7 +1088,11 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 		for _, arg := range docOpts.Fields {
 			switch arg.Key.Name {
 			case "package":
-				gOpts.Package = arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value
+				gOpts.Package = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
+			case "importPath":
+				gOpts.ImportPath = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
+			case "backend":
+				gOpts.Backend = strings.Trim(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value, "\"")
 			case "descriptions":
 				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
 				if err != nil {
@@ -753,26 +1100,62 @@ func getOptions(doc *ast.Document, opts map[string]interface{}) (gOpts *Options,
 				}
 
 				gOpts.Descriptions = b
+			case "models":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Models = b
+			case "split":
+				b, err := strconv.ParseBool(arg.Val.Value.(*ast.CompositeLit_BasicLit).BasicLit.Value)
+				if err != nil {
+					return gOpts, err
+				}
+
+				gOpts.Split = b
 			}
 		}
 	}
 
 	// Unmarshal cli options
 	if opts == nil {
-		return
+		return gOpts, checkBackend(gOpts.Backend)
 	}
 	if p, ok := opts["package"]; ok {
-		gOpts.Package, _ = p.(string)
+		gOpts.Package = stringOpt(p)
 	}
 	if d, ok := opts["descriptions"]; ok {
 		gOpts.Descriptions, _ = d.(bool)
 	}
+	if m, ok := opts["models"]; ok {
+		gOpts.Models, _ = m.(bool)
+	}
+	if s, ok := opts["split"]; ok {
+		gOpts.Split, _ = s.(bool)
+	}
+	if i, ok := opts["importPath"]; ok {
+		gOpts.ImportPath = stringOpt(i)
+	}
+	if b, ok := opts["backend"]; ok {
+		gOpts.Backend = stringOpt(b)
+	}
+	return gOpts, checkBackend(gOpts.Backend)
+}
+
+// stringOpt returns a string cli option without the quotes it may be given in.
+func stringOpt(v interface{}) string {
+	s, _ := v.(string)
+	return strings.Trim(s, "\"")
+}
 
-	// Trim '"' from beginning and end of title string
-	if gOpts.Package[0] == '"' {
-		gOpts.Package = gOpts.Package[1 : len(gOpts.Package)-1]
+// checkBackend checks that a backend is known.
+func checkBackend(backend string) error {
+	switch backend {
+	case backendGraphQLGo, backendGqlgen, backendGophers:
+		return nil
 	}
-	return
+	return fmt.Errorf("unknown backend: %s", backend)
 }
 
 func getResolver(dirs []*ast.DirectiveLit) string {
@@ -786,6 +1169,55 @@ func getResolver(dirs []*ast.DirectiveLit) string {
 	return ""
 }
 
+// getBatch returns the key of a @batch directive.
+func getBatch(dirs []*ast.DirectiveLit) string {
+	for _, d := range dirs {
+		if d.Name != "batch" {
+			continue
+		}
+
+		return strings.Trim(d.Args.Args[0].Value.(*ast.Arg_BasicLit).BasicLit.Value, "\"")
+	}
+	return ""
+}
+
+// getDeprecation returns the reason given by a @deprecated
+// directive, which is empty without a reason argument.
+//
+func getDeprecation(dirs []*ast.DirectiveLit) (reason string, ok bool) {
+	for _, d := range dirs {
+		if d.Name != "deprecated" {
+			continue
+		}
+
+		if d.Args != nil {
+			for _, arg := range d.Args.Args {
+				if lit, isLit := arg.Value.(*ast.Arg_BasicLit); isLit && arg.Name.Name == "reason" {
+					reason = stringValue(lit.BasicLit.Value)
+				}
+			}
+		}
+		return reason, true
+	}
+	return "", false
+}
+
+// generateDeprecation sets the DeprecationReason of a field or enum value,
+// which defaults to the one of graphql-go without a reason.
+//
+func (g *Generator) generateDeprecation(dirs []*ast.DirectiveLit) {
+	reason, ok := getDeprecation(dirs)
+	if !ok {
+		return
+	}
+
+	if reason == "" {
+		g.P("DeprecationReason: graphql.DefaultDeprecationReason,")
+		return
+	}
+	g.P("DeprecationReason: ", strconv.Quote(reason), ",")
+}
+
 func getValue(dirs []*ast.DirectiveLit) string {
 	for _, d := range dirs {
 		if d.Name != "as" {
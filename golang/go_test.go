@@ -9,11 +9,13 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/operation"
 	"github.com/gqlc/graphql/ast"
 	"github.com/gqlc/graphql/parser"
 	"github.com/gqlc/graphql/token"
@@ -108,6 +110,74 @@ func TestScalar(t *testing.T) {
 	gen.CompareBytes(t, ex, g.Bytes())
 }
 
+func TestBindings(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`scalar DateTime @goType(name: "time.Time", package: "time")
+
+scalar JSON @goType(name: "map[string]interface{}", marshal: "marshalJSON", unmarshal: "unmarshalJSON")
+
+scalar Raw`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs := bindings(doc.Types)
+	if len(bs) != 2 || bs["DateTime"].pkg != "time" || bs["JSON"].unmarshal != "unmarshalJSON" {
+		t.Fatalf("unexpected bindings: %v", bs)
+	}
+
+	std, other := groupImports(append([]string{graphqlImport}, bindingImports(bs)...))
+	if strings.Join(std, ",") != "strconv,time" {
+		t.Errorf("unexpected standard imports: %v", std)
+	}
+	if strings.Join(other, ",") != "github.com/graphql-go/graphql,github.com/graphql-go/graphql/language/ast" {
+		t.Errorf("unexpected other imports: %v", other)
+	}
+
+	ks := typeKinds(doc.Types)
+	dt := &ast.Ident{Name: "DateTime"}
+	if typ := ks.goType(dt, false); typ != "*time.Time" {
+		t.Errorf("expected nullable DateTime to be *time.Time, but got: %s", typ)
+	}
+	if typ := ks.goType(&ast.NonNull{Type: &ast.NonNull_Ident{Ident: dt}}, true); typ != "time.Time" {
+		t.Errorf("expected non-null DateTime to be time.Time, but got: %s", typ)
+	}
+
+	g := &Generator{bindings: bs}
+	g.generateScalar("DateTime", false, nil, doc.Types[0].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
+
+	ex := []byte(`NewScalar(graphql.ScalarConfig{
+	Name: "DateTime",
+	Serialize: serializeDateTime,
+	ParseValue: parseDateTime,
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		return parseDateTime(literalValue(valueAST))
+	},
+})
+`)
+	gen.CompareBytes(t, ex, g.Bytes())
+
+	g.Reset()
+	g.generateBindings(doc.Types, bs)
+
+	for _, ex := range []string{
+		"func serializeDateTime(value interface{}) interface{} {",
+		"\tif p, ok := value.(*time.Time); ok {",
+		"\tb, err := v.MarshalText()",
+		"\tvar v time.Time\n\tif err := v.UnmarshalText([]byte(s)); err != nil {",
+		"\tv, ok := value.(map[string]interface{})",
+		"\treturn marshalJSON(v)",
+		"\tv, err := unmarshalJSON(value)",
+		"func literalValue(valueAST ast.Value) interface{} {",
+	} {
+		if !strings.Contains(g.String(), ex) {
+			t.Errorf("expected output to contain: %s\n%s", ex, g.Bytes())
+		}
+	}
+	if strings.Contains(g.String(), "Raw") {
+		t.Errorf("expected unbound scalar to be skipped:\n%s", g.Bytes())
+	}
+}
+
 func TestObject(t *testing.T) {
 	g := &Generator{}
 
@@ -168,15 +238,12 @@ func TestObject(t *testing.T) {
 	Fields: graphql.Fields{
 		"one": &graphql.Field{
 			Type: graphql.Int,
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 		},
 		"str": &graphql.Field{
 			Type: graphql.String,
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 		},
 		"list": &graphql.Field{
 			Type: graphql.NewList(TestType),
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 		},
 		"withDefaultVal": &graphql.Field{
 			Type: graphql.String,
@@ -186,7 +253,6 @@ func TestObject(t *testing.T) {
 					DefaultValue: "hello",
 				},
 			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 		},
 		"withEnumDefaultVal": &graphql.Field{
 			Type: graphql.String,
@@ -196,7 +262,6 @@ func TestObject(t *testing.T) {
 					DefaultValue: "A_ENUM_VALUE",
 				},
 			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 		},
 	},
 })
@@ -244,15 +309,12 @@ func TestObject(t *testing.T) {
 	Fields: graphql.Fields{
 		"one": &graphql.Field{
 			Type: graphql.Int,
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 		},
 		"str": &graphql.Field{
 			Type: graphql.String,
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 		},
 		"list": &graphql.Field{
 			Type: graphql.NewList(TestType),
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 		},
 	},
 })
@@ -365,7 +427,6 @@ func TestUnion(t *testing.T) {
 		AType,
 		BType,
 	},
-	ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object { return nil },
 })
 `)
 
@@ -387,7 +448,7 @@ func TestEnum(t *testing.T) {
 		},
 	}}
 
-	g.generateEnum("Test", false, nil, ts)
+	g.generateEnum("Test", false, false, nil, ts)
 
 	ex := []byte(`NewEnum(graphql.EnumConfig{
 	Name: "Test",
@@ -532,6 +593,104 @@ func TestInput(t *testing.T) {
 	})
 }
 
+func TestDefaults(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`enum Order {
+	ASC @as(value: "asc")
+	DESC
+}
+
+input Page {
+	order: Order
+	sizes: [Float!]
+}
+
+type Query {
+	users(order: Order = ASC, orders: [Order!] = [ASC, DESC], page: Page = {order: DESC, sizes: [1, 2.5]}, ids: [ID] = 1, after: String = null): String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		Name   string
+		Models bool
+		Ex     []string
+	}{
+		{
+			Name: "Values",
+			Ex: []string{
+				`DefaultValue: "asc",`,
+				`DefaultValue: []interface{}{"asc", "DESC"},`,
+				`DefaultValue: map[string]interface{}{"order": "DESC", "sizes": []interface{}{1.0, 2.5}},`,
+				`DefaultValue: []interface{}{"1"},`,
+				`DefaultValue: nil,`,
+			},
+		},
+		{
+			Name:   "Models",
+			Models: true,
+			Ex: []string{
+				`DefaultValue: OrderAsc,`,
+				`DefaultValue: []interface{}{OrderAsc, OrderDesc},`,
+				`DefaultValue: map[string]interface{}{"order": OrderDesc, "sizes": []interface{}{1.0, 2.5}},`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			var b bytes.Buffer
+			g := &Generator{}
+			ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+			err := g.Generate(ctx, doc, map[string]interface{}{"models": testCase.Models})
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			for _, ex := range testCase.Ex {
+				if !strings.Contains(b.String(), ex) {
+					subT.Errorf("expected output to contain: %s\n%s", ex, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`enum Color {
+	RED @deprecated
+	GREEN
+}
+
+type Query {
+	color: Color @deprecated(reason: "Use colors")
+	colors: [Color]
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	g := &Generator{}
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err = g.Generate(ctx, doc, map[string]interface{}{"models": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exs := []string{
+		"Value:             ColorRed,\n\t\t\tDeprecationReason: graphql.DefaultDeprecationReason,\n",
+		"Type:              ColorType,\n\t\t\tDeprecationReason: \"Use colors\",\n",
+		"\t// Deprecated: No longer supported\n\tColorRed ",
+		"\t// Deprecated: Use colors\n\tColor ",
+	}
+	for _, ex := range exs {
+		if !strings.Contains(b.String(), ex) {
+			t.Errorf("expected output to contain: %s\n%s", ex, b.String())
+		}
+	}
+}
+
 func TestDirective(t *testing.T) {
 	g := &Generator{}
 
@@ -637,6 +796,430 @@ func TestDirective(t *testing.T) {
 	})
 }
 
+func TestCyclicTypes(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`type User {
+	friends: [User!]
+	posts(filter: Filter): [Post]
+}
+
+type Post {
+	author: User
+}
+
+input Filter {
+	and: [Filter!]
+}
+
+type Version {
+	tag: String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cyclic := cyclicTypes(doc.Types)
+	for _, name := range []string{"User", "Post", "Filter"} {
+		if !cyclic[name] {
+			t.Errorf("expected %s to be cyclic", name)
+		}
+	}
+	if cyclic["Version"] {
+		t.Error("expected Version to not be cyclic")
+	}
+
+	g := &Generator{cyclic: cyclic}
+	g.generateObject("Post", false, nil, doc.Types[1].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
+	g.P()
+	g.generateInput("Filter", false, nil, doc.Types[2].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
+
+	ex := []byte(`NewObject(graphql.ObjectConfig{
+	Name: "Post",
+	Fields: graphql.Fields{},
+})
+
+func init() {
+	PostType.AddFieldConfig("author", &graphql.Field{
+		Type: UserType,
+	})
+}
+
+NewInputObject(graphql.InputObjectConfig{
+	Name: "Filter",
+	Fields: graphql.InputObjectConfigFieldMap{},
+})
+
+func init() {
+	FilterType.AddFieldConfig("and", &graphql.InputObjectFieldConfig{
+		Type: graphql.NewList(graphql.NewNonNull(FilterType)),
+	})
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+}
+
+// The releases of graphql-go and graph-gophers generated code is built against in tests.
+const (
+	graphqlGoModule = "github.com/graphql-go/graphql v0.8.1"
+	gophersModule   = "github.com/graph-gophers/graphql-go v1.5.0"
+)
+
+// runGo writes the given files into a temporary module requiring the given
+// modules and runs the go command with args in it. It skips the test, if
+// the go command or the required modules are unavailable.
+//
+func runGo(t *testing.T, files map[string]string, requires []string, args ...string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping go command in short mode")
+	}
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+
+	dir, err := ioutil.TempDir("", "gqlc-golang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mod := "module gqlctest\n\ngo 1.13\n"
+	for _, r := range requires {
+		mod += "\nrequire " + r + "\n"
+	}
+	files["go.mod"] = mod
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func(args ...string) ([]byte, error) {
+		cmd := exec.Command(goCmd, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+		return cmd.CombinedOutput()
+	}
+	if len(requires) > 0 {
+		if out, err := run("mod", "download"); err != nil {
+			t.Skipf("required modules are unavailable: %s", out)
+		}
+	}
+
+	out, err := run(args...)
+	if err != nil {
+		t.Fatalf("go %s failed: %s\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// generatedFiles returns the files written by a generator as strings.
+func generatedFiles(files filesCtx) map[string]string {
+	m := make(map[string]string, len(files))
+	for name, b := range files {
+		m[name] = b.String()
+	}
+	return m
+}
+
+func TestGenerator_BuildCyclic(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`type Query {
+	user(filter: Filter): User
+	node: Node
+}
+
+type User implements Node {
+	id: ID!
+	posts: [Post!]
+}
+
+type Post implements Node {
+	id: ID!
+	author: User
+}
+
+interface Node {
+	id: ID!
+	related: [Node!]
+}
+
+input Filter {
+	and: [Filter!]
+	not: Filter
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, models := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Models=%v", models), func(subT *testing.T) {
+			files := make(filesCtx)
+			g := &Generator{}
+			ctx := gen.WithContext(context.Background(), files)
+			err := g.Generate(ctx, doc, map[string]interface{}{"package": "schema", "models": models})
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			runGo(subT, generatedFiles(files), []string{graphqlGoModule}, "build", "./...")
+		})
+	}
+}
+
+func TestResolvers(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`type Query {
+	a: Int
+	b: Int @resolver(name: "resolveB")
+}
+
+type Mutation {
+	c: Int @resolver(name: "resolveC")
+}
+
+union U @resolver(name: "resolveU") = Query | Mutation`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{}
+	g.generateResolvers(doc.Types, false)
+
+	ex := []byte(`
+// QueryResolver resolves the fields of Query.
+type QueryResolver interface {
+	A(p graphql.ResolveParams) (interface{}, error)
+}
+
+// Resolvers are the resolvers of each type. A nil resolver keeps
+// the default resolution of graphql-go for the type in place.
+type Resolvers struct {
+	Query QueryResolver
+}
+
+// NewSchema wires the given resolvers into the types and builds the schema.
+func NewSchema(resolvers Resolvers) (graphql.Schema, error) {
+	if r := resolvers.Query; r != nil {
+		fields := QueryType.Fields()
+		fields["a"].Resolve = r.A
+	}
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: QueryType,
+		Mutation: MutationType,
+		Types: []graphql.Type{
+			QueryType,
+			MutationType,
+			UType,
+		},
+	})
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+
+	t.Run("WithModels", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`type Query {
+	echo(text: String!): String!
+}`), 0)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		g := &Generator{}
+		g.generateResolvers(doc.Types, true)
+
+		for _, ex := range []string{
+			"Echo(p graphql.ResolveParams, args QueryEchoArgs) (string, error)",
+			"args, err := DecodeQueryEchoArgs(p.Args)",
+			"return r.Echo(p, args)",
+		} {
+			if !bytes.Contains(g.Bytes(), []byte(ex)) {
+				subT.Errorf("expected output to contain: %s\n%s", ex, g.Bytes())
+			}
+		}
+	})
+
+	t.Run("MissingResolvers", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`type Query {
+	user: User
+}
+
+type User {
+	name: String
+}`), 0)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		files := make(filesCtx)
+		g := &Generator{}
+		ctx := gen.WithContext(context.Background(), files)
+		err = g.Generate(ctx, doc, map[string]interface{}{})
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		// Only Query is resolved, so User keeps the default resolution
+		src := generatedFiles(files)
+		src["main.go"] = `package main
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+type queryResolver struct{}
+
+func (queryResolver) User(p graphql.ResolveParams) (interface{}, error) {
+	return map[string]interface{}{"name": "gopher"}, nil
+}
+
+func main() {
+	schema, err := NewSchema(Resolvers{Query: queryResolver{}})
+	if err != nil {
+		panic(err)
+	}
+	res := graphql.Do(graphql.Params{Schema: schema, RequestString: "{ user { name } }"})
+	fmt.Print(res.Data)
+}
+`
+		out := runGo(subT, src, []string{graphqlGoModule}, "run", ".")
+		if ex := "map[user:map[name:gopher]]"; out != ex {
+			subT.Errorf("expected: %s, but got: %s", ex, out)
+		}
+	})
+}
+
+func TestExportName(t *testing.T) {
+	testCases := map[string]string{
+		"name":    "Name",
+		"id":      "ID",
+		"userId":  "UserID",
+		"url":     "URL",
+		"user_id": "User_ID",
+		"ids":     "Ids",
+	}
+
+	for name, ex := range testCases {
+		if out := exportName(name); out != ex {
+			t.Errorf("expected %s to be exported as %s, but got: %s", name, ex, out)
+		}
+	}
+}
+
+func TestModels(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`type Query {
+	user(id: ID!, dir: Direction = NORTH_EAST): User
+}
+
+type User {
+	id: ID!
+	friends: [User!]!
+	node: Node
+}
+
+interface Node {
+	id: ID!
+}
+
+union Result = User
+
+enum Direction {
+	NORTH_EAST
+	SOUTH @as(value: "S")
+}
+
+input Filter {
+	name: String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &Generator{}
+	g.generateModels(doc.Types, false)
+
+	ex := []byte(`
+type Query struct {
+	User *User ` + "`json:\"user\"`" + `
+}
+
+// QueryUserArgs are the arguments of Query.user.
+type QueryUserArgs struct {
+	ID string ` + "`json:\"id\"`" + `
+	Dir *Direction ` + "`json:\"dir\"`" + `
+}
+
+// DecodeQueryUserArgs decodes the arguments of Query.user e.g. p.Args.
+func DecodeQueryUserArgs(args map[string]interface{}) (a QueryUserArgs, err error) {
+	b, err := json.Marshal(args)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(b, &a)
+	return
+}
+
+type User struct {
+	ID string ` + "`json:\"id\"`" + `
+	Friends []*User ` + "`json:\"friends\"`" + `
+	Node interface{} ` + "`json:\"node\"`" + `
+}
+
+func (User) isResult() {}
+
+type Node struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+type Result interface {
+	isResult()
+}
+
+type Direction string
+
+const (
+	DirectionNorthEast Direction = "NORTH_EAST"
+	DirectionSouth Direction = "S"
+)
+
+type Filter struct {
+	Name *string ` + "`json:\"name\"`" + `
+}
+`)
+
+	gen.CompareBytes(t, ex, g.Bytes())
+
+	t.Run("WithSchema", func(subT *testing.T) {
+		doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(`schema {
+	query: Query
+}
+
+type Query {
+	version: String
+}`), 0)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		g := &Generator{}
+		g.generateModels(doc.Types, false)
+
+		ex := []byte(`
+type Query struct {
+	Version *string ` + "`json:\"version\"`" + `
+}
+`)
+
+		gen.CompareBytes(subT, ex, g.Bytes())
+	})
+}
+
 func TestGenerator_Generate(t *testing.T) {
 	g := &Generator{}
 
@@ -657,6 +1240,582 @@ func TestGenerator_Generate(t *testing.T) {
 	gen.CompareBytes(t, ex, b.Bytes())
 }
 
+type noopCloser struct {
+	io.Writer
+}
+
+func (noopCloser) Close() error { return nil }
+
+type filesCtx map[string]*bytes.Buffer
+
+func (ctx filesCtx) Open(name string) (io.WriteCloser, error) {
+	b := new(bytes.Buffer)
+	ctx[name] = b
+	return noopCloser{b}, nil
+}
+
+func (ctx filesCtx) Create(name string) (io.WriteCloser, error) {
+	if _, ok := ctx[name]; ok {
+		return nil, &os.PathError{Op: "create", Path: name, Err: os.ErrExist}
+	}
+	return ctx.Open(name)
+}
+
+func TestGenerator_Split(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`scalar DateTime @goType(name: "time.Time", package: "time")
+
+type Query {
+	now: DateTime
+	user: User
+}
+
+type User {
+	name: String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(filesCtx)
+	g := &Generator{}
+	ctx := gen.WithContext(context.Background(), files)
+	err = g.Generate(ctx, doc, map[string]interface{}{"split": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string][]string{
+		"test/datetime.go": {"import (\n\t\"github.com/graphql-go/graphql\"\n\t\"github.com/graphql-go/graphql/language/ast\"\n)", "var DateTimeType"},
+		"test/query.go":    {"import \"github.com/graphql-go/graphql\"\n", "var QueryType"},
+		"test/user.go":     {"import \"github.com/graphql-go/graphql\"\n", "var UserType"},
+		"test/schema.go":   {"\t\"strconv\"\n\t\"time\"\n\n\t\"github.com/graphql-go/graphql\"", "func serializeDateTime", "func NewSchema"},
+	}
+	if len(files) != len(testCases) {
+		t.Errorf("expected %d files, but got: %d", len(testCases), len(files))
+	}
+	for name, exs := range testCases {
+		b, ok := files[name]
+		if !ok {
+			t.Errorf("expected file: %s", name)
+			continue
+		}
+
+		if !strings.HasPrefix(b.String(), "// Code generated by gqlc. DO NOT EDIT.\n\npackage main\n\n") {
+			t.Errorf("expected generated code header in %s:\n%s", name, b)
+		}
+		for _, ex := range exs {
+			if !strings.Contains(b.String(), ex) {
+				t.Errorf("expected %s to contain: %s\n%s", name, ex, b)
+			}
+		}
+	}
+
+	t.Run("Clashes", func(subT *testing.T) {
+		testCases := map[string]struct {
+			Doc string
+			Err string
+		}{
+			"Schema": {
+				Doc: "type Query { schema: Schema }\ntype Schema { name: String }",
+				Err: "type Schema can not be split into schema.go, since it contains everything else",
+			},
+			"Case": {
+				Doc: "type Query { foo: Foo, bar: FOO }\ntype Foo { name: String }\ntype FOO { name: String }",
+				Err: "types Foo and FOO can not both be split into foo.go",
+			},
+		}
+
+		for name, testCase := range testCases {
+			doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(testCase.Doc), 0)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			files := make(filesCtx)
+			g := &Generator{}
+			ctx := gen.WithContext(context.Background(), files)
+			err = g.Generate(ctx, doc, map[string]interface{}{"split": true})
+			if err == nil || !strings.Contains(err.Error(), testCase.Err) {
+				subT.Errorf("%s: expected error: %s, but got: %v", name, testCase.Err, err)
+			}
+			if len(files) != 0 {
+				subT.Errorf("%s: expected no files to be written, but got: %d", name, len(files))
+			}
+		}
+	})
+}
+
+func TestGenerator_ImportPath(t *testing.T) {
+	dset := token.NewDocSet()
+	common, err := parser.ParseDoc(dset, "/imports/common.gql", strings.NewReader(`@go(options: {package: "common", importPath: "github.com/acme/common"})
+
+type PageInfo {
+	hasNext: Boolean
+}
+
+interface Node {
+	id: ID!
+}
+
+scalar Cursor`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.Name = "common"
+
+	shared, err := parser.ParseDoc(dset, "/imports/shared.gql", strings.NewReader(`@go(options: {importPath: "github.com/acme/svc"})
+
+scalar Version`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared.Name = "shared"
+
+	svc, err := parser.ParseDoc(dset, "/svc.gql", strings.NewReader(`@go(options: {importPath: "github.com/acme/svc"})
+
+type Query {
+	page(after: Cursor): PageInfo!
+	version: Version
+	item: Item
+}
+
+type Item implements Node {
+	id: ID!
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Name = "svc"
+
+	// Imported types are merged into the document
+	doc := &ast.Document{
+		Name:       "svc",
+		Directives: common.Directives,
+		Types:      append(append(append([]*ast.TypeDecl{}, common.Types...), shared.Types...), svc.Types...),
+	}
+
+	var b bytes.Buffer
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	ctx = gen.WithSources(gen.WithDocSet(ctx, dset), map[string]*ast.Document{
+		"/imports/common.gql": common,
+		"/imports/shared.gql": shared,
+		"/svc.gql":            svc,
+	})
+
+	g := &Generator{}
+	err = g.Generate(ctx, doc, map[string]interface{}{"models": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := b.String()
+	for _, ex := range []string{
+		"package main\n",
+		"\t\"github.com/acme/common\"\n",
+		"Type: graphql.NewNonNull(common.PageInfoType),",
+		"Type: common.CursorType,",
+		"Type: VersionType,",
+		"*common.PageInfo `json:\"page\"`",
+		"\t\tcommon.PageInfoType,\n",
+		"Interfaces: []*graphql.Interface{common.NodeType},",
+	} {
+		if !strings.Contains(out, ex) {
+			t.Errorf("expected output to contain: %s\n%s", ex, out)
+		}
+	}
+	for _, name := range []string{"var PageInfoType", "var NodeType", "var CursorType", "var VersionType", "type PageInfo struct"} {
+		if strings.Contains(out, name) {
+			t.Errorf("expected imported type to not be generated: %s\n%s", name, out)
+		}
+	}
+
+	p := goPackage{name: "common", path: "github.com/acme/common-go"}
+	if imp := formatImport(p.importSpec()); imp != `common "github.com/acme/common-go"` {
+		t.Errorf("expected named import, but got: %s", imp)
+	}
+}
+
+func TestGenerator_Batch(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`type Query {
+	users: [User!]!
+}
+
+type User {
+	id: ID!
+	team: String
+	posts: [Post!] @batch(key: "id")
+	teamSize: Int @batch(key: "team")
+}
+
+type Post {
+	title: String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(filesCtx)
+	g := &Generator{}
+	ctx := gen.WithContext(context.Background(), files)
+	err = g.Generate(ctx, doc, map[string]interface{}{"models": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := files["test.go"].String()
+	for _, ex := range []string{
+		"\t\"context\"\n\t\"errors\"\n\t\"fmt\"\n\t\"sync\"\n",
+		"Resolve: resolveUserPosts,",
+		"type UserPostsBatch func(ctx context.Context, keys []string) ([][]*Post, []error)",
+		"func (l *UserPostsLoader) Load(ctx context.Context, key string) func() (interface{}, error) {",
+		"return l.UserPosts.Load(p.Context, src.ID), nil",
+		"if src.Team == nil {\n\t\treturn nil, nil\n\t}\n\treturn l.UserTeamSize.Load(p.Context, *src.Team), nil",
+		"UserTeamSize(ctx context.Context, keys []string) ([]*int, []error)",
+		"func WithLoaders(ctx context.Context, b Batchers) context.Context {",
+		"func LoadersFrom(ctx context.Context) *Loaders {",
+	} {
+		if !strings.Contains(out, ex) {
+			t.Errorf("expected output to contain: %s\n%s", ex, out)
+		}
+	}
+	if strings.Contains(out, "\tPosts(p graphql.ResolveParams)") {
+		t.Errorf("expected batched fields to be left out of UserResolver:\n%s", out)
+	}
+
+	t.Run("WithoutModels", func(subT *testing.T) {
+		files := make(filesCtx)
+		g := &Generator{}
+		ctx := gen.WithContext(context.Background(), files)
+		err := g.Generate(ctx, doc, nil)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		out := files["test.go"].String()
+		for _, ex := range []string{
+			"UserPosts(ctx context.Context, keys []interface{}) ([]interface{}, []error)",
+			"Info:    graphql.ResolveInfo{FieldName: \"id\"},",
+			"return l.UserPosts.Load(p.Context, key), nil",
+		} {
+			if !strings.Contains(out, ex) {
+				subT.Errorf("expected output to contain: %s\n%s", ex, out)
+			}
+		}
+	})
+
+	testCases := map[string]struct {
+		doc string
+		err string
+	}{
+		"Resolver": {
+			doc: `type User { id: ID! posts: [String] @batch(key: "id") @resolver(name: "posts") }`,
+			err: "User.posts can not have both @batch and @resolver",
+		},
+		"Arguments": {
+			doc: `type User { id: ID! posts(first: Int): [String] @batch(key: "id") }`,
+			err: "User.posts can not be batched, since it has arguments",
+		},
+		"UnknownKey": {
+			doc: `type User { id: ID! posts: [String] @batch(key: "uuid") }`,
+			err: "@batch key uuid of User.posts is not a field of User",
+		},
+		"ListKey": {
+			doc: `type User { ids: [ID!] posts: [String] @batch(key: "ids") }`,
+			err: "@batch key ids of User.posts must be a scalar or enum",
+		},
+		"ObjectKey": {
+			doc: `type User { self: User posts: [String] @batch(key: "self") }`,
+			err: "@batch key self of User.posts must be a scalar or enum",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(subT *testing.T) {
+			doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(tc.doc), 0)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			g := &Generator{}
+			ctx := gen.WithContext(context.Background(), make(filesCtx))
+			err = g.Generate(ctx, doc, nil)
+			if err == nil || !strings.HasSuffix(err.Error(), tc.err) {
+				subT.Errorf("expected error: %s, but got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestSDL(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`"A date"
+scalar Date @goType(name: "time.Time", package: "time")
+
+type Query {
+	users(first: Int = 10, order: [Order!] = [ASC]): [User!]! @deprecated(reason: "Use search")
+}
+
+enum Order {
+	ASC
+	DESC
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	writeSDL(&b, doc.Types)
+
+	ex := `"A date"
+scalar Date
+
+type Query {
+	users(first: Int = 10, order: [Order!] = [ASC]): [User!]! @deprecated(reason: "Use search")
+}
+
+enum Order {
+	ASC
+	DESC
+}
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
+const backendDoc = `scalar Date @goType(name: "time.Time", package: "time")
+
+enum Color {
+	RED @as(value: "red")
+	GREEN
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	userId: String
+	color: Color!
+	born: Date
+	friends(first: Int): [User!]! @resolver(name: "friends")
+}
+
+type Query {
+	node(id: ID!): Node
+}`
+
+func TestGenerator_Gqlgen(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(backendDoc), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(filesCtx)
+	g := &Generator{}
+	ctx := gen.WithContext(context.Background(), files)
+	err = g.Generate(ctx, doc, map[string]interface{}{"backend": "gqlgen", "importPath": "github.com/acme/api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string][]string{
+		"test.graphql": {"scalar Date\n", "type User implements Node {"},
+		"gqlgen.yml": {
+			"schema:\n  - test.graphql\n",
+			"autobind:\n  - github.com/acme/api\n",
+			"  Date:\n    model: github.com/acme/api.Date\n",
+			"  User:\n    fields:\n      friends:\n        resolver: true\n",
+		},
+		"test.go": {
+			"func MarshalDate(v time.Time) graphql.Marshaler {",
+			"func UnmarshalDate(v interface{}) (time.Time, error) {",
+			"UserID *string",
+			"func (User) IsNode() {}",
+			"type Node interface {\n\tIsNode()\n}",
+			"ColorRed   Color = \"red\"",
+			"func (e Color) MarshalGQL(w io.Writer) {",
+			"func (e *Color) UnmarshalGQL(v interface{}) error {",
+		},
+		"resolver.go": {
+			"func (r *Resolver) Query() QueryResolver {",
+			"func (r *queryResolver) Node(ctx context.Context, id string) (Node, error) {\n\tpanic(\"not implemented\")",
+			"func (r *userResolver) Friends(ctx context.Context, obj *User, first *int) ([]*User, error) {\n\treturn friends(ctx, obj, first)",
+		},
+	}
+	if len(files) != len(testCases) {
+		t.Errorf("expected %d files, but got: %d", len(testCases), len(files))
+	}
+	for name, exs := range testCases {
+		b, ok := files[name]
+		if !ok {
+			t.Errorf("expected file: %s", name)
+			continue
+		}
+
+		for _, ex := range exs {
+			if !strings.Contains(b.String(), ex) {
+				t.Errorf("expected %s to contain: %s\n%s", name, ex, b)
+			}
+		}
+	}
+}
+
+func TestGenerator_Gophers(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(backendDoc), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(filesCtx)
+	g := &Generator{}
+	ctx := gen.WithContext(context.Background(), files)
+	err = g.Generate(ctx, doc, map[string]interface{}{"backend": "graph-gophers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string][]string{
+		"test.go": {
+			"graphql \"github.com/graph-gophers/graphql-go\"",
+			"const Schema = `scalar Date\n",
+			"func NewSchema(resolver *Resolver, opts ...graphql.SchemaOpt) (*graphql.Schema, error) {",
+			"type Date struct {\n\tValue time.Time\n}",
+			"func (s *Date) UnmarshalGraphQL(input interface{}) error {",
+			"func (Color) ImplementsGraphQLType(name string) bool { return name == \"Color\" }",
+			"func (r *NodeResolver) ToUser() (*UserResolver, bool) {",
+			"case *UserResolver:\n\t\treturn res.ID(ctx)",
+		},
+		"resolver.go": {
+			string(scaffoldComment),
+			"func (r *Resolver) Node(ctx context.Context, args struct{ ID graphql.ID }) (*NodeResolver, error) {",
+			"func (r *UserResolver) Color(ctx context.Context) (Color, error) {",
+			"func (r *UserResolver) Friends(ctx context.Context, args struct{ First *int32 }) ([]*UserResolver, error) {\n\treturn friends(ctx, r, args)",
+		},
+	}
+	if len(files) != len(testCases) {
+		t.Errorf("expected %d files, but got: %d", len(testCases), len(files))
+	}
+	for name, exs := range testCases {
+		b, ok := files[name]
+		if !ok {
+			t.Errorf("expected file: %s", name)
+			continue
+		}
+
+		for _, ex := range exs {
+			if !strings.Contains(b.String(), ex) {
+				t.Errorf("expected %s to contain: %s\n%s", name, ex, b)
+			}
+		}
+	}
+
+	t.Run("KeepResolvers", func(subT *testing.T) {
+		for _, backend := range []string{backendGqlgen, backendGophers} {
+			files := filesCtx{"resolver.go": bytes.NewBufferString("package main\n")}
+			g := &Generator{}
+			ctx := gen.WithContext(context.Background(), files)
+			err := g.Generate(ctx, doc, map[string]interface{}{"backend": backend, "importPath": "github.com/acme/svc"})
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if b := files["resolver.go"].String(); b != "package main\n" {
+				subT.Errorf("expected %s to keep the existing resolver.go, but got:\n%s", backend, b)
+			}
+		}
+	})
+}
+
+func TestGenerator_GophersDefaults(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(`type Query {
+	users(filter: Filter): [String!]
+	count(role: Role = ADMIN): Int
+}
+
+enum Role {
+	ADMIN
+	USER
+}
+
+input Filter {
+	role: Role = ADMIN
+	roles: [Role!] = [USER]
+	name: String
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(filesCtx)
+	g := &Generator{}
+	ctx := gen.WithContext(context.Background(), files)
+	err = g.Generate(ctx, doc, map[string]interface{}{"backend": "graph-gophers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := "type Filter struct {\n\tRole  Role\n\tRoles []Role\n\tName  *string\n}"
+	if b := files["test.go"].String(); !strings.Contains(b, ex) {
+		t.Errorf("expected test.go to contain: %s\n%s", ex, b)
+	}
+	ex = "args struct{ Role Role }"
+	if b := files["resolver.go"].String(); !strings.Contains(b, ex) {
+		t.Errorf("expected resolver.go to contain: %s\n%s", ex, b)
+	}
+
+	src := generatedFiles(files)
+	src["main.go"] = `package main
+
+func main() {
+	if _, err := NewSchema(&Resolver{}); err != nil {
+		panic(err)
+	}
+}
+`
+	runGo(t, src, []string{gophersModule}, "run", ".")
+}
+
+func TestGenerator_BackendErrors(t *testing.T) {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test.gql", strings.NewReader(backendDoc), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		opts map[string]interface{}
+		err  string
+	}{
+		"Unknown": {
+			opts: map[string]interface{}{"backend": "graphql-js"},
+			err:  "unknown backend: graphql-js",
+		},
+		"NoImportPath": {
+			opts: map[string]interface{}{"backend": "gqlgen"},
+			err:  "the gqlgen backend requires an importPath",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(subT *testing.T) {
+			g := &Generator{}
+			ctx := gen.WithContext(context.Background(), make(filesCtx))
+			err := g.Generate(ctx, doc, tc.opts)
+			if err == nil || !strings.HasSuffix(err.Error(), tc.err) {
+				subT.Errorf("expected error: %s, but got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestGenerator_FormatError(t *testing.T) {
+	g := &Generator{}
+	err := g.writeFile(make(filesCtx), "test.go", "main", []string{graphqlImport}, []byte("var QueryType = graphql.NewObject("))
+	if err == nil || !strings.HasPrefix(err.Error(), "formatting test.go: ") {
+		t.Errorf("expected formatting error, but got: %v", err)
+	}
+}
+
 func BenchmarkGenerator_Generate(b *testing.B) {
 	g := &Generator{}
 
@@ -702,6 +1861,8 @@ type Query {
 	fmt.Println(b.String())
 
 	// Output:
+	// // Code generated by gqlc. DO NOT EDIT.
+	//
 	// package main
 	//
 	// import "github.com/graphql-go/graphql"
@@ -711,10 +1872,158 @@ type Query {
 	//	Fields: graphql.Fields{
 	//		"hello": &graphql.Field{
 	//			Type: graphql.String,
-	//			Resolve: func(p graphql.ResolveParams) (interface{}, error) { return nil, nil },
 	//		},
 	//	},
 	//	Description: "Query represents the queries this example provides.",
 	// })
 	//
+	// // QueryResolver resolves the fields of Query.
+	// type QueryResolver interface {
+	// 	Hello(p graphql.ResolveParams) (interface{}, error)
+	// }
+	//
+	// // Resolvers are the resolvers of each type. A nil resolver keeps
+	// // the default resolution of graphql-go for the type in place.
+	// type Resolvers struct {
+	// 	Query QueryResolver
+	// }
+	//
+	// // NewSchema wires the given resolvers into the types and builds the schema.
+	// func NewSchema(resolvers Resolvers) (graphql.Schema, error) {
+	// 	if r := resolvers.Query; r != nil {
+	// 		fields := QueryType.Fields()
+	// 		fields["hello"].Resolve = r.Hello
+	// 	}
+	// 	return graphql.NewSchema(graphql.SchemaConfig{
+	// 		Query: QueryType,
+	// 		Types: []graphql.Type{
+	// 			QueryType,
+	// 		},
+	// 	})
+	// }
+	//
+}
+
+func TestClientGenerator(t *testing.T) {
+	schema, err := parser.ParseDoc(token.NewDocSet(), "schema.gql", strings.NewReader(`scalar Time @goType(name: "time.Time", package: "time")
+
+type Query {
+	user(id: ID!): User
+	search(text: String!): [Result!]!
+}
+
+type User {
+	id: ID!
+	name: String
+	createdAt: Time!
+	friends(kind: Kind): [User!]
+}
+
+type Post {
+	title: String! @deprecated
+}
+
+union Result = User | Post
+
+enum Kind {
+	CLOSE @as(value: "close")
+	OTHER
+}
+
+input Filter {
+	kinds: [Kind!]
+}`), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ops, err := operation.Parse("users.graphql", []byte(`query GetUser($id: ID!, $kind: Kind) {
+	user(id: $id) {
+		...UserFields
+		friends(kind: $kind) @include(if: true) { id }
+	}
+}
+
+query Search {
+	search(text: "gqlc") {
+		__typename
+		... on Post { title }
+	}
+}
+
+fragment UserFields on User { id handle: name createdAt }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops.Name = "users"
+
+	files := make(filesCtx)
+	ctx := operation.WithDocuments(gen.WithContext(context.Background(), files), []*operation.Document{ops})
+	g := &ClientGenerator{}
+	err = g.GenerateDocs(ctx, []*ast.Document{schema}, map[string]interface{}{"package": "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string][]string{
+		"client.go": {
+			"package api\n",
+			"type Client struct {",
+			"func (c *Client) Do(ctx context.Context, query, operationName string, variables, data interface{}) error {",
+		},
+		"users.go": {
+			"const GetUserQuery = `query GetUser($id: ID!, $kind: Kind) {",
+			"}\n\nfragment UserFields on User { id handle: name createdAt }`",
+			"type GetUserVariables struct {\n\tID   string `json:\"id\"`\n\tKind *Kind  `json:\"kind,omitempty\"`\n}",
+			"User *GetUserResponseUser `json:\"user\"`",
+			"Handle    *string                      `json:\"handle\"`",
+			"CreatedAt time.Time                    `json:\"createdAt\"`",
+			"Friends   []GetUserResponseUserFriends `json:\"friends\"`",
+			"func (c *Client) GetUser(ctx context.Context, vars GetUserVariables) (*GetUserResponse, error) {",
+			"err := c.Do(ctx, GetUserQuery, \"GetUser\", vars, &data)",
+			"Search []SearchResponseSearch `json:\"search\"`",
+			"Typename string `json:\"__typename\"`\n\t// Deprecated: No longer supported\n\tTitle *string `json:\"title\"`",
+			"func (c *Client) Search(ctx context.Context) (*SearchResponse, error) {",
+			"err := c.Do(ctx, SearchQuery, \"Search\", nil, &data)",
+		},
+		"types.go": {
+			"KindClose Kind = \"close\"",
+			"func (e *Kind) UnmarshalJSON(b []byte) error {",
+		},
+	}
+	if len(files) != len(testCases) {
+		t.Errorf("expected %d files, but got: %d", len(testCases), len(files))
+	}
+	for name, exs := range testCases {
+		b, ok := files[name]
+		if !ok {
+			t.Errorf("expected file: %s", name)
+			continue
+		}
+
+		for _, ex := range exs {
+			if !strings.Contains(b.String(), ex) {
+				t.Errorf("expected %s to contain: %s\n%s", name, ex, b)
+			}
+		}
+	}
+	if strings.Contains(files["types.go"].String(), "Filter") {
+		t.Errorf("expected unused input to not be generated\n%s", files["types.go"])
+	}
+
+	for name, src := range map[string]string{
+		"requires named operations":      `{ user(id: 1) { id } }`,
+		"does not support subscriptions": `subscription S { user(id: 1) { id } }`,
+	} {
+		ops, err := operation.Parse("bad.graphql", []byte(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx := operation.WithDocuments(gen.WithContext(context.Background(), make(filesCtx)), []*operation.Document{ops})
+		err = g.GenerateDocs(ctx, []*ast.Document{schema}, nil)
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("expected error %q, but got: %v", name, err)
+		}
+	}
 }
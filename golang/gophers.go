@@ -0,0 +1,431 @@
+// gophers.go generates the schema and a resolver skeleton for graph-gophers/graphql-go
+
+package golang
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+)
+
+const gophersImport = "graphql github.com/graph-gophers/graphql-go"
+
+var gophersTypes = map[string]string{
+	"Int":     "int32",
+	"Float":   "float64",
+	"String":  "string",
+	"Boolean": "bool",
+	"ID":      "graphql.ID",
+}
+
+// gophersType returns the Go type graph-gophers resolves a GraphQL type
+// to. Nullable types are pointers, while objects, interfaces and unions
+// are always resolved by a pointer to their resolver.
+//
+func (ks kinds) gophersType(typ interface{}, nonNull bool) string {
+	ptr := "*"
+	if nonNull {
+		ptr = ""
+	}
+
+	switch v := typ.(type) {
+	case *ast.Ident:
+		if t, ok := gophersTypes[v.Name]; ok {
+			return ptr + t
+		}
+
+		switch ks[v.Name].(type) {
+		case *ast.TypeSpec_Object, *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			return "*" + v.Name + "Resolver"
+		}
+		return ptr + v.Name
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return ptr + "[]" + ks.gophersType(w.Ident, false)
+		case *ast.List_List:
+			return ptr + "[]" + ks.gophersType(w.List, false)
+		case *ast.List_NonNull:
+			return ptr + "[]" + ks.gophersType(w.NonNull, false)
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return ks.gophersType(w.Ident, true)
+		case *ast.NonNull_List:
+			return ks.gophersType(w.List, true)
+		}
+	}
+	return "interface{}"
+}
+
+// gophersInputType returns the Go type of an argument or input field.
+// Since graph-gophers treats inputs with a default value as non-null,
+// they aren't pointers.
+//
+func (ks kinds) gophersInputType(v *ast.InputValue) string {
+	return ks.gophersType(graph.InputValueType(v), v.Default != nil)
+}
+
+// generateGophers generates the schema, its constructor and types of
+// a document for graph-gophers, along with a skeleton of the root
+// Resolver and the resolvers of each object, which is only scaffolded
+// once, since users implement it.
+//
+func (g *Generator) generateGophers(gCtx gen.GeneratorContext, doc *ast.Document, baseName string, opts *Options) (err error) {
+	g.bindings = bindings(doc.Types)
+	roots := rootTypes(doc.Types)
+	ks := typeKinds(doc.Types)
+
+	g.log.Info("generating schema")
+	var sdl bytes.Buffer
+	writeSDL(&sdl, doc.Types)
+
+	g.P("// Schema is the schema of the document.")
+	if bytes.IndexByte(sdl.Bytes(), '`') < 0 {
+		g.P("const Schema = `", sdl.Bytes(), "`")
+	} else {
+		g.P("const Schema = ", strconv.Quote(sdl.String()))
+	}
+	g.P()
+	g.P("// NewSchema parses the schema with the given root resolver.")
+	g.P("func NewSchema(resolver *Resolver, opts ...graphql.SchemaOpt) (*graphql.Schema, error) {")
+	g.In()
+	g.P("return graphql.ParseSchema(Schema, resolver, opts...)")
+	g.Out()
+	g.P("}")
+
+	g.log.Info("generating types")
+	g.generateGophersTypes(ks, doc.Types, opts.Descriptions)
+
+	imports := append([]string{"context", "encoding/json", "fmt", gophersImport}, bindingPackages(g.bindings)...)
+	err = g.writeFile(gCtx, baseName+".go", opts.Package, imports, g.Bytes())
+	if err != nil {
+		return
+	}
+
+	g.log.Info("generating resolvers")
+	g.Reset()
+	g.generateGophersResolvers(ks, doc.Types, roots)
+	return g.writeScaffold(gCtx, "resolver.go", opts.Package, []string{"context", gophersImport}, g.Bytes())
+}
+
+// generateGophersTypes generates the Go types of scalars, enums and
+// inputs, as well as the resolvers of interfaces and unions, which
+// resolve to the resolvers of their implementations.
+//
+func (g *Generator) generateGophersTypes(ks kinds, decls []*ast.TypeDecl, descr bool) {
+	// Interfaces and unions resolve to the objects implementing them
+	impls := make(map[string][]*ast.TypeSpec)
+	objs := make(map[string]*ast.TypeSpec)
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			objs[ts.TypeSpec.Name.Name] = ts.TypeSpec
+			for _, i := range v.Object.Interfaces {
+				impls[i.Name] = append(impls[i.Name], ts.TypeSpec)
+			}
+		}
+	}
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		if u, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Union); ok {
+			for _, m := range u.Union.Members {
+				if obj, ok := objs[m.Name]; ok {
+					impls[ts.TypeSpec.Name.Name] = append(impls[ts.TypeSpec.Name.Name], obj)
+				}
+			}
+		}
+	}
+
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.generateGophersScalar(name, descr, d.Doc, g.bindings[name], getResolver(ts.TypeSpec.Directives))
+		case *ast.TypeSpec_Enum:
+			g.generateEnumType(name, "UnmarshalGraphQL", descr, d.Doc, v.Enum)
+
+			g.P()
+			g.P("// ImplementsGraphQLType reports whether name is ", name, ".")
+			g.P("func (", name, ") ImplementsGraphQLType(name string) bool { return name == \"", name, "\" }")
+		case *ast.TypeSpec_Input:
+			g.P()
+			g.printComment(descr, d.Doc)
+			g.P("type ", name, " struct {")
+			g.In()
+			if v.Input.Fields != nil {
+				for _, f := range v.Input.Fields.List {
+					g.P(exportName(f.Name.Name), " ", ks.gophersInputType(f))
+				}
+			}
+			g.Out()
+			g.P("}")
+		case *ast.TypeSpec_Interface:
+			g.generateGophersAbstract(ks, name, descr, d.Doc, v.Interface.Fields, impls[name])
+		case *ast.TypeSpec_Union:
+			g.generateGophersAbstract(ks, name, descr, d.Doc, nil, impls[name])
+		}
+	}
+}
+
+// generateGophersScalar generates the Go type of a scalar, which holds its
+// value. Bound scalars hold their Go type, while others hold interface{}
+// and are serialized by their @resolver function, if any.
+//
+func (g *Generator) generateGophersScalar(name string, descr bool, doc *ast.DocGroup, b *binding, resolver string) {
+	typ := "interface{}"
+	if b != nil {
+		typ = b.typ
+	}
+
+	g.P()
+	g.printComment(descr, doc)
+	g.P("type ", name, " struct {")
+	g.In()
+	g.P("Value ", typ)
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// ImplementsGraphQLType reports whether name is ", name, ".")
+	g.P("func (", name, ") ImplementsGraphQLType(name string) bool { return name == \"", name, "\" }")
+
+	g.P()
+	g.P("// UnmarshalGraphQL sets the value of s from the given input.")
+	g.P("func (s *", name, ") UnmarshalGraphQL(input interface{}) error {")
+	g.In()
+	switch {
+	case b != nil && b.unmarshal != "":
+		g.P("v, err := ", b.unmarshal, "(input)")
+		g.P("if err != nil {")
+		g.In()
+		g.P("return err")
+		g.Out()
+		g.P("}")
+		g.P("s.Value = v")
+		g.P("return nil")
+	case b != nil:
+		g.P("text, ok := input.(string)")
+		g.P("if !ok {")
+		g.In()
+		g.P("return fmt.Errorf(\"", name, " must be a string\")")
+		g.Out()
+		g.P("}")
+		g.P("return s.Value.UnmarshalText([]byte(text))")
+	default:
+		g.P("s.Value = input")
+		g.P("return nil")
+	}
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// MarshalJSON marshals the value of s.")
+	g.P("func (s ", name, ") MarshalJSON() ([]byte, error) {")
+	g.In()
+	switch {
+	case b != nil && b.marshal != "":
+		g.P("return json.Marshal(", b.marshal, "(s.Value))")
+	case b != nil:
+		g.P("text, err := s.Value.MarshalText()")
+		g.P("if err != nil {")
+		g.In()
+		g.P("return nil, err")
+		g.Out()
+		g.P("}")
+		g.P("return json.Marshal(string(text))")
+	case resolver != "":
+		g.P("return json.Marshal(", resolver, "(s.Value))")
+	default:
+		g.P("return json.Marshal(s.Value)")
+	}
+	g.Out()
+	g.P("}")
+}
+
+// generateGophersAbstract generates the resolver of an interface or union,
+// which holds the resolver of an implementation. The fields of interfaces
+// are resolved by the implementation, if it declares them alike.
+//
+func (g *Generator) generateGophersAbstract(ks kinds, name string, descr bool, doc *ast.DocGroup, fields *ast.FieldList, impls []*ast.TypeSpec) {
+	g.P()
+	g.printComment(descr, doc)
+	g.P("type ", name, "Resolver struct {")
+	g.In()
+	g.P("Result interface{}")
+	g.Out()
+	g.P("}")
+
+	for _, impl := range impls {
+		g.P()
+		g.P("// To", impl.Name.Name, " returns the result, if it's a ", impl.Name.Name, ".")
+		g.P("func (r *", name, "Resolver) To", impl.Name.Name, "() (*", impl.Name.Name, "Resolver, bool) {")
+		g.In()
+		g.P("res, ok := r.Result.(*", impl.Name.Name, "Resolver)")
+		g.P("return res, ok")
+		g.Out()
+		g.P("}")
+	}
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		params, call := gophersParams(ks, f), "ctx"
+		if f.Args != nil && len(f.Args.List) > 0 {
+			call += ", args"
+		}
+		result := ks.gophersType(graph.FieldType(f), false)
+
+		g.P()
+		g.P("func (r *", name, "Resolver) ", exportName(f.Name.Name), "(", params, ") (v ", result, ", err error) {")
+		g.In()
+		g.P("switch res := r.Result.(type) {")
+		for _, impl := range impls {
+			implField := findField(impl.Type.(*ast.TypeSpec_Object).Object.Fields, f.Name.Name)
+			if implField == nil || ks.gophersType(graph.FieldType(implField), false) != result {
+				continue
+			}
+			if gophersParams(ks, implField) != params {
+				continue
+			}
+
+			g.P("case *", impl.Name.Name, "Resolver:")
+			g.In()
+			g.P("return res.", exportName(f.Name.Name), "(", call, ")")
+			g.Out()
+		}
+		g.P("}")
+		g.P("err = fmt.Errorf(\"%T does not resolve ", name, ".", f.Name.Name, "\", r.Result)")
+		g.P("return")
+		g.Out()
+		g.P("}")
+	}
+}
+
+// gophersParams returns the parameters of the method resolving a field.
+// The arguments of a field are passed as an anonymous struct, so the
+// resolvers of interfaces may pass them on to their implementations.
+//
+func gophersParams(ks kinds, f *ast.Field) string {
+	if f.Args == nil || len(f.Args.List) == 0 {
+		return "ctx context.Context"
+	}
+
+	var fields []string
+	for _, a := range f.Args.List {
+		fields = append(fields, exportName(a.Name.Name)+" "+ks.gophersInputType(a))
+	}
+	return "ctx context.Context, args struct{ " + strings.Join(fields, "; ") + " }"
+}
+
+// findField returns the named field or nil.
+func findField(fields *ast.FieldList, name string) *ast.Field {
+	if fields == nil {
+		return nil
+	}
+
+	for _, f := range fields.List {
+		if f.Name.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// generateGophersResolvers generates the root Resolver, which resolves the
+// fields of the root types, and a resolver for each other object. Fields
+// with @resolver call the named function with the context, the resolver,
+// unless it's the root one, and the arguments, while all others panic.
+//
+func (g *Generator) generateGophersResolvers(ks kinds, decls []*ast.TypeDecl, roots map[string]string) {
+	g.P("// Resolver is the root resolver of the schema. Its resolvers are")
+	g.P("// stubs, which either panic or call the functions named by @resolver.")
+	g.P("type Resolver struct{}")
+	for _, op := range []string{"query", "mutation", "subscription"} {
+		ts := findType(decls, roots[op])
+		if ts == nil {
+			continue
+		}
+
+		g.generateGophersMethods(ks, "Resolver", true, op == "subscription", ts.Type.(*ast.TypeSpec_Object).Object.Fields)
+	}
+
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object)
+		if !ok || isRootType(roots, ts.TypeSpec.Name.Name) {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		g.P()
+		g.P("// ", name, "Resolver resolves the fields of ", name, ".")
+		g.P("type ", name, "Resolver struct{}")
+		g.generateGophersMethods(ks, name+"Resolver", false, false, obj.Object.Fields)
+	}
+}
+
+func (g *Generator) generateGophersMethods(ks kinds, recv string, root, subscription bool, fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		params, call := gophersParams(ks, f), "ctx"
+		if !root {
+			call += ", r"
+		}
+		if f.Args != nil && len(f.Args.List) > 0 {
+			call += ", args"
+		}
+		result := ks.gophersType(graph.FieldType(f), false)
+		if subscription {
+			result = "<-chan " + result
+		}
+
+		g.P()
+		g.P("func (r *", recv, ") ", exportName(f.Name.Name), "(", params, ") (", result, ", error) {")
+		g.In()
+		if resolver := getResolver(f.Directives); resolver != "" {
+			g.P("return ", resolver, "(", call, ")")
+		} else {
+			g.P("panic(\"not implemented\")")
+		}
+		g.Out()
+		g.P("}")
+	}
+}
+
+// findType returns the type spec of the named type or nil.
+func findType(decls []*ast.TypeDecl, name string) *ast.TypeSpec {
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if ok && ts.TypeSpec.Name != nil && ts.TypeSpec.Name.Name == name {
+			return ts.TypeSpec
+		}
+	}
+	return nil
+}
@@ -0,0 +1,441 @@
+// gqlgen.go generates the configuration, models and resolver stubs for gqlgen
+
+package golang
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+)
+
+const gqlgenImport = "github.com/99designs/gqlgen/graphql"
+
+// abstract is the kind of interfaces for gqlgen, which
+// are Go interfaces instead of structs with it.
+//
+type abstract struct{}
+
+// generateGqlgen generates the schema, gqlgen.yml, models and resolver
+// stubs of a document for gqlgen, which then generates the executable
+// schema and the resolver interfaces implemented by the stubs. The
+// stubs are only scaffolded once, since users implement them.
+//
+func (g *Generator) generateGqlgen(gCtx gen.GeneratorContext, doc *ast.Document, baseName string, opts *Options) (err error) {
+	// gqlgen binds the models by the import path of their package
+	if opts.ImportPath == "" {
+		return fmt.Errorf("the %s backend requires an importPath", backendGqlgen)
+	}
+
+	g.log.Info("writing schema")
+	var b bytes.Buffer
+	writeSDL(&b, doc.Types)
+	schemaFile := baseName + ".graphql"
+	if err = writeRaw(gCtx, schemaFile, b.Bytes()); err != nil {
+		return
+	}
+
+	g.log.Info("writing gqlgen.yml")
+	b.Reset()
+	writeGqlgenConfig(&b, doc.Types, schemaFile, opts)
+	if err = writeRaw(gCtx, "gqlgen.yml", b.Bytes()); err != nil {
+		return
+	}
+
+	g.log.Info("generating models")
+	g.bindings = bindings(doc.Types)
+	roots := rootTypes(doc.Types)
+	ks := typeKinds(doc.Types)
+	for name, k := range ks {
+		if _, ok := k.(*ast.TypeSpec_Interface); ok {
+			ks[name] = abstract{}
+		}
+	}
+	g.generateGqlgenModels(ks, doc.Types, roots, opts.Descriptions)
+
+	imports := append([]string{"fmt", "io", "strconv", gqlgenImport}, bindingPackages(g.bindings)...)
+	err = g.writeFile(gCtx, baseName+".go", opts.Package, imports, g.Bytes())
+	if err != nil {
+		return
+	}
+
+	g.log.Info("generating resolvers")
+	g.Reset()
+	g.generateGqlgenResolvers(ks, doc.Types, roots)
+	return g.writeScaffold(gCtx, "resolver.go", opts.Package, []string{"context"}, g.Bytes())
+}
+
+// writeGqlgenConfig writes the gqlgen.yml, which binds the models of the
+// package and marks the fields resolved by methods. Scalars are bound to
+// their Marshal and Unmarshal functions.
+//
+func writeGqlgenConfig(b *bytes.Buffer, decls []*ast.TypeDecl, schemaFile string, opts *Options) {
+	b.WriteString("# Code generated by gqlc. DO NOT EDIT.\n\n")
+	fmt.Fprintf(b, "schema:\n  - %s\n\n", schemaFile)
+	fmt.Fprintf(b, "exec:\n  filename: generated.go\n  package: %s\n\n", opts.Package)
+	fmt.Fprintf(b, "resolver:\n  filename: resolver.go\n  package: %s\n  type: Resolver\n\n", opts.Package)
+	fmt.Fprintf(b, "autobind:\n  - %s\n", opts.ImportPath)
+
+	roots := rootTypes(decls)
+	isRoot := make(map[string]bool, len(roots))
+	for _, r := range roots {
+		isRoot[r] = true
+	}
+
+	var models bytes.Buffer
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			fmt.Fprintf(&models, "  %s:\n    model: %s.%s\n", name, opts.ImportPath, name)
+		case *ast.TypeSpec_Object:
+			fields := resolvedFields(v.Object.Fields)
+			if isRoot[name] || len(fields) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(&models, "  %s:\n    fields:\n", name)
+			for _, f := range fields {
+				fmt.Fprintf(&models, "      %s:\n        resolver: true\n", f.Name.Name)
+			}
+		}
+	}
+	if models.Len() > 0 {
+		b.WriteString("\nmodels:\n")
+		b.Write(models.Bytes())
+	}
+}
+
+// resolvedFields returns the fields of an object, which are resolved by
+// methods instead of struct fields, i.e. fields with arguments or @resolver.
+//
+func resolvedFields(fields *ast.FieldList) (resolved []*ast.Field) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		if getResolver(f.Directives) != "" || (f.Args != nil && len(f.Args.List) > 0) {
+			resolved = append(resolved, f)
+		}
+	}
+	return
+}
+
+// generateGqlgenModels generates the models gqlgen binds to. Root types
+// have no models, since gqlgen resolves all of their fields by methods.
+//
+func (g *Generator) generateGqlgenModels(ks kinds, decls []*ast.TypeDecl, roots map[string]string, descr bool) {
+	isRoot := make(map[string]bool, len(roots))
+	for _, r := range roots {
+		isRoot[r] = true
+	}
+
+	// Objects implement the marker methods of their interfaces and unions
+	abstracts := make(map[string][]string)
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			for _, i := range v.Object.Interfaces {
+				abstracts[ts.TypeSpec.Name.Name] = append(abstracts[ts.TypeSpec.Name.Name], i.Name)
+			}
+		case *ast.TypeSpec_Union:
+			for _, m := range v.Union.Members {
+				abstracts[m.Name] = append(abstracts[m.Name], ts.TypeSpec.Name.Name)
+			}
+		}
+	}
+
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Scalar:
+			g.generateGqlgenScalar(name, g.bindings[name], getResolver(ts.TypeSpec.Directives))
+		case *ast.TypeSpec_Object:
+			if isRoot[name] {
+				continue
+			}
+
+			resolved := make(map[*ast.Field]bool)
+			for _, f := range resolvedFields(v.Object.Fields) {
+				resolved[f] = true
+			}
+
+			g.P()
+			g.printComment(descr, d.Doc)
+			g.P("type ", name, " struct {")
+			g.In()
+			if v.Object.Fields != nil {
+				for _, f := range v.Object.Fields.List {
+					if !resolved[f] {
+						g.printDeprecated(f.Directives)
+						g.P(gqlgenName(f.Name.Name), " ", ks.goType(graph.FieldType(f), false), " `json:\"", f.Name.Name, "\"`")
+					}
+				}
+			}
+			g.Out()
+			g.P("}")
+			for _, a := range abstracts[name] {
+				g.P()
+				g.P("func (", name, ") Is", a, "() {}")
+			}
+		case *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+			g.P()
+			g.printComment(descr, d.Doc)
+			g.P("type ", name, " interface {")
+			g.In()
+			g.P("Is", name, "()")
+			g.Out()
+			g.P("}")
+		case *ast.TypeSpec_Input:
+			g.P()
+			g.printComment(descr, d.Doc)
+			g.P("type ", name, " struct {")
+			g.In()
+			if v.Input.Fields != nil {
+				for _, f := range v.Input.Fields.List {
+					g.P(gqlgenName(f.Name.Name), " ", ks.goType(graph.InputValueType(f), false), " `json:\"", f.Name.Name, "\"`")
+				}
+			}
+			g.Out()
+			g.P("}")
+		case *ast.TypeSpec_Enum:
+			g.generateEnumType(name, "UnmarshalGQL", descr, d.Doc, v.Enum)
+
+			g.P()
+			g.P("// MarshalGQL writes the GraphQL name of e.")
+			g.P("func (e ", name, ") MarshalGQL(w io.Writer) {")
+			g.In()
+			g.P("io.WriteString(w, strconv.Quote(e.String()))")
+			g.Out()
+			g.P("}")
+		}
+	}
+}
+
+// generateGqlgenScalar generates the Marshal and Unmarshal functions
+// gqlgen binds a scalar to. Unbound scalars are held as interface{}
+// and serialized by their @resolver function, if any.
+//
+func (g *Generator) generateGqlgenScalar(name string, b *binding, resolver string) {
+	typ := "interface{}"
+	if b != nil {
+		typ = b.typ
+	}
+
+	g.P()
+	g.P("// Marshal", name, " marshals ", name, " values of type ", typ, ".")
+	g.P("func Marshal", name, "(v ", typ, ") graphql.Marshaler {")
+	g.In()
+	switch {
+	case b != nil && b.marshal != "":
+		g.P("return graphql.MarshalAny(", b.marshal, "(v))")
+	case b != nil:
+		g.P("text, err := v.MarshalText()")
+		g.P("if err != nil {")
+		g.In()
+		g.P("return graphql.Null")
+		g.Out()
+		g.P("}")
+		g.P("return graphql.MarshalString(string(text))")
+	case resolver != "":
+		g.P("return graphql.MarshalAny(", resolver, "(v))")
+	default:
+		g.P("return graphql.MarshalAny(v)")
+	}
+	g.Out()
+	g.P("}")
+
+	g.P()
+	g.P("// Unmarshal", name, " unmarshals ", name, " values into ", typ, ".")
+	g.P("func Unmarshal", name, "(v interface{}) (", typ, ", error) {")
+	g.In()
+	switch {
+	case b != nil && b.unmarshal != "":
+		g.P("return ", b.unmarshal, "(v)")
+	case b != nil:
+		g.P("var x ", b.typ)
+		g.P("s, ok := v.(string)")
+		g.P("if !ok {")
+		g.In()
+		g.P("return x, fmt.Errorf(\"", name, " must be a string\")")
+		g.Out()
+		g.P("}")
+		g.P("err := x.UnmarshalText([]byte(s))")
+		g.P("return x, err")
+	default:
+		g.P("return v, nil")
+	}
+	g.Out()
+	g.P("}")
+}
+
+// generateGqlgenResolvers generates the root Resolver and stubs for the
+// resolver interfaces gqlgen generates. Fields with @resolver call the
+// named function with the context, the parent object, unless it's a
+// root type, and the arguments, while all others panic.
+//
+func (g *Generator) generateGqlgenResolvers(ks kinds, decls []*ast.TypeDecl, roots map[string]string) {
+	type resolved struct {
+		name   string
+		root   string
+		fields []*ast.Field
+	}
+
+	var types []resolved
+	for _, op := range []string{"query", "mutation", "subscription"} {
+		if roots[op] != "" {
+			types = append(types, resolved{name: roots[op], root: op})
+		}
+	}
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object)
+		if !ok {
+			continue
+		}
+
+		name := ts.TypeSpec.Name.Name
+		for i, t := range types {
+			if t.name == name && obj.Object.Fields != nil {
+				types[i].fields = obj.Object.Fields.List
+			}
+		}
+		if fields := resolvedFields(obj.Object.Fields); !isRootType(roots, name) && len(fields) > 0 {
+			types = append(types, resolved{name: name, fields: fields})
+		}
+	}
+
+	g.P("// Resolver is the root resolver of the schema. Its resolvers are")
+	g.P("// stubs, which either panic or call the functions named by @resolver.")
+	g.P("type Resolver struct{}")
+	for _, t := range types {
+		g.P()
+		g.P("// ", gqlgenName(t.name), " returns the resolver of ", t.name, ".")
+		g.P("func (r *Resolver) ", gqlgenName(t.name), "() ", gqlgenName(t.name), "Resolver {")
+		g.In()
+		g.P("return &", unexportName(t.name), "Resolver{r}")
+		g.Out()
+		g.P("}")
+	}
+
+	for _, t := range types {
+		impl := unexportName(t.name) + "Resolver"
+		g.P()
+		g.P("type ", impl, " struct{ *Resolver }")
+
+		for _, f := range t.fields {
+			params, call := "ctx context.Context", "ctx"
+			if t.root == "" {
+				params += ", obj *" + t.name
+				call += ", obj"
+			}
+			if f.Args != nil {
+				for _, a := range f.Args.List {
+					params += ", " + goIdent(a.Name.Name) + " " + ks.goType(graph.InputValueType(a), false)
+					call += ", " + goIdent(a.Name.Name)
+				}
+			}
+			result := ks.goType(graph.FieldType(f), false)
+			if t.root == "subscription" {
+				result = "<-chan " + result
+			}
+
+			g.P()
+			g.P("func (r *", impl, ") ", gqlgenName(f.Name.Name), "(", params, ") (", result, ", error) {")
+			g.In()
+			if resolver := getResolver(f.Directives); resolver != "" {
+				g.P("return ", resolver, "(", call, ")")
+			} else {
+				g.P("panic(\"not implemented\")")
+			}
+			g.Out()
+			g.P("}")
+		}
+	}
+}
+
+// isRootType reports whether the named type is a root operation type.
+func isRootType(roots map[string]string, name string) bool {
+	for _, r := range roots {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// commonInitialisms are the initialisms kept upper case in Go names.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true,
+	"QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true,
+	"SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true,
+	"TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+	"XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// gqlgenName returns the Go name gqlgen derives from a GraphQL name,
+// which upper cases common initialisms e.g. userId to UserID.
+//
+func gqlgenName(name string) string {
+	var b strings.Builder
+	for _, w := range splitWords(name) {
+		if u := strings.ToUpper(w); commonInitialisms[u] {
+			b.WriteString(u)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// splitWords splits a name into its words at underscores
+// and case changes e.g. HTTPServer_url to HTTP, Server, url.
+//
+func splitWords(name string) (words []string) {
+	isUpper := func(c byte) bool { return 'A' <= c && c <= 'Z' }
+	isLower := func(c byte) bool { return 'a' <= c && c <= 'z' || '0' <= c && c <= '9' }
+
+	start := 0
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_':
+			if i > start {
+				words = append(words, name[start:i])
+			}
+			start = i + 1
+		case i > start && isUpper(c) && (isLower(name[i-1]) || i+1 < len(name) && isUpper(name[i-1]) && isLower(name[i+1])):
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	if start < len(name) {
+		words = append(words, name[start:])
+	}
+	return
+}
@@ -0,0 +1,130 @@
+// imports.go maps types of imported documents to their Go packages
+
+package golang
+
+import (
+	"path"
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// goPackage is the Go package a type of an imported document belongs to.
+type goPackage struct {
+	// name is the package name or empty, if the type
+	// belongs to the package being generated.
+	//
+	name string
+
+	// path is the import path of the package.
+	path string
+}
+
+// importSpec returns the import of the package, which is
+// only named, if its name differs from its path.
+//
+func (p goPackage) importSpec() string {
+	if path.Base(p.path) == p.name {
+		return p.path
+	}
+	return p.name + " " + p.path
+}
+
+// externalTypes returns the types of doc, which come from imported documents
+// with an import path. They are generated by those documents instead.
+//
+func externalTypes(doc *ast.Document, importPath string, dset *token.DocSet, srcs map[string]*ast.Document) (map[string]goPackage, error) {
+	exts := make(map[string]goPackage)
+	if dset == nil || len(srcs) == 0 {
+		return exts, nil
+	}
+
+	for _, d := range doc.Types {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		f := dset.Doc(token.Pos(d.TokPos))
+		if f == nil {
+			continue
+		}
+		src, ok := srcs[f.Name()]
+		if !ok || src.Name == doc.Name {
+			continue
+		}
+
+		opts, err := getOptions(src, nil)
+		if err != nil {
+			return nil, err
+		}
+		switch opts.ImportPath {
+		case "":
+		case importPath:
+			exts[ts.TypeSpec.Name.Name] = goPackage{path: importPath}
+		default:
+			exts[ts.TypeSpec.Name.Name] = goPackage{name: opts.Package, path: opts.ImportPath}
+		}
+	}
+	return exts, nil
+}
+
+// sourceDoc returns the given document as it was parsed, if known.
+func sourceDoc(doc *ast.Document, srcs map[string]*ast.Document) *ast.Document {
+	for _, src := range srcs {
+		if src.Name == doc.Name {
+			return src
+		}
+	}
+	return doc
+}
+
+// externalImports returns the imports of the packages of the given types.
+func externalImports(exts map[string]goPackage) (imports []string) {
+	for _, p := range exts {
+		if p.name != "" {
+			imports = append(imports, p.importSpec())
+		}
+	}
+	return
+}
+
+// typeRef returns the reference to the variable of the named type.
+func (g *Generator) typeRef(name string) string {
+	if p := g.externals[name]; p.name != "" {
+		return p.name + "." + name + string(typeSuffix)
+	}
+	return name + string(typeSuffix)
+}
+
+// kinds returns the kinds of the given types, where the
+// types of other packages are qualified by their package.
+//
+func (g *Generator) kinds(decls []*ast.TypeDecl) kinds {
+	ks := typeKinds(decls)
+	for name, p := range g.externals {
+		if p.name == "" {
+			continue
+		}
+
+		if k, ok := ks[name]; ok {
+			ks[name] = external{pkg: p.name, kind: k}
+		}
+	}
+	return ks
+}
+
+// external is the kind of a type declared in another package.
+type external struct {
+	pkg  string
+	kind interface{}
+}
+
+// splitImport splits an import into its package name and path.
+func splitImport(imp string) (name, impPath string) {
+	if i := strings.IndexByte(imp, ' '); i > 0 {
+		return imp[:i], imp[i+1:]
+	}
+	return path.Base(imp), imp
+}
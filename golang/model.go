@@ -0,0 +1,326 @@
+// model.go generates Go models for types and field arguments
+
+package golang
+
+import (
+	"strings"
+
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+)
+
+// kinds maps type names to the kind of their declaration
+// or, for scalars bound to Go types, their binding.
+//
+type kinds map[string]interface{}
+
+func typeKinds(decls []*ast.TypeDecl) kinds {
+	ks := make(kinds, len(decls))
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		// Bound scalars are referenced by their Go type
+		if b := getBinding(ts.TypeSpec.Directives); b != nil {
+			ks[ts.TypeSpec.Name.Name] = b
+			continue
+		}
+
+		ks[ts.TypeSpec.Name.Name] = ts.TypeSpec.Type
+	}
+	return ks
+}
+
+var builtinTypes = map[string]string{
+	"Int":     "int",
+	"Float":   "float64",
+	"String":  "string",
+	"Boolean": "bool",
+	"ID":      "string",
+}
+
+// goType returns the Go type of a, possibly wrapped, GraphQL type. Nullable
+// scalars and enums become pointers, while objects and inputs are always
+// referenced by pointer, since types may reference themselves. Interfaces
+// are referenced as interface{}, since any implementation may be used,
+// unless they're Go interfaces, as with gqlgen.
+//
+func (ks kinds) goType(typ interface{}, nonNull bool) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		if t, ok := builtinTypes[v.Name]; ok {
+			if nonNull {
+				return t
+			}
+			return "*" + t
+		}
+
+		name, kind := v.Name, ks[v.Name]
+		if e, ok := kind.(external); ok {
+			name, kind = e.pkg+"."+name, e.kind
+		}
+
+		switch k := kind.(type) {
+		case *binding:
+			if nonNull {
+				return k.typ
+			}
+			return "*" + k.typ
+		case *ast.TypeSpec_Enum:
+			if nonNull {
+				return name
+			}
+			return "*" + name
+		case *ast.TypeSpec_Object, *ast.TypeSpec_Input:
+			return "*" + name
+		case *ast.TypeSpec_Union, abstract:
+			return name
+		}
+		return "interface{}"
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return "[]" + ks.goType(w.Ident, false)
+		case *ast.List_List:
+			return "[]" + ks.goType(w.List, false)
+		case *ast.List_NonNull:
+			return "[]" + ks.goType(w.NonNull, false)
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return ks.goType(w.Ident, true)
+		case *ast.NonNull_List:
+			return ks.goType(w.List, true)
+		}
+	}
+	return "interface{}"
+}
+
+// argsName returns the name of the arguments struct of a field.
+func argsName(typeName string, f *ast.Field) string {
+	return typeName + exportName(f.Name.Name) + "Args"
+}
+
+// generateModels generates Go structs for objects, interfaces and inputs,
+// string types for enums, marker interfaces for unions and structs for the
+// arguments of fields.
+//
+func (g *Generator) generateModels(decls []*ast.TypeDecl, descr bool) {
+	ks := g.kinds(decls)
+
+	// Union members implement the marker interfaces of their unions,
+	// which is only possible for unions of the same package
+	//
+	unions := make(map[string][]string)
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil || g.externals[ts.TypeSpec.Name.Name].name != "" {
+			continue
+		}
+		if u, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Union); ok {
+			for _, m := range u.Union.Members {
+				unions[m.Name] = append(unions[m.Name], ts.TypeSpec.Name.Name)
+			}
+		}
+	}
+
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+		if _, ok = g.externals[name]; ok {
+			continue
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			g.generateStruct(ks, name, descr, d.Doc, v.Object.Fields)
+			for _, u := range unions[name] {
+				g.P()
+				g.P("func (", name, ") is", u, "() {}")
+			}
+			g.generateArgStructs(ks, name, v.Object.Fields)
+		case *ast.TypeSpec_Interface:
+			g.generateStruct(ks, name, descr, d.Doc, v.Interface.Fields)
+			g.generateArgStructs(ks, name, v.Interface.Fields)
+		case *ast.TypeSpec_Input:
+			g.P()
+			g.printComment(descr, d.Doc)
+			g.P("type ", name, " struct {")
+			g.In()
+			if v.Input.Fields != nil {
+				g.generateStructFields(ks, v.Input.Fields.List)
+			}
+			g.Out()
+			g.P("}")
+		case *ast.TypeSpec_Enum:
+			g.P()
+			g.printComment(descr, d.Doc)
+			g.P("type ", name, " string")
+			if v.Enum.Values == nil {
+				break
+			}
+
+			g.P()
+			g.P("const (")
+			g.In()
+			for _, ev := range v.Enum.Values.List {
+				val := getValue(ev.Directives)
+				if val == "" {
+					val = ev.Name.Name
+				}
+				g.printDeprecated(ev.Directives)
+				g.P(name, enumName(ev.Name.Name), " ", name, " = \"", val, "\"")
+			}
+			g.Out()
+			g.P(")")
+		case *ast.TypeSpec_Union:
+			g.P()
+			g.printComment(descr, d.Doc)
+			g.P("type ", name, " interface {")
+			g.In()
+			g.P("is", name, "()")
+			g.Out()
+			g.P("}")
+		}
+	}
+}
+
+func (g *Generator) generateStruct(ks kinds, name string, descr bool, doc *ast.DocGroup, fields *ast.FieldList) {
+	g.P()
+	g.printComment(descr, doc)
+	g.P("type ", name, " struct {")
+	g.In()
+	if fields != nil {
+		for _, f := range fields.List {
+			g.printDeprecated(f.Directives)
+			g.P(exportName(f.Name.Name), " ", ks.goType(graph.FieldType(f), false), " `json:\"", f.Name.Name, "\"`")
+		}
+	}
+	g.Out()
+	g.P("}")
+}
+
+func (g *Generator) generateStructFields(ks kinds, fields []*ast.InputValue) {
+	for _, f := range fields {
+		g.P(exportName(f.Name.Name), " ", ks.goType(graph.InputValueType(f), false), " `json:\"", f.Name.Name, "\"`")
+	}
+}
+
+// generateArgStructs generates an arguments struct and its
+// decode function for each field with arguments.
+//
+func (g *Generator) generateArgStructs(ks kinds, typeName string, fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+
+	for _, f := range fields.List {
+		if f.Args == nil || len(f.Args.List) == 0 {
+			continue
+		}
+		name := argsName(typeName, f)
+
+		g.P()
+		g.P("// ", name, " are the arguments of ", typeName, ".", f.Name.Name, ".")
+		g.P("type ", name, " struct {")
+		g.In()
+		g.generateStructFields(ks, f.Args.List)
+		g.Out()
+		g.P("}")
+
+		g.P()
+		g.P("// Decode", name, " decodes the arguments of ", typeName, ".", f.Name.Name, " e.g. p.Args.")
+		g.P("func Decode", name, "(args map[string]interface{}) (a ", name, ", err error) {")
+		g.In()
+		g.P("b, err := json.Marshal(args)")
+		g.P("if err != nil {")
+		g.In()
+		g.P("return")
+		g.Out()
+		g.P("}")
+		g.P("err = json.Unmarshal(b, &a)")
+		g.P("return")
+		g.Out()
+		g.P("}")
+	}
+}
+
+// printComment prints the description of a type as a Go comment.
+func (g *Generator) printComment(descr bool, doc *ast.DocGroup) {
+	if doc == nil || !descr {
+		return
+	}
+
+	text := doc.Text()
+	if len(text) > 0 {
+		g.P("// ", strings.Replace(text[:len(text)-1], "\n", "\n// ", -1))
+	}
+}
+
+// printDeprecated prints the deprecation of a field or enum value as a Go comment.
+func (g *Generator) printDeprecated(dirs []*ast.DirectiveLit) {
+	reason, ok := getDeprecation(dirs)
+	if !ok {
+		return
+	}
+
+	if reason == "" {
+		reason = "No longer supported"
+	}
+	g.P("// Deprecated: ", strings.Replace(reason, "\n", "\n// ", -1))
+}
+
+// hasArgs reports whether any field of an object or interface has arguments.
+func hasArgs(decls []*ast.TypeDecl) bool {
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		var fields *ast.FieldList
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			fields = v.Object.Fields
+		case *ast.TypeSpec_Interface:
+			fields = v.Interface.Fields
+		}
+		if fields == nil {
+			continue
+		}
+
+		for _, f := range fields.List {
+			if f.Args != nil && len(f.Args.List) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// enumName returns the camel case name of an enum value e.g. NORTH_EAST to NorthEast.
+func enumName(name string) string {
+	b := make([]byte, 0, len(name))
+	upper := true
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_':
+			upper = true
+			continue
+		case upper && 'a' <= c && c <= 'z':
+			c -= 'a' - 'A'
+		case !upper && 'A' <= c && c <= 'Z':
+			c += 'a' - 'A'
+		}
+		upper = false
+		b = append(b, c)
+	}
+	return string(b)
+}
@@ -0,0 +1,263 @@
+// resolver.go generates typed resolver interfaces and the schema constructor
+
+package golang
+
+import (
+	"strings"
+
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+)
+
+// resolvedType is a type, which is resolved by a generated resolver interface.
+type resolvedType struct {
+	name string
+
+	// fields are the fields without a custom resolver or dataloader.
+	// They're only set for objects, since abstract types resolve
+	// their concrete types instead.
+	//
+	fields []*ast.Field
+}
+
+// resolvedTypes returns all types, which need resolvers.
+func resolvedTypes(decls []*ast.TypeDecl) (types []resolvedType) {
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			if v.Object.Fields == nil {
+				continue
+			}
+
+			rt := resolvedType{name: ts.TypeSpec.Name.Name}
+			for _, f := range v.Object.Fields.List {
+				if getResolver(f.Directives) == "" && getBatch(f.Directives) == "" {
+					rt.fields = append(rt.fields, f)
+				}
+			}
+			if len(rt.fields) > 0 {
+				types = append(types, rt)
+			}
+		case *ast.TypeSpec_Interface:
+			types = append(types, resolvedType{name: ts.TypeSpec.Name.Name})
+		case *ast.TypeSpec_Union:
+			if getResolver(ts.TypeSpec.Directives) == "" {
+				types = append(types, resolvedType{name: ts.TypeSpec.Name.Name})
+			}
+		}
+	}
+	return
+}
+
+// rootTypes returns the query, mutation and subscription types. Without
+// a schema declaration, the types named after the operations are used.
+//
+func rootTypes(decls []*ast.TypeDecl) (roots map[string]string) {
+	roots = make(map[string]string, 3)
+	names := make(map[string]bool, len(decls))
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Schema:
+			if v.Schema.RootOps == nil {
+				continue
+			}
+
+			for _, f := range v.Schema.RootOps.List {
+				if id, ok := f.Type.(*ast.Field_Ident); ok {
+					roots[strings.ToLower(f.Name.Name)] = id.Ident.Name
+				}
+			}
+			return
+		case *ast.TypeSpec_Object:
+			names[ts.TypeSpec.Name.Name] = true
+		}
+	}
+
+	for _, op := range []string{"Query", "Mutation", "Subscription"} {
+		if names[op] {
+			roots[strings.ToLower(op)] = op
+		}
+	}
+	return
+}
+
+// generateResolvers generates a resolver interface for each type in need of
+// one, the Resolvers struct holding them and the NewSchema constructor.
+// With models, field resolvers take decoded arguments and return models.
+//
+func (g *Generator) generateResolvers(decls []*ast.TypeDecl, models bool) {
+	var ks kinds
+	if models {
+		ks = g.kinds(decls)
+	}
+
+	// The types of imported documents are resolved by their own package
+	var resolved []resolvedType
+	for _, t := range resolvedTypes(decls) {
+		if _, ok := g.externals[t.name]; !ok {
+			resolved = append(resolved, t)
+		}
+	}
+	roots := rootTypes(decls)
+	if len(resolved) == 0 && roots["query"] == "" {
+		return
+	}
+
+	for _, t := range resolved {
+		g.P()
+		if t.fields == nil {
+			g.P("// ", t.name, "Resolver resolves the concrete type of ", t.name, ".")
+			g.P("type ", t.name, "Resolver interface {")
+			g.In()
+			g.P("ResolveType(p graphql.ResolveTypeParams) *graphql.Object")
+			g.Out()
+			g.P("}")
+			continue
+		}
+
+		g.P("// ", t.name, "Resolver resolves the fields of ", t.name, ".")
+		g.P("type ", t.name, "Resolver interface {")
+		g.In()
+		for _, f := range t.fields {
+			if !models {
+				g.P(exportName(f.Name.Name), "(p graphql.ResolveParams) (interface{}, error)")
+				continue
+			}
+
+			args := ""
+			if f.Args != nil && len(f.Args.List) > 0 {
+				args = ", args " + argsName(t.name, f)
+			}
+			g.P(exportName(f.Name.Name), "(p graphql.ResolveParams", args, ") (", ks.goType(graph.FieldType(f), false), ", error)")
+		}
+		g.Out()
+		g.P("}")
+	}
+
+	g.P()
+	g.P("// Resolvers are the resolvers of each type. A nil resolver keeps")
+	g.P("// the default resolution of graphql-go for the type in place.")
+	g.P("type Resolvers struct {")
+	g.In()
+	for _, t := range resolved {
+		g.P(t.name, " ", t.name, "Resolver")
+	}
+	g.Out()
+	g.P("}")
+
+	if roots["query"] == "" {
+		return
+	}
+
+	g.P()
+	g.P("// NewSchema wires the given resolvers into the types and builds the schema.")
+	g.P("func NewSchema(resolvers Resolvers) (graphql.Schema, error) {")
+	g.In()
+	for _, t := range resolved {
+		g.P("if r := resolvers.", t.name, "; r != nil {")
+		g.In()
+		if t.fields == nil {
+			g.P(t.name, typeSuffix, ".ResolveType = r.ResolveType")
+		} else {
+			g.P("fields := ", t.name, typeSuffix, ".Fields()")
+			for _, f := range t.fields {
+				g.generateWiring(t.name, f, models)
+			}
+		}
+		g.Out()
+		g.P("}")
+	}
+
+	g.P("return graphql.NewSchema(graphql.SchemaConfig{")
+	g.In()
+	g.P("Query: ", g.typeRef(roots["query"]), ",")
+	if m := roots["mutation"]; m != "" {
+		g.P("Mutation: ", g.typeRef(m), ",")
+	}
+	if s := roots["subscription"]; s != "" {
+		g.P("Subscription: ", g.typeRef(s), ",")
+	}
+
+	// Register all types, since types only reachable through
+	// interfaces would be unknown to the schema otherwise
+	//
+	types, directives := schemaTypes(decls)
+	g.P("Types: []graphql.Type{")
+	g.In()
+	for _, t := range types {
+		g.P(g.typeRef(t), ",")
+	}
+	g.Out()
+	g.P("},")
+	if len(directives) > 0 {
+		g.P("Directives: []*graphql.Directive{")
+		g.In()
+		g.P("graphql.IncludeDirective,")
+		g.P("graphql.SkipDirective,")
+		g.P("graphql.DeprecatedDirective,")
+		for _, d := range directives {
+			g.P(g.typeRef(d), ",")
+		}
+		g.Out()
+		g.P("},")
+	}
+	g.Out()
+	g.P("})")
+	g.Out()
+	g.P("}")
+}
+
+// generateWiring sets the resolver of a field to the method of r resolving it.
+func (g *Generator) generateWiring(typeName string, f *ast.Field, models bool) {
+	method := exportName(f.Name.Name)
+	if !models {
+		g.P("fields[\"", f.Name.Name, "\"].Resolve = r.", method)
+		return
+	}
+
+	g.P("fields[\"", f.Name.Name, "\"].Resolve = func(p graphql.ResolveParams) (interface{}, error) {")
+	g.In()
+	if f.Args == nil || len(f.Args.List) == 0 {
+		g.P("return r.", method, "(p)")
+	} else {
+		g.P("args, err := Decode", argsName(typeName, f), "(p.Args)")
+		g.P("if err != nil {")
+		g.In()
+		g.P("return nil, err")
+		g.Out()
+		g.P("}")
+		g.P("return r.", method, "(p, args)")
+	}
+	g.Out()
+	g.P("}")
+}
+
+// exportName returns the exported Go name of a GraphQL name, which
+// upper cases common initialisms, but keeps underscores e.g. user_id
+// to User_ID.
+//
+func exportName(name string) string {
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		var b strings.Builder
+		for _, w := range splitWords(part) {
+			if u := strings.ToUpper(w); commonInitialisms[u] {
+				w = u
+			}
+			b.WriteString(w)
+		}
+		parts[i] = b.String()
+	}
+	name = strings.Join(parts, "_")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
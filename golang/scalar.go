@@ -0,0 +1,237 @@
+// scalar.go generates the serialization of scalars bound to Go types
+
+package golang
+
+import (
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// binding binds a custom scalar to a Go type via the @goType directive. The
+// type is given by its name argument, since type is a keyword in GraphQL.
+//
+type binding struct {
+	// typ is the qualified Go type e.g. time.Time
+	typ string
+
+	// pkg is the import path of the package providing typ
+	pkg string
+
+	// marshal and unmarshal are functions converting between typ and
+	// the wire value, i.e. func(typ) interface{} and
+	// func(interface{}) (typ, error). Without them, typ
+	// must implement encoding.TextMarshaler and *typ
+	// encoding.TextUnmarshaler.
+	//
+	marshal, unmarshal string
+}
+
+// getBinding returns the binding of a scalar, if it has one.
+func getBinding(dirs []*ast.DirectiveLit) *binding {
+	for _, d := range dirs {
+		if d.Name != "goType" || d.Args == nil {
+			continue
+		}
+
+		b := new(binding)
+		for _, arg := range d.Args.Args {
+			lit, ok := arg.Value.(*ast.Arg_BasicLit)
+			if !ok {
+				continue
+			}
+			val := strings.Trim(lit.BasicLit.Value, "\"")
+
+			switch arg.Name.Name {
+			case "name":
+				b.typ = val
+			case "package":
+				b.pkg = val
+			case "marshal":
+				b.marshal = val
+			case "unmarshal":
+				b.unmarshal = val
+			}
+		}
+		if b.typ == "" {
+			return nil
+		}
+		return b
+	}
+	return nil
+}
+
+// bindings returns the bound scalars of the given types by name.
+func bindings(decls []*ast.TypeDecl) map[string]*binding {
+	bs := make(map[string]*binding)
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Scalar); !ok {
+			continue
+		}
+
+		if b := getBinding(ts.TypeSpec.Directives); b != nil {
+			bs[ts.TypeSpec.Name.Name] = b
+		}
+	}
+	return bs
+}
+
+// bindingImports returns the imports needed by the given bindings.
+func bindingImports(bs map[string]*binding) (imports []string) {
+	if len(bs) == 0 {
+		return
+	}
+
+	imports = append(imports, "strconv", "github.com/graphql-go/graphql/language/ast")
+	for _, b := range bs {
+		if b.pkg != "" {
+			imports = append(imports, b.pkg)
+		}
+	}
+	return
+}
+
+// generateBinding generates the Serialize, ParseValue and ParseLiteral
+// functions of a bound scalar, as part of its configuration.
+//
+func (g *Generator) generateBinding(name string) {
+	g.P("Serialize: serialize", name, ",")
+	g.P("ParseValue: parse", name, ",")
+	g.P("ParseLiteral: func(valueAST ast.Value) interface{} {")
+	g.In()
+	g.P("return parse", name, "(literalValue(valueAST))")
+	g.Out()
+	g.P("},")
+}
+
+// generateBindings generates the serialize and parse functions
+// of each bound scalar and the literalValue helper they share.
+//
+func (g *Generator) generateBindings(decls []*ast.TypeDecl, bs map[string]*binding) {
+	if len(bs) == 0 {
+		return
+	}
+
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+		b, ok := bs[name]
+		if !ok {
+			continue
+		}
+
+		g.P()
+		g.P("// serialize", name, " serializes ", name, " values of type ", b.typ, ".")
+		g.P("func serialize", name, "(value interface{}) interface{} {")
+		g.In()
+		g.P("if p, ok := value.(*", b.typ, "); ok {")
+		g.In()
+		g.P("if p == nil {")
+		g.In()
+		g.P("return nil")
+		g.Out()
+		g.P("}")
+		g.P("value = *p")
+		g.Out()
+		g.P("}")
+		g.P("v, ok := value.(", b.typ, ")")
+		g.P("if !ok {")
+		g.In()
+		g.P("return nil")
+		g.Out()
+		g.P("}")
+		if b.marshal != "" {
+			g.P("return ", b.marshal, "(v)")
+		} else {
+			g.P("b, err := v.MarshalText()")
+			g.P("if err != nil {")
+			g.In()
+			g.P("return nil")
+			g.Out()
+			g.P("}")
+			g.P("return string(b)")
+		}
+		g.Out()
+		g.P("}")
+
+		g.P()
+		g.P("// parse", name, " parses ", name, " values into ", b.typ, ".")
+		g.P("func parse", name, "(value interface{}) interface{} {")
+		g.In()
+		if b.unmarshal != "" {
+			g.P("v, err := ", b.unmarshal, "(value)")
+			g.P("if err != nil {")
+			g.In()
+			g.P("return nil")
+			g.Out()
+			g.P("}")
+			g.P("return v")
+		} else {
+			g.P("s, ok := value.(string)")
+			g.P("if !ok {")
+			g.In()
+			g.P("return nil")
+			g.Out()
+			g.P("}")
+			g.P("var v ", b.typ)
+			g.P("if err := v.UnmarshalText([]byte(s)); err != nil {")
+			g.In()
+			g.P("return nil")
+			g.Out()
+			g.P("}")
+			g.P("return v")
+		}
+		g.Out()
+		g.P("}")
+	}
+
+	g.P()
+	g.P(literalValueFunc)
+}
+
+// literalValueFunc converts literals into the values decoded from
+// variables, so scalars parse both the same way.
+//
+const literalValueFunc = `// literalValue converts a literal into the value it represents.
+func literalValue(valueAST ast.Value) interface{} {
+	switch v := valueAST.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.IntValue:
+		f, err := strconv.ParseFloat(v.Value, 64)
+		if err != nil {
+			return nil
+		}
+		return f
+	case *ast.FloatValue:
+		f, err := strconv.ParseFloat(v.Value, 64)
+		if err != nil {
+			return nil
+		}
+		return f
+	case *ast.ListValue:
+		list := make([]interface{}, len(v.Values))
+		for i, item := range v.Values {
+			list[i] = literalValue(item)
+		}
+		return list
+	case *ast.ObjectValue:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			obj[f.Name.Value] = literalValue(f.Value)
+		}
+		return obj
+	}
+	return nil
+}`
@@ -0,0 +1,164 @@
+// schema.go determines cyclic types and the types registered with the schema
+
+package golang
+
+import (
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+)
+
+// cyclicTypes returns the objects, interfaces and inputs, which reference
+// themselves, directly or indirectly. Their fields are added by init
+// functions, since their package variables would otherwise form
+// initialization cycles.
+//
+func cyclicTypes(decls []*ast.TypeDecl) map[string]bool {
+	refs := make(map[string][]string)
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		name := ts.TypeSpec.Name.Name
+
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Object:
+			for _, i := range v.Object.Interfaces {
+				refs[name] = append(refs[name], i.Name)
+			}
+			refs[name] = appendFieldRefs(refs[name], v.Object.Fields)
+		case *ast.TypeSpec_Interface:
+			refs[name] = appendFieldRefs(refs[name], v.Interface.Fields)
+		case *ast.TypeSpec_Union:
+			for _, m := range v.Union.Members {
+				refs[name] = append(refs[name], m.Name)
+			}
+		case *ast.TypeSpec_Input:
+			if v.Input.Fields != nil {
+				refs[name] = appendInputRefs(refs[name], v.Input.Fields.List)
+			}
+		}
+	}
+
+	// Find the strongly connected components with Tarjan's algorithm
+	index := make(map[string]int, len(refs))
+	low := make(map[string]int, len(refs))
+	onStack := make(map[string]bool, len(refs))
+	var stack []string
+	cyclic := make(map[string]bool)
+
+	var visit func(n string)
+	visit = func(n string) {
+		index[n] = len(index)
+		low[n] = index[n]
+		stack = append(stack, n)
+		onStack[n] = true
+
+		for _, m := range refs[n] {
+			if _, ok := refs[m]; !ok {
+				continue
+			}
+
+			if _, ok := index[m]; !ok {
+				visit(m)
+				if low[m] < low[n] {
+					low[n] = low[m]
+				}
+			} else if onStack[m] && index[m] < low[n] {
+				low[n] = index[m]
+			}
+			if m == n {
+				cyclic[n] = true
+			}
+		}
+
+		if low[n] != index[n] {
+			return
+		}
+
+		var scc []string
+		for {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[m] = false
+			scc = append(scc, m)
+			if m == n {
+				break
+			}
+		}
+		if len(scc) > 1 {
+			for _, m := range scc {
+				cyclic[m] = true
+			}
+		}
+	}
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+		if _, ok := refs[ts.TypeSpec.Name.Name]; !ok {
+			continue
+		}
+		if _, ok := index[ts.TypeSpec.Name.Name]; !ok {
+			visit(ts.TypeSpec.Name.Name)
+		}
+	}
+	return cyclic
+}
+
+func appendFieldRefs(refs []string, fields *ast.FieldList) []string {
+	if fields == nil {
+		return refs
+	}
+
+	for _, f := range fields.List {
+		refs = append(refs, graph.BaseName(graph.FieldType(f)))
+		if f.Args != nil {
+			refs = appendInputRefs(refs, f.Args.List)
+		}
+	}
+	return refs
+}
+
+func appendInputRefs(refs []string, args []*ast.InputValue) []string {
+	for _, a := range args {
+		refs = append(refs, graph.BaseName(graph.InputValueType(a)))
+	}
+	return refs
+}
+
+// schemaTypes returns the names of the types and directives
+// declared by the document, which are registered with the schema.
+//
+func schemaTypes(decls []*ast.TypeDecl) (types, directives []string) {
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		switch ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Schema:
+		case *ast.TypeSpec_Directive:
+			directives = append(directives, ts.TypeSpec.Name.Name)
+		default:
+			types = append(types, ts.TypeSpec.Name.Name)
+		}
+	}
+	return
+}
+
+// typeSpecs returns the declared types by name.
+func typeSpecs(decls []*ast.TypeDecl) map[string]*ast.TypeSpec {
+	specs := make(map[string]*ast.TypeSpec, len(decls))
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok || ts.TypeSpec.Name == nil {
+			continue
+		}
+
+		specs[ts.TypeSpec.Name.Name] = ts.TypeSpec
+	}
+	return specs
+}
@@ -0,0 +1,292 @@
+// sdl.go prints type declarations as GraphQL SDL for the schema-first backends
+
+package golang
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+)
+
+// writeSDL writes the given types as GraphQL SDL, separated by blank lines.
+// Any directives custom to gqlc are left out, since they only configure gqlc.
+//
+func writeSDL(b *bytes.Buffer, decls []*ast.TypeDecl) {
+	for _, d := range decls {
+		ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+		if !ok {
+			continue
+		}
+		if _, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Directive); ok && types.IsGqlcDirective(ts.TypeSpec.Name.Name) {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		writeSDLDescr(b, "", d.Doc)
+		switch v := ts.TypeSpec.Type.(type) {
+		case *ast.TypeSpec_Schema:
+			b.WriteString("schema")
+			writeSDLDirectives(b, ts.TypeSpec.Directives)
+			writeSDLFields(b, v.Schema.RootOps)
+		case *ast.TypeSpec_Scalar:
+			b.WriteString("scalar ")
+			b.WriteString(ts.TypeSpec.Name.Name)
+			writeSDLDirectives(b, ts.TypeSpec.Directives)
+			b.WriteByte('\n')
+		case *ast.TypeSpec_Object:
+			b.WriteString("type ")
+			b.WriteString(ts.TypeSpec.Name.Name)
+			for i, inter := range v.Object.Interfaces {
+				if i == 0 {
+					b.WriteString(" implements ")
+				} else {
+					b.WriteString(" & ")
+				}
+				b.WriteString(inter.Name)
+			}
+			writeSDLDirectives(b, ts.TypeSpec.Directives)
+			writeSDLFields(b, v.Object.Fields)
+		case *ast.TypeSpec_Interface:
+			b.WriteString("interface ")
+			b.WriteString(ts.TypeSpec.Name.Name)
+			writeSDLDirectives(b, ts.TypeSpec.Directives)
+			writeSDLFields(b, v.Interface.Fields)
+		case *ast.TypeSpec_Union:
+			b.WriteString("union ")
+			b.WriteString(ts.TypeSpec.Name.Name)
+			writeSDLDirectives(b, ts.TypeSpec.Directives)
+			for i, m := range v.Union.Members {
+				if i == 0 {
+					b.WriteString(" = ")
+				} else {
+					b.WriteString(" | ")
+				}
+				b.WriteString(m.Name)
+			}
+			b.WriteByte('\n')
+		case *ast.TypeSpec_Enum:
+			b.WriteString("enum ")
+			b.WriteString(ts.TypeSpec.Name.Name)
+			writeSDLDirectives(b, ts.TypeSpec.Directives)
+			writeSDLFields(b, v.Enum.Values)
+		case *ast.TypeSpec_Directive:
+			b.WriteString("directive @")
+			b.WriteString(ts.TypeSpec.Name.Name)
+			writeSDLArgs(b, v.Directive.Args)
+			b.WriteString(" on ")
+			for i, l := range v.Directive.Locs {
+				if i > 0 {
+					b.WriteString(" | ")
+				}
+				b.WriteString(l.Loc.String())
+			}
+			b.WriteByte('\n')
+		case *ast.TypeSpec_Input:
+			b.WriteString("input ")
+			b.WriteString(ts.TypeSpec.Name.Name)
+			writeSDLDirectives(b, ts.TypeSpec.Directives)
+			b.WriteString(" {\n")
+			if v.Input.Fields != nil {
+				for _, f := range v.Input.Fields.List {
+					writeSDLDescr(b, "\t", f.Doc)
+					b.WriteByte('\t')
+					writeSDLInputValue(b, f)
+					b.WriteByte('\n')
+				}
+			}
+			b.WriteString("}\n")
+		}
+	}
+}
+
+func writeSDLDescr(b *bytes.Buffer, indent string, doc *ast.DocGroup) {
+	if doc == nil {
+		return
+	}
+
+	text := doc.Text()
+	if len(text) == 0 {
+		return
+	}
+	text = text[:len(text)-1]
+
+	b.WriteString(indent)
+	if !strings.Contains(text, "\n") {
+		b.WriteString(`"`)
+		b.WriteString(strings.Replace(text, `"`, `\"`, -1))
+		b.WriteString("\"\n")
+		return
+	}
+
+	b.WriteString(`"""`)
+	b.WriteByte('\n')
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(indent)
+		b.WriteString(strings.Replace(line, `"""`, `\"""`, -1))
+		b.WriteByte('\n')
+	}
+	b.WriteString(indent)
+	b.WriteString(`"""`)
+	b.WriteByte('\n')
+}
+
+// writeSDLFields writes a fields block, which also holds enum values.
+func writeSDLFields(b *bytes.Buffer, fields *ast.FieldList) {
+	b.WriteString(" {\n")
+	if fields != nil {
+		for _, f := range fields.List {
+			writeSDLDescr(b, "\t", f.Doc)
+			b.WriteByte('\t')
+			b.WriteString(f.Name.Name)
+			if f.Args != nil && len(f.Args.List) > 0 {
+				writeSDLArgs(b, f.Args)
+			}
+			if typ := graph.FieldType(f); typ != nil {
+				b.WriteString(": ")
+				writeSDLType(b, typ)
+			}
+			writeSDLDirectives(b, f.Directives)
+			b.WriteByte('\n')
+		}
+	}
+	b.WriteString("}\n")
+}
+
+func writeSDLArgs(b *bytes.Buffer, args *ast.InputValueList) {
+	if args == nil {
+		return
+	}
+
+	b.WriteByte('(')
+	for i, a := range args.List {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeSDLInputValue(b, a)
+	}
+	b.WriteByte(')')
+}
+
+func writeSDLInputValue(b *bytes.Buffer, iv *ast.InputValue) {
+	b.WriteString(iv.Name.Name)
+	b.WriteString(": ")
+	writeSDLType(b, graph.InputValueType(iv))
+
+	switch v := iv.Default.(type) {
+	case *ast.InputValue_BasicLit:
+		b.WriteString(" = ")
+		writeSDLValue(b, v.BasicLit)
+	case *ast.InputValue_CompositeLit:
+		b.WriteString(" = ")
+		writeSDLValue(b, v.CompositeLit)
+	}
+	writeSDLDirectives(b, iv.Directives)
+}
+
+func writeSDLType(b *bytes.Buffer, typ interface{}) {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		b.WriteString(v.Name)
+	case *ast.List:
+		b.WriteByte('[')
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			writeSDLType(b, w.Ident)
+		case *ast.List_List:
+			writeSDLType(b, w.List)
+		case *ast.List_NonNull:
+			writeSDLType(b, w.NonNull)
+		}
+		b.WriteByte(']')
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			writeSDLType(b, w.Ident)
+		case *ast.NonNull_List:
+			writeSDLType(b, w.List)
+		}
+		b.WriteByte('!')
+	}
+}
+
+func writeSDLDirectives(b *bytes.Buffer, dirs []*ast.DirectiveLit) {
+	for _, d := range dirs {
+		if types.IsGqlcDirective(d.Name) {
+			continue
+		}
+
+		b.WriteString(" @")
+		b.WriteString(d.Name)
+		if d.Args == nil || len(d.Args.Args) == 0 {
+			continue
+		}
+
+		b.WriteByte('(')
+		for i, a := range d.Args.Args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(a.Name.Name)
+			b.WriteString(": ")
+
+			switch v := a.Value.(type) {
+			case *ast.Arg_BasicLit:
+				writeSDLValue(b, v.BasicLit)
+			case *ast.Arg_CompositeLit:
+				writeSDLValue(b, v.CompositeLit)
+			}
+		}
+		b.WriteByte(')')
+	}
+}
+
+func writeSDLValue(b *bytes.Buffer, val interface{}) {
+	switch v := val.(type) {
+	case *ast.BasicLit:
+		b.WriteString(v.Value)
+	case *ast.CompositeLit:
+		switch w := v.Value.(type) {
+		case *ast.CompositeLit_BasicLit:
+			writeSDLValue(b, w.BasicLit)
+		case *ast.CompositeLit_ListLit:
+			writeSDLValue(b, w.ListLit)
+		case *ast.CompositeLit_ObjLit:
+			writeSDLValue(b, w.ObjLit)
+		}
+	case *ast.ListLit:
+		b.WriteByte('[')
+		switch w := v.List.(type) {
+		case *ast.ListLit_BasicList:
+			for i, item := range w.BasicList.Values {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				writeSDLValue(b, item)
+			}
+		case *ast.ListLit_CompositeList:
+			for i, item := range w.CompositeList.Values {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				writeSDLValue(b, item)
+			}
+		}
+		b.WriteByte(']')
+	case *ast.ObjLit:
+		b.WriteByte('{')
+		for i, p := range v.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(p.Key.Name)
+			b.WriteString(": ")
+			writeSDLValue(b, p.Val)
+		}
+		b.WriteByte('}')
+	}
+}
@@ -0,0 +1,371 @@
+// Package graph contains the type reference graph of GraphQL Documents.
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// Kind is the kind of a type.
+type Kind string
+
+// Kinds of types, which are part of the graph.
+const (
+	Object    Kind = "object"
+	Interface Kind = "interface"
+	Union     Kind = "union"
+	Input     Kind = "input"
+	Enum      Kind = "enum"
+)
+
+// EdgeKind is the kind of reference between two types.
+type EdgeKind string
+
+// Kinds of references between types.
+const (
+	// FieldEdge references the type of a field.
+	FieldEdge EdgeKind = "field"
+
+	// ImplementsEdge references an interface implemented by an object.
+	ImplementsEdge EdgeKind = "implements"
+
+	// MemberEdge references a member of a union.
+	MemberEdge EdgeKind = "member"
+)
+
+// Node is a type in the graph.
+type Node struct {
+	Name string `json:"name"`
+	Kind Kind   `json:"kind"`
+
+	// Fields contains the fields of objects, interfaces and
+	// inputs, as well as the values of enums, which have no type.
+	//
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Field is a field of a type.
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// Edge is a reference from one type to another.
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+
+	// Label is the name of the field for field references.
+	Label string `json:"label,omitempty"`
+}
+
+// Graph is the type reference graph of a schema.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+}
+
+// Types returns the type reference graph of the given documents. Scalars,
+// directives and the schema declaration are not part of the graph.
+//
+func Types(docs ...*ast.Document) *Graph {
+	g := new(Graph)
+
+	var edges []*Edge
+	for _, doc := range docs {
+		for _, decl := range doc.Types {
+			ts, ok := decl.Spec.(*ast.TypeDecl_TypeSpec)
+			if !ok || ts.TypeSpec.Name == nil {
+				continue
+			}
+			name := ts.TypeSpec.Name.Name
+
+			n := &Node{Name: name}
+			switch v := ts.TypeSpec.Type.(type) {
+			case *ast.TypeSpec_Object:
+				n.Kind = Object
+				for _, i := range v.Object.Interfaces {
+					edges = append(edges, &Edge{From: name, To: i.Name, Kind: ImplementsEdge})
+				}
+				edges = addFields(n, v.Object.Fields, edges)
+			case *ast.TypeSpec_Interface:
+				n.Kind = Interface
+				edges = addFields(n, v.Interface.Fields, edges)
+			case *ast.TypeSpec_Union:
+				n.Kind = Union
+				for _, m := range v.Union.Members {
+					edges = append(edges, &Edge{From: name, To: m.Name, Kind: MemberEdge})
+				}
+			case *ast.TypeSpec_Input:
+				n.Kind = Input
+				if v.Input.Fields != nil {
+					for _, f := range v.Input.Fields.List {
+						typ := InputValueType(f)
+						n.Fields = append(n.Fields, Field{Name: f.Name.Name, Type: TypeString(typ)})
+						edges = append(edges, &Edge{From: name, To: BaseName(typ), Kind: FieldEdge, Label: f.Name.Name})
+					}
+				}
+			case *ast.TypeSpec_Enum:
+				n.Kind = Enum
+				if v.Enum.Values != nil {
+					for _, f := range v.Enum.Values.List {
+						n.Fields = append(n.Fields, Field{Name: f.Name.Name})
+					}
+				}
+			default:
+				continue
+			}
+
+			g.Nodes = append(g.Nodes, n)
+		}
+	}
+
+	// Only keep references between types in the graph
+	nodes := g.nodeSet()
+	for _, e := range edges {
+		if nodes[e.To] {
+			g.Edges = append(g.Edges, e)
+		}
+	}
+	return g
+}
+
+func addFields(n *Node, fields *ast.FieldList, edges []*Edge) []*Edge {
+	if fields == nil {
+		return edges
+	}
+
+	for _, f := range fields.List {
+		typ := FieldType(f)
+		n.Fields = append(n.Fields, Field{Name: f.Name.Name, Type: TypeString(typ)})
+		edges = append(edges, &Edge{From: n.Name, To: BaseName(typ), Kind: FieldEdge, Label: f.Name.Name})
+	}
+	return edges
+}
+
+// FieldType returns the, possibly wrapped, type of a field i.e.
+// an *ast.Ident, *ast.List or *ast.NonNull.
+//
+func FieldType(f *ast.Field) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.Field_Ident:
+		return v.Ident
+	case *ast.Field_List:
+		return v.List
+	case *ast.Field_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// InputValueType returns the, possibly wrapped, type of an argument or
+// input field i.e. an *ast.Ident, *ast.List or *ast.NonNull.
+//
+func InputValueType(f *ast.InputValue) interface{} {
+	switch v := f.Type.(type) {
+	case *ast.InputValue_Ident:
+		return v.Ident
+	case *ast.InputValue_List:
+		return v.List
+	case *ast.InputValue_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+// unwrap returns the type wrapped by a list or non-null type.
+func unwrap(typ interface{}) interface{} {
+	switch v := typ.(type) {
+	case *ast.List:
+		switch w := v.Type.(type) {
+		case *ast.List_Ident:
+			return w.Ident
+		case *ast.List_List:
+			return w.List
+		case *ast.List_NonNull:
+			return w.NonNull
+		}
+	case *ast.NonNull:
+		switch w := v.Type.(type) {
+		case *ast.NonNull_Ident:
+			return w.Ident
+		case *ast.NonNull_List:
+			return w.List
+		}
+	}
+	return nil
+}
+
+// BaseName returns the name of the named type of a, possibly wrapped, type.
+func BaseName(typ interface{}) string {
+	for typ != nil {
+		if id, ok := typ.(*ast.Ident); ok {
+			return id.Name
+		}
+		typ = unwrap(typ)
+	}
+	return ""
+}
+
+// TypeString returns the GraphQL notation of a, possibly wrapped, type e.g. [User!]!.
+func TypeString(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		return "[" + TypeString(unwrap(v)) + "]"
+	case *ast.NonNull:
+		return TypeString(unwrap(v)) + "!"
+	}
+	return ""
+}
+
+func (g *Graph) nodeSet() map[string]bool {
+	nodes := make(map[string]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		nodes[n.Name] = true
+	}
+	return nodes
+}
+
+// Focus returns the subgraph of all types within depth references of the
+// named type, regardless of the direction of the references. A negative
+// depth doesn't limit the subgraph.
+//
+func (g *Graph) Focus(name string, depth int) (*Graph, error) {
+	if !g.nodeSet()[name] {
+		return nil, fmt.Errorf("graph: unknown type: %s", name)
+	}
+
+	adj := make(map[string][]string)
+	for _, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], e.To)
+		adj[e.To] = append(adj[e.To], e.From)
+	}
+
+	seen := map[string]bool{name: true}
+	frontier := []string{name}
+	for d := 0; len(frontier) > 0 && (depth < 0 || d < depth); d++ {
+		var next []string
+		for _, n := range frontier {
+			for _, m := range adj[n] {
+				if !seen[m] {
+					seen[m] = true
+					next = append(next, m)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	sub := new(Graph)
+	for _, n := range g.Nodes {
+		if seen[n.Name] {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if seen[e.From] && seen[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub, nil
+}
+
+var recordEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`|`, `\|`,
+	`<`, `\<`,
+	`>`, `\>`,
+)
+
+// WriteDOT renders the graph in the Graphviz DOT language.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b bytes.Buffer
+	b.WriteString("digraph types {\n")
+	b.WriteString("\tnode [shape=record];\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\t%s [label=\"{", strconv.Quote(n.Name))
+		if n.Kind != Object {
+			fmt.Fprintf(&b, "%s\\n", recordEscaper.Replace("<<"+string(n.Kind)+">>"))
+		}
+		b.WriteString(recordEscaper.Replace(n.Name))
+		if len(n.Fields) > 0 {
+			b.WriteByte('|')
+			for _, f := range n.Fields {
+				b.WriteString(recordEscaper.Replace(f.Name))
+				if f.Type != "" {
+					b.WriteString(": ")
+					b.WriteString(recordEscaper.Replace(f.Type))
+				}
+				b.WriteString(`\l`)
+			}
+		}
+		b.WriteString("}\"];\n")
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s", strconv.Quote(e.From), strconv.Quote(e.To))
+		switch e.Kind {
+		case FieldEdge:
+			fmt.Fprintf(&b, " [label=%s]", strconv.Quote(e.Label))
+		case ImplementsEdge:
+			b.WriteString(" [style=dashed, arrowhead=empty]")
+		case MemberEdge:
+			b.WriteString(" [arrowhead=odiamond]")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid class diagram.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b bytes.Buffer
+	b.WriteString("classDiagram\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\tclass %s {\n", n.Name)
+		switch n.Kind {
+		case Interface, Union, Input:
+			fmt.Fprintf(&b, "\t\t<<%s>>\n", n.Kind)
+		case Enum:
+			b.WriteString("\t\t<<enumeration>>\n")
+		}
+		for _, f := range n.Fields {
+			b.WriteString("\t\t")
+			b.WriteString(f.Name)
+			if f.Type != "" {
+				b.WriteString(": ")
+				b.WriteString(f.Type)
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString("\t}\n")
+	}
+
+	for _, e := range g.Edges {
+		switch e.Kind {
+		case FieldEdge:
+			fmt.Fprintf(&b, "\t%s --> %s : %s\n", e.From, e.To, e.Label)
+		case ImplementsEdge:
+			fmt.Fprintf(&b, "\t%s ..|> %s\n", e.From, e.To)
+		case MemberEdge:
+			fmt.Fprintf(&b, "\t%s o-- %s\n", e.From, e.To)
+		}
+	}
+
+	_, err := b.WriteTo(w)
+	return err
+}
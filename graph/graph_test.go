@@ -0,0 +1,198 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+const testSchema = `scalar Time
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	friends: [User!]!
+	posts: [Post]
+}
+
+type Post implements Node {
+	id: ID!
+	author: User
+	at: Time
+}
+
+union Result = User | Post
+
+input Filter {
+	role: Role
+}
+
+enum Role {
+	ADMIN
+	USER
+}`
+
+func testGraph(t *testing.T) *Graph {
+	doc, err := parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(testSchema), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Types(doc)
+}
+
+func TestTypes(t *testing.T) {
+	g := testGraph(t)
+
+	if len(g.Nodes) != 6 {
+		t.Fatalf("expected 6 nodes, but got: %d", len(g.Nodes))
+	}
+
+	var user *Node
+	for _, n := range g.Nodes {
+		if n.Name == "User" {
+			user = n
+		}
+	}
+	if user == nil || user.Kind != Object || len(user.Fields) != 3 || user.Fields[1].Type != "[User!]!" {
+		t.Fatalf("unexpected user node: %#v", user)
+	}
+
+	counts := make(map[EdgeKind]int)
+	for _, e := range g.Edges {
+		if e.To == "Time" || e.To == "ID" {
+			t.Errorf("unexpected edge to scalar: %#v", e)
+		}
+		counts[e.Kind]++
+	}
+	if counts[FieldEdge] != 4 || counts[ImplementsEdge] != 2 || counts[MemberEdge] != 2 {
+		t.Errorf("unexpected edges: %v", counts)
+	}
+}
+
+func TestGraph_Focus(t *testing.T) {
+	g := testGraph(t)
+
+	testCases := []struct {
+		Name  string
+		Depth int
+		Nodes string
+	}{
+		{
+			Name:  "Self",
+			Depth: 0,
+			Nodes: "Role",
+		},
+		{
+			Name:  "Adjacent",
+			Depth: 1,
+			Nodes: "Filter,Role",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			sub, err := g.Focus("Role", testCase.Depth)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if names := nodeNames(sub); names != testCase.Nodes {
+				subT.Errorf("expected nodes: %s, but got: %s", testCase.Nodes, names)
+			}
+		})
+	}
+
+	t.Run("Unlimited", func(subT *testing.T) {
+		sub, err := g.Focus("Node", -1)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if names := nodeNames(sub); names != "Node,User,Post,Result" {
+			subT.Errorf("unexpected nodes: %s", names)
+		}
+		if len(sub.Edges) != 7 {
+			subT.Errorf("expected 7 edges, but got: %d", len(sub.Edges))
+		}
+	})
+
+	t.Run("Unknown", func(subT *testing.T) {
+		_, err := g.Focus("Unknown", 1)
+		if err == nil {
+			subT.Error("expected error for unknown type")
+		}
+	})
+}
+
+func nodeNames(g *Graph) string {
+	names := make([]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		names[i] = n.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	g := &Graph{
+		Nodes: []*Node{
+			{Name: "User", Kind: Object, Fields: []Field{{Name: "friends", Type: "[User!]!"}}},
+			{Name: "Role", Kind: Enum, Fields: []Field{{Name: "ADMIN"}}},
+		},
+		Edges: []*Edge{{From: "User", To: "User", Kind: FieldEdge, Label: "friends"}},
+	}
+
+	var b bytes.Buffer
+	err := g.WriteDOT(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := `digraph types {
+	node [shape=record];
+	"User" [label="{User|friends: [User!]!\l}"];
+	"Role" [label="{\<\<enum\>\>\nRole|ADMIN\l}"];
+	"User" -> "User" [label="friends"];
+}
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
+func TestGraph_WriteMermaid(t *testing.T) {
+	g := &Graph{
+		Nodes: []*Node{
+			{Name: "Node", Kind: Interface, Fields: []Field{{Name: "id", Type: "ID!"}}},
+			{Name: "User", Kind: Object, Fields: []Field{{Name: "id", Type: "ID!"}}},
+		},
+		Edges: []*Edge{{From: "User", To: "Node", Kind: ImplementsEdge}},
+	}
+
+	var b bytes.Buffer
+	err := g.WriteMermaid(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := `classDiagram
+	class Node {
+		<<interface>>
+		id: ID!
+	}
+	class User {
+		id: ID!
+	}
+	User ..|> Node
+`
+	if b.String() != ex {
+		t.Errorf("expected:\n%s\nbut got:\n%s", ex, b.String())
+	}
+}
+
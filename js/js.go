@@ -133,15 +133,9 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	mask |= listBit | nonNullBit
 	mask |= intBit | floatBit | stringBit | booleanBit | idBit
 
-	// Generate schema
-	if doc.Schema != nil {
-		g.log.Info("generating schema")
-		mask &= ^schemaBit
-		g.generateSchema(gOpts, doc.Schema.Spec.(*ast.TypeDecl_TypeSpec).TypeSpec)
-		g.P()
-	}
-
-	// Generate types
+	// Generate types, where the schema is generated in place, such
+	// that it follows its root operation types in topological order
+	//
 	g.log.Info("generating types")
 	totalTypes := len(doc.Types) - 1
 	for i, d := range doc.Types {
@@ -150,6 +144,10 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 			continue
 		}
 		if _, ok = ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+			g.log.Info("generating schema")
+			mask &= ^schemaBit
+			g.generateSchema(gOpts, ts.TypeSpec)
+			g.P()
 			continue
 		}
 
@@ -29,6 +29,13 @@ func main() {
 		"Generate Go source.",
 	)
 
+	// Register Go client generator
+	cli.RegisterGenerator(&golang.ClientGenerator{},
+		"goclient_out",
+		"goclient_opt",
+		"Generate a Go client for GraphQL operations.",
+	)
+
 	// Register Javascript generator
 	cli.RegisterGenerator(&js.Generator{},
 		"js_out",
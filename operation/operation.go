@@ -0,0 +1,156 @@
+// Package operation parses and validates GraphQL executable documents,
+// i.e. documents containing operations and fragments. Types, arguments
+// and values are represented by the same nodes as in schema documents.
+//
+package operation
+
+import (
+	"context"
+	"fmt"
+	"text/scanner"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// Operation types
+const (
+	Query        = "query"
+	Mutation     = "mutation"
+	Subscription = "subscription"
+)
+
+type docsKey struct{}
+
+// WithDocuments returns a context.Context carrying the executable
+// documents, which were compiled and validated along with the schema,
+// for generators.
+//
+func WithDocuments(ctx context.Context, docs []*Document) context.Context {
+	return context.WithValue(ctx, docsKey{}, docs)
+}
+
+// Documents returns the executable documents or nil, if there are none.
+func Documents(ctx context.Context) []*Document {
+	docs, _ := ctx.Value(docsKey{}).([]*Document)
+	return docs
+}
+
+// Document is an executable document.
+type Document struct {
+	// Name is the name of the document.
+	Name string
+
+	// Operations are the operations in the order they were declared.
+	Operations []*Operation
+
+	// Fragments are the fragments in the order they were declared.
+	Fragments []*Fragment
+}
+
+// Fragment returns the named fragment or nil, if there is none.
+func (doc *Document) Fragment(name string) *Fragment {
+	for _, f := range doc.Fragments {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// Operation is a query, mutation or subscription.
+type Operation struct {
+	Pos scanner.Position
+
+	// Type is one of Query, Mutation or Subscription.
+	Type string
+
+	// Name is empty for an anonymous operation.
+	Name string
+
+	Variables    []*Variable
+	Directives   []*ast.DirectiveLit
+	SelectionSet []Selection
+
+	// Source is the operation as it was written.
+	Source string
+}
+
+// Variable is a variable definition of an operation.
+type Variable struct {
+	Pos  scanner.Position
+	Name string
+
+	// Type is an *ast.Ident, *ast.List or *ast.NonNull.
+	Type interface{}
+
+	// Default is an *ast.BasicLit, *ast.CompositeLit or nil.
+	Default interface{}
+}
+
+// Fragment is a named fragment.
+type Fragment struct {
+	Pos           scanner.Position
+	Name          string
+	TypeCondition string
+	Directives    []*ast.DirectiveLit
+	SelectionSet  []Selection
+
+	// Source is the fragment as it was written.
+	Source string
+}
+
+// Selection is a *Field, *FragmentSpread or *InlineFragment.
+type Selection interface {
+	selection()
+}
+
+// Field selects a field. Variables are referenced by
+// basic literals of kind token.Token_VAR.
+//
+type Field struct {
+	Pos          scanner.Position
+	Alias        string
+	Name         string
+	Args         []*ast.Arg
+	Directives   []*ast.DirectiveLit
+	SelectionSet []Selection
+}
+
+// Key returns the response key of the field i.e. its alias or name.
+func (f *Field) Key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread spreads a named fragment.
+type FragmentSpread struct {
+	Pos        scanner.Position
+	Name       string
+	Directives []*ast.DirectiveLit
+}
+
+// InlineFragment is a fragment without a name,
+// whose type condition may be empty.
+//
+type InlineFragment struct {
+	Pos           scanner.Position
+	TypeCondition string
+	Directives    []*ast.DirectiveLit
+	SelectionSet  []Selection
+}
+
+func (*Field) selection()          {}
+func (*FragmentSpread) selection() {}
+func (*InlineFragment) selection() {}
+
+// Error is a syntax or validation error.
+type Error struct {
+	Pos scanner.Position
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
@@ -0,0 +1,489 @@
+// parse.go parses executable documents
+
+package operation
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/scanner"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+type parser struct {
+	s   scanner.Scanner
+	src []byte
+
+	// tok, lit and pos describe the current token
+	tok rune
+	lit string
+	pos scanner.Position
+
+	// end is the offset immediately after the previous token.
+	end int
+}
+
+// bailout is panicked with on the first error, which ends parsing.
+type bailout struct {
+	err *Error
+}
+
+func newParser(name string, src []byte) *parser {
+	p := &parser{src: src}
+	p.s.Init(bytes.NewReader(src))
+	p.s.Filename = name
+	p.s.Mode = scanner.ScanIdents | scanner.ScanInts | scanner.ScanFloats | scanner.ScanStrings
+	p.s.Whitespace = scanner.GoWhitespace | 1<<','
+	p.s.Error = func(s *scanner.Scanner, msg string) {
+		p.errorf(s.Pos(), "%s", msg)
+	}
+	return p
+}
+
+// IsExecutable reports whether src is an executable document rather than
+// a schema document, i.e. whether it starts with an operation or fragment.
+//
+func IsExecutable(src []byte) bool {
+	p := newParser("", src)
+	p.s.Error = func(*scanner.Scanner, string) {}
+
+	p.next()
+	return p.tok == '{' || p.tok == scanner.Ident && isDefinition(p.lit)
+}
+
+func isDefinition(keyword string) bool {
+	switch keyword {
+	case Query, Mutation, Subscription, "fragment":
+		return true
+	}
+	return false
+}
+
+// Parse parses an executable document. The document is named
+// by the caller, while positions refer to the given filename.
+//
+func Parse(filename string, src []byte) (doc *Document, err error) {
+	p := newParser(filename, src)
+	defer func() {
+		if r := recover(); r != nil {
+			b, ok := r.(bailout)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, b.err
+		}
+	}()
+
+	doc = new(Document)
+	p.next()
+	for p.tok != scanner.EOF {
+		switch {
+		case p.tok == '{', p.tok == scanner.Ident && p.lit != "fragment" && isDefinition(p.lit):
+			doc.Operations = append(doc.Operations, p.parseOperation())
+		case p.tok == scanner.Ident && p.lit == "fragment":
+			doc.Fragments = append(doc.Fragments, p.parseFragment())
+		default:
+			p.errorf(p.pos, "unexpected %s, expected an operation or fragment", p.desc())
+		}
+	}
+	if len(doc.Operations) == 0 && len(doc.Fragments) == 0 {
+		p.errorf(p.pos, "document has no operations or fragments")
+	}
+	return doc, nil
+}
+
+func (p *parser) errorf(pos scanner.Position, format string, args ...interface{}) {
+	panic(bailout{err: &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}})
+}
+
+// next advances to the next token, skipping any comments.
+func (p *parser) next() {
+	p.end = p.s.Pos().Offset
+	for {
+		p.tok = p.s.Scan()
+		if p.tok != '#' {
+			break
+		}
+
+		for ch := p.s.Peek(); ch != '\n' && ch != scanner.EOF; ch = p.s.Peek() {
+			p.s.Next()
+		}
+	}
+	p.pos = p.s.Position
+	p.lit = p.s.TokenText()
+
+	// The first two quotes of a block string are scanned as an empty string
+	if p.tok == scanner.String && p.lit == `""` && p.s.Peek() == '"' {
+		p.lit = p.scanBlockString()
+	}
+}
+
+// desc describes the current token for errors.
+func (p *parser) desc() string {
+	if p.tok == scanner.EOF {
+		return "end of document"
+	}
+	return strconv.Quote(p.lit)
+}
+
+func (p *parser) expect(tok rune) {
+	if p.tok != tok {
+		p.errorf(p.pos, "unexpected %s, expected %s", p.desc(), scanner.TokenString(tok))
+	}
+	p.next()
+}
+
+func (p *parser) name() string {
+	if p.tok != scanner.Ident {
+		p.errorf(p.pos, "unexpected %s, expected a name", p.desc())
+	}
+	name := p.lit
+	p.next()
+	return name
+}
+
+// scanBlockString scans the rest of a block string and
+// returns its value as a double quoted string.
+//
+func (p *parser) scanBlockString() string {
+	p.s.Next()
+
+	var b strings.Builder
+	for {
+		ch := p.s.Next()
+		switch ch {
+		case scanner.EOF:
+			p.errorf(p.pos, "block string not terminated")
+		case '\\':
+			n := p.quotes()
+			if n < 3 {
+				b.WriteByte('\\')
+			}
+			b.WriteString(strings.Repeat(`"`, n))
+			continue
+		case '"':
+			n := 1 + p.quotes()
+			if n == 3 {
+				return strconv.Quote(blockStringValue(b.String()))
+			}
+			b.WriteString(strings.Repeat(`"`, n))
+			continue
+		}
+		b.WriteRune(ch)
+	}
+}
+
+// quotes consumes up to three quotes and returns how many there were.
+func (p *parser) quotes() (n int) {
+	for n < 3 && p.s.Peek() == '"' {
+		p.s.Next()
+		n++
+	}
+	return
+}
+
+// blockStringValue removes the common indentation and
+// any leading and trailing blank lines of a block string.
+//
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.Replace(raw, "\r\n", "\n", -1), "\n")
+
+	indent := -1
+	for _, l := range lines[1:] {
+		trimmed := strings.TrimLeft(l, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(l) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = ""
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (p *parser) parseOperation() *Operation {
+	op := &Operation{Pos: p.pos, Type: Query}
+	if p.tok == '{' {
+		op.SelectionSet = p.parseSelectionSet()
+		op.Source = string(p.src[op.Pos.Offset:p.end])
+		return op
+	}
+
+	op.Type = p.lit
+	p.next()
+	if p.tok == scanner.Ident {
+		op.Name = p.name()
+	}
+	if p.tok == '(' {
+		p.next()
+		for {
+			op.Variables = append(op.Variables, p.parseVariable())
+			if p.tok == ')' {
+				break
+			}
+		}
+		p.next()
+	}
+	op.Directives = p.parseDirectives(false)
+	op.SelectionSet = p.parseSelectionSet()
+	op.Source = string(p.src[op.Pos.Offset:p.end])
+	return op
+}
+
+func (p *parser) parseVariable() *Variable {
+	v := &Variable{Pos: p.pos}
+	p.expect('$')
+	v.Name = p.name()
+	p.expect(':')
+	v.Type = p.parseType()
+	if p.tok == '=' {
+		p.next()
+		v.Default = p.parseValue(true)
+	}
+
+	// Directives on variables only annotate them
+	p.parseDirectives(true)
+	return v
+}
+
+func (p *parser) parseFragment() *Fragment {
+	f := &Fragment{Pos: p.pos}
+	p.next()
+	if p.tok == scanner.Ident && p.lit == "on" {
+		p.errorf(p.pos, "fragment must be named")
+	}
+	f.Name = p.name()
+	if p.tok != scanner.Ident || p.lit != "on" {
+		p.errorf(p.pos, "unexpected %s, expected \"on\"", p.desc())
+	}
+	p.next()
+	f.TypeCondition = p.name()
+	f.Directives = p.parseDirectives(false)
+	f.SelectionSet = p.parseSelectionSet()
+	f.Source = string(p.src[f.Pos.Offset:p.end])
+	return f
+}
+
+func (p *parser) parseSelectionSet() (sels []Selection) {
+	p.expect('{')
+	if p.tok == '}' {
+		p.errorf(p.pos, "selection set must not be empty")
+	}
+	for p.tok != '}' {
+		sels = append(sels, p.parseSelection())
+	}
+	p.next()
+	return
+}
+
+func (p *parser) parseSelection() Selection {
+	pos := p.pos
+	if p.tok != '.' {
+		f := &Field{Pos: pos, Name: p.name()}
+		if p.tok == ':' {
+			p.next()
+			f.Alias, f.Name = f.Name, p.name()
+		}
+		if p.tok == '(' {
+			f.Args = p.parseArgs(false)
+		}
+		f.Directives = p.parseDirectives(false)
+		if p.tok == '{' {
+			f.SelectionSet = p.parseSelectionSet()
+		}
+		return f
+	}
+
+	p.expect('.')
+	p.expect('.')
+	p.expect('.')
+	if p.tok == scanner.Ident && p.lit != "on" {
+		return &FragmentSpread{Pos: pos, Name: p.name(), Directives: p.parseDirectives(false)}
+	}
+
+	f := &InlineFragment{Pos: pos}
+	if p.tok == scanner.Ident {
+		p.next()
+		f.TypeCondition = p.name()
+	}
+	f.Directives = p.parseDirectives(false)
+	f.SelectionSet = p.parseSelectionSet()
+	return f
+}
+
+func (p *parser) parseArgs(isConst bool) (args []*ast.Arg) {
+	p.expect('(')
+	for {
+		arg := &ast.Arg{Name: &ast.Ident{Name: p.name()}}
+		p.expect(':')
+
+		switch v := p.parseValue(isConst).(type) {
+		case *ast.BasicLit:
+			arg.Value = &ast.Arg_BasicLit{BasicLit: v}
+		case *ast.CompositeLit:
+			arg.Value = &ast.Arg_CompositeLit{CompositeLit: v}
+		}
+		args = append(args, arg)
+
+		if p.tok == ')' {
+			p.next()
+			return
+		}
+	}
+}
+
+func (p *parser) parseDirectives(isConst bool) (dirs []*ast.DirectiveLit) {
+	for p.tok == '@' {
+		p.next()
+		d := &ast.DirectiveLit{Name: p.name()}
+		if p.tok == '(' {
+			d.Args = &ast.CallExpr{Args: p.parseArgs(isConst)}
+		}
+		dirs = append(dirs, d)
+	}
+	return
+}
+
+// parseType parses a type reference into an *ast.Ident, *ast.List or *ast.NonNull.
+func (p *parser) parseType() interface{} {
+	var typ interface{}
+	if p.tok == '[' {
+		p.next()
+		l := new(ast.List)
+		switch v := p.parseType().(type) {
+		case *ast.Ident:
+			l.Type = &ast.List_Ident{Ident: v}
+		case *ast.List:
+			l.Type = &ast.List_List{List: v}
+		case *ast.NonNull:
+			l.Type = &ast.List_NonNull{NonNull: v}
+		}
+		p.expect(']')
+		typ = l
+	} else {
+		typ = &ast.Ident{Name: p.name()}
+	}
+
+	if p.tok != '!' {
+		return typ
+	}
+	p.next()
+
+	nn := new(ast.NonNull)
+	switch v := typ.(type) {
+	case *ast.Ident:
+		nn.Type = &ast.NonNull_Ident{Ident: v}
+	case *ast.List:
+		nn.Type = &ast.NonNull_List{List: v}
+	}
+	return nn
+}
+
+// parseValue parses a value into an *ast.BasicLit or *ast.CompositeLit.
+// Constant values, i.e. default values, must not reference variables.
+//
+func (p *parser) parseValue(isConst bool) interface{} {
+	pos := p.pos
+	switch p.tok {
+	case '$':
+		if isConst {
+			p.errorf(pos, "variables are not allowed in constant values")
+		}
+		p.next()
+		return &ast.BasicLit{Kind: token.Token_VAR, Value: p.name()}
+	case '-':
+		p.next()
+		if p.tok != scanner.Int && p.tok != scanner.Float {
+			p.errorf(p.pos, "unexpected %s, expected a number", p.desc())
+		}
+		p.lit = "-" + p.lit
+		return p.parseValue(isConst)
+	case scanner.Int:
+		return p.basicLit(token.Token_INT)
+	case scanner.Float:
+		return p.basicLit(token.Token_FLOAT)
+	case scanner.String:
+		return p.basicLit(token.Token_STRING)
+	case scanner.Ident:
+		switch p.lit {
+		case "true", "false":
+			return p.basicLit(token.Token_BOOL)
+		case "null":
+			return p.basicLit(token.Token_NULL)
+		}
+		return p.basicLit(token.Token_IDENT)
+	case '[':
+		p.next()
+		var vals []interface{}
+		for p.tok != ']' {
+			vals = append(vals, p.parseValue(isConst))
+		}
+		p.next()
+		return &ast.CompositeLit{Value: &ast.CompositeLit_ListLit{ListLit: listLit(vals)}}
+	case '{':
+		p.next()
+		obj := new(ast.ObjLit)
+		for p.tok != '}' {
+			key := &ast.Ident{Name: p.name()}
+			p.expect(':')
+			obj.Fields = append(obj.Fields, &ast.ObjLit_Pair{Key: key, Val: compositeLit(p.parseValue(isConst))})
+		}
+		p.next()
+		return &ast.CompositeLit{Value: &ast.CompositeLit_ObjLit{ObjLit: obj}}
+	}
+	p.errorf(pos, "unexpected %s, expected a value", p.desc())
+	return nil
+}
+
+func (p *parser) basicLit(kind token.Token) *ast.BasicLit {
+	lit := &ast.BasicLit{Kind: kind, Value: p.lit}
+	p.next()
+	return lit
+}
+
+// listLit returns a list of basic literals, unless any value is composite.
+func listLit(vals []interface{}) *ast.ListLit {
+	basic := make([]*ast.BasicLit, 0, len(vals))
+	for _, v := range vals {
+		lit, ok := v.(*ast.BasicLit)
+		if !ok {
+			break
+		}
+		basic = append(basic, lit)
+	}
+	if len(basic) == len(vals) {
+		return &ast.ListLit{List: &ast.ListLit_BasicList{BasicList: &ast.ListLit_Basic{Values: basic}}}
+	}
+
+	comp := make([]*ast.CompositeLit, len(vals))
+	for i, v := range vals {
+		comp[i] = compositeLit(v)
+	}
+	return &ast.ListLit{List: &ast.ListLit_CompositeList{CompositeList: &ast.ListLit_Composite{Values: comp}}}
+}
+
+func compositeLit(v interface{}) *ast.CompositeLit {
+	if lit, ok := v.(*ast.BasicLit); ok {
+		return &ast.CompositeLit{Value: &ast.CompositeLit_BasicLit{BasicLit: lit}}
+	}
+	return v.(*ast.CompositeLit)
+}
@@ -0,0 +1,167 @@
+package operation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+func TestIsExecutable(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Exec bool
+	}{
+		{Name: "Query", Src: "query Q { a }", Exec: true},
+		{Name: "Shorthand", Src: "# comment\n{ a }", Exec: true},
+		{Name: "Fragment", Src: "fragment F on T { a }", Exec: true},
+		{Name: "Type", Src: "type Query { a: String }"},
+		{Name: "Description", Src: "\"query\" type Query { a: String }"},
+		{Name: "Import", Src: `@import(paths: ["a.gql"])`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			if exec := IsExecutable([]byte(testCase.Src)); exec != testCase.Exec {
+				subT.Errorf("expected %v but got %v", testCase.Exec, exec)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	src := `# Fetches a user
+query GetUser($id: ID!, $first: Int = 10, $filter: [String!]) @cached {
+	user(id: $id) {
+		id
+		handle: name
+		friends(first: $first, where: {names: $filter, active: true}, order: [NAME, -1]) @include(if: true) {
+			...UserFields
+		}
+		... on Admin {
+			level
+		}
+		... @skip(if: false) {
+			bio(format: """
+				markdown
+			""")
+		}
+	}
+}
+
+fragment UserFields on User {
+	id
+}
+
+{ viewer { id } }`
+
+	doc, err := Parse("ops.graphql", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Operations) != 2 || len(doc.Fragments) != 1 {
+		t.Fatalf("expected 2 operations and 1 fragment but got %d and %d", len(doc.Operations), len(doc.Fragments))
+	}
+
+	op := doc.Operations[0]
+	if op.Type != Query || op.Name != "GetUser" || len(op.Directives) != 1 {
+		t.Errorf("unexpected operation: %s %s", op.Type, op.Name)
+	}
+	if !strings.HasPrefix(op.Source, "query GetUser(") || !strings.HasSuffix(op.Source, "}\n}") {
+		t.Errorf("unexpected source: %q", op.Source)
+	}
+	if op.Pos.Line != 2 || op.Pos.Filename != "ops.graphql" {
+		t.Errorf("unexpected position: %s", op.Pos)
+	}
+
+	if len(op.Variables) != 3 {
+		t.Fatalf("expected 3 variables but got %d", len(op.Variables))
+	}
+	if typ := typeString(op.Variables[0].Type); typ != "ID!" {
+		t.Errorf("expected ID! but got %s", typ)
+	}
+	if typ := typeString(op.Variables[2].Type); typ != "[String!]" {
+		t.Errorf("expected [String!] but got %s", typ)
+	}
+	if def, ok := op.Variables[1].Default.(*ast.BasicLit); !ok || def.Kind != token.Token_INT || def.Value != "10" {
+		t.Errorf("unexpected default: %v", op.Variables[1].Default)
+	}
+
+	user := op.SelectionSet[0].(*Field)
+	if user.Name != "user" || len(user.SelectionSet) != 5 {
+		t.Fatalf("unexpected field: %s with %d selections", user.Name, len(user.SelectionSet))
+	}
+	if id := user.Args[0].Value.(*ast.Arg_BasicLit).BasicLit; id.Kind != token.Token_VAR || id.Value != "id" {
+		t.Errorf("unexpected argument: %v", id)
+	}
+
+	handle := user.SelectionSet[1].(*Field)
+	if handle.Alias != "handle" || handle.Name != "name" || handle.Key() != "handle" {
+		t.Errorf("unexpected alias: %s: %s", handle.Alias, handle.Name)
+	}
+
+	friends := user.SelectionSet[2].(*Field)
+	if len(friends.Args) != 3 || len(friends.Directives) != 1 {
+		t.Fatalf("unexpected friends field: %v", friends)
+	}
+	where := friends.Args[1].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ObjLit).ObjLit
+	if len(where.Fields) != 2 || where.Fields[0].Key.Name != "names" {
+		t.Errorf("unexpected object: %v", where)
+	}
+	order := friends.Args[2].Value.(*ast.Arg_CompositeLit).CompositeLit.Value.(*ast.CompositeLit_ListLit).ListLit
+	vals := order.List.(*ast.ListLit_BasicList).BasicList.Values
+	if len(vals) != 2 || vals[0].Kind != token.Token_IDENT || vals[1].Value != "-1" {
+		t.Errorf("unexpected list: %v", vals)
+	}
+	if spread := friends.SelectionSet[0].(*FragmentSpread); spread.Name != "UserFields" {
+		t.Errorf("unexpected spread: %s", spread.Name)
+	}
+
+	if inline := user.SelectionSet[3].(*InlineFragment); inline.TypeCondition != "Admin" {
+		t.Errorf("unexpected type condition: %s", inline.TypeCondition)
+	}
+	inline := user.SelectionSet[4].(*InlineFragment)
+	if inline.TypeCondition != "" || len(inline.Directives) != 1 {
+		t.Errorf("unexpected inline fragment: %v", inline)
+	}
+	bio := inline.SelectionSet[0].(*Field).Args[0].Value.(*ast.Arg_BasicLit).BasicLit
+	if bio.Kind != token.Token_STRING || bio.Value != `"markdown"` {
+		t.Errorf("unexpected block string: %s", bio.Value)
+	}
+
+	if f := doc.Fragment("UserFields"); f == nil || f.TypeCondition != "User" || f.Source != "fragment UserFields on User {\n\tid\n}" {
+		t.Errorf("unexpected fragment: %v", f)
+	}
+	if shorthand := doc.Operations[1]; shorthand.Type != Query || shorthand.Name != "" || shorthand.Source != "{ viewer { id } }" {
+		t.Errorf("unexpected shorthand: %v", shorthand)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Src  string
+		Err  string
+	}{
+		{Name: "Empty", Src: "# nothing", Err: "ops.graphql:1:10: document has no operations or fragments"},
+		{Name: "Type", Src: "query { a } type T { a: Int }", Err: `1:13: unexpected "type", expected an operation or fragment`},
+		{Name: "EmptySelection", Src: "query { }", Err: "1:9: selection set must not be empty"},
+		{Name: "Unterminated", Src: "query { a(b: 1) ", Err: "unexpected end of document, expected a name"},
+		{Name: "ConstVar", Src: "query ($a: Int = $b) { a }", Err: "variables are not allowed in constant values"},
+		{Name: "UnnamedFragment", Src: "fragment on T { a }", Err: "fragment must be named"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			_, err := Parse("ops.graphql", []byte(testCase.Src))
+			if err == nil {
+				subT.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), testCase.Err) {
+				subT.Errorf("expected %q in %q", testCase.Err, err)
+			}
+		})
+	}
+}
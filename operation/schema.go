@@ -0,0 +1,216 @@
+// schema.go indexes the types operations are validated against
+
+package operation
+
+import (
+	"strings"
+
+	"github.com/gqlc/graphql/ast"
+)
+
+// Schema indexes the types of compiled schema documents.
+type Schema struct {
+	// Types maps the names of the declared types to their specs.
+	Types map[string]*ast.TypeSpec
+
+	// Roots maps the operation types to the names of their root types.
+	Roots map[string]string
+
+	// impls maps interfaces to the objects implementing them.
+	impls map[string][]string
+}
+
+// builtinScalars are the scalars every schema provides without declaring them.
+var builtinScalars = map[string]bool{
+	"Int":     true,
+	"Float":   true,
+	"String":  true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// typenameField is the meta field every composite type provides.
+var typenameField = &ast.Field{
+	Name: &ast.Ident{Name: "__typename"},
+	Type: &ast.Field_NonNull{NonNull: &ast.NonNull{
+		Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "String"}},
+	}},
+}
+
+// NewSchema indexes the types of the given documents. Since compiled
+// documents include the types they import, a type declared by more
+// than one document is only indexed once.
+//
+func NewSchema(docs []*ast.Document) *Schema {
+	s := &Schema{
+		Types: make(map[string]*ast.TypeSpec),
+		Roots: make(map[string]string, 3),
+		impls: make(map[string][]string),
+	}
+
+	var schema *ast.SchemaType
+	for _, doc := range docs {
+		for _, d := range doc.Types {
+			ts, ok := d.Spec.(*ast.TypeDecl_TypeSpec)
+			if !ok {
+				continue
+			}
+
+			if v, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Schema); ok {
+				if schema == nil {
+					schema = v.Schema
+				}
+				continue
+			}
+
+			name := ts.TypeSpec.Name.Name
+			if _, exists := s.Types[name]; exists {
+				continue
+			}
+			s.Types[name] = ts.TypeSpec
+
+			if obj, ok := ts.TypeSpec.Type.(*ast.TypeSpec_Object); ok {
+				for _, i := range obj.Object.Interfaces {
+					s.impls[i.Name] = append(s.impls[i.Name], name)
+				}
+			}
+		}
+	}
+
+	// Without a schema declaration, the types named after the operations are used
+	if schema == nil {
+		for _, op := range []string{"Query", "Mutation", "Subscription"} {
+			if _, ok := s.Types[op].GetType().(*ast.TypeSpec_Object); ok {
+				s.Roots[strings.ToLower(op)] = op
+			}
+		}
+		return s
+	}
+
+	if schema.RootOps != nil {
+		for _, f := range schema.RootOps.List {
+			if id, ok := f.Type.(*ast.Field_Ident); ok {
+				s.Roots[strings.ToLower(f.Name.Name)] = id.Ident.Name
+			}
+		}
+	}
+	return s
+}
+
+// Field returns the named field of an object or interface, as well as
+// the __typename field of any composite type, or nil, if there is none.
+//
+func (s *Schema) Field(typ, name string) *ast.Field {
+	var fields *ast.FieldList
+	switch v := s.Types[typ].GetType().(type) {
+	case *ast.TypeSpec_Object:
+		fields = v.Object.Fields
+	case *ast.TypeSpec_Interface:
+		fields = v.Interface.Fields
+	case *ast.TypeSpec_Union:
+	default:
+		return nil
+	}
+
+	if name == typenameField.Name.Name {
+		return typenameField
+	}
+	if fields == nil {
+		return nil
+	}
+	for _, f := range fields.List {
+		if f.Name.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// IsLeaf reports whether the named type is a scalar or enum.
+func (s *Schema) IsLeaf(name string) bool {
+	if builtinScalars[name] {
+		return true
+	}
+
+	switch s.Types[name].GetType().(type) {
+	case *ast.TypeSpec_Scalar, *ast.TypeSpec_Enum:
+		return true
+	}
+	return false
+}
+
+// IsComposite reports whether the named type is an object, interface or union.
+func (s *Schema) IsComposite(name string) bool {
+	switch s.Types[name].GetType().(type) {
+	case *ast.TypeSpec_Object, *ast.TypeSpec_Interface, *ast.TypeSpec_Union:
+		return true
+	}
+	return false
+}
+
+// PossibleTypes returns the objects a value of the named type may be.
+func (s *Schema) PossibleTypes(name string) []string {
+	switch v := s.Types[name].GetType().(type) {
+	case *ast.TypeSpec_Object:
+		return []string{name}
+	case *ast.TypeSpec_Interface:
+		return s.impls[name]
+	case *ast.TypeSpec_Union:
+		members := make([]string, len(v.Union.Members))
+		for i, m := range v.Union.Members {
+			members[i] = m.Name
+		}
+		return members
+	}
+	return nil
+}
+
+// TypeName returns the name of the named type
+// an *ast.Ident, *ast.List or *ast.NonNull wraps.
+//
+func TypeName(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		return TypeName(listElem(v))
+	case *ast.NonNull:
+		return TypeName(nonNullElem(v))
+	}
+	return ""
+}
+
+func listElem(l *ast.List) interface{} {
+	switch v := l.Type.(type) {
+	case *ast.List_Ident:
+		return v.Ident
+	case *ast.List_List:
+		return v.List
+	case *ast.List_NonNull:
+		return v.NonNull
+	}
+	return nil
+}
+
+func nonNullElem(nn *ast.NonNull) interface{} {
+	switch v := nn.Type.(type) {
+	case *ast.NonNull_Ident:
+		return v.Ident
+	case *ast.NonNull_List:
+		return v.List
+	}
+	return nil
+}
+
+// typeString returns a type as it's written in GraphQL.
+func typeString(typ interface{}) string {
+	switch v := typ.(type) {
+	case *ast.Ident:
+		return v.Name
+	case *ast.List:
+		return "[" + typeString(listElem(v)) + "]"
+	case *ast.NonNull:
+		return typeString(nonNullElem(v)) + "!"
+	}
+	return ""
+}
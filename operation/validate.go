@@ -0,0 +1,550 @@
+// validate.go validates executable documents against a schema
+
+package operation
+
+import (
+	"fmt"
+	"text/scanner"
+
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
+)
+
+// conditionArgs are the arguments of the @skip and @include directives.
+var conditionArgs = &ast.InputValueList{
+	List: []*ast.InputValue{
+		{
+			Name: &ast.Ident{Name: "if"},
+			Type: &ast.InputValue_NonNull{NonNull: &ast.NonNull{
+				Type: &ast.NonNull_Ident{Ident: &ast.Ident{Name: "Boolean"}},
+			}},
+		},
+	},
+}
+
+// usage is a reference to a variable.
+type usage struct {
+	pos  scanner.Position
+	name string
+
+	// typ is the type expected where the variable is used and
+	// hasDefault whether that location has a default value.
+	//
+	typ        interface{}
+	hasDefault bool
+}
+
+type validator struct {
+	s    *Schema
+	doc  *Document
+	errs []error
+
+	// usages and spreads map operations and fragments to the
+	// variables they use and the fragments they spread.
+	//
+	usages  map[interface{}][]usage
+	spreads map[interface{}][]string
+}
+
+// Validate validates the operations and fragments of a document
+// against a schema and returns any errors as *Error.
+//
+func Validate(doc *Document, s *Schema) []error {
+	v := &validator{
+		s:       s,
+		doc:     doc,
+		usages:  make(map[interface{}][]usage),
+		spreads: make(map[interface{}][]string),
+	}
+
+	v.checkNames()
+	for _, op := range doc.Operations {
+		v.checkOperation(op)
+	}
+	for _, f := range doc.Fragments {
+		v.checkFragment(f)
+	}
+	v.checkFragmentCycles()
+
+	// Variables are checked once every spread of an operation is known
+	used := make(map[string]bool)
+	for _, op := range doc.Operations {
+		v.checkVariables(op, used)
+	}
+	for _, f := range doc.Fragments {
+		if !used[f.Name] {
+			v.errorf(f.Pos, "fragment %s is never used", f.Name)
+		}
+	}
+	return v.errs
+}
+
+func (v *validator) errorf(pos scanner.Position, format string, args ...interface{}) {
+	v.errs = append(v.errs, &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// opName names an operation in errors.
+func opName(op *Operation) string {
+	if op.Name == "" {
+		return "anonymous " + op.Type
+	}
+	return op.Type + " " + op.Name
+}
+
+func (v *validator) checkNames() {
+	ops := make(map[string]bool, len(v.doc.Operations))
+	for _, op := range v.doc.Operations {
+		if op.Name == "" {
+			if len(v.doc.Operations) > 1 {
+				v.errorf(op.Pos, "anonymous operation must be the only operation of the document")
+			}
+			continue
+		}
+
+		if ops[op.Name] {
+			v.errorf(op.Pos, "operation %s is declared more than once", op.Name)
+		}
+		ops[op.Name] = true
+	}
+
+	frags := make(map[string]bool, len(v.doc.Fragments))
+	for _, f := range v.doc.Fragments {
+		if frags[f.Name] {
+			v.errorf(f.Pos, "fragment %s is declared more than once", f.Name)
+		}
+		frags[f.Name] = true
+	}
+}
+
+func (v *validator) checkOperation(op *Operation) {
+	vars := make(map[string]bool, len(op.Variables))
+	for _, vr := range op.Variables {
+		if vars[vr.Name] {
+			v.errorf(vr.Pos, "variable $%s is declared more than once", vr.Name)
+		}
+		vars[vr.Name] = true
+
+		name := TypeName(vr.Type)
+		if _, isInput := v.s.Types[name].GetType().(*ast.TypeSpec_Input); !isInput && !v.s.IsLeaf(name) {
+			v.errorf(vr.Pos, "variable $%s must have an input type, not %s", vr.Name, typeString(vr.Type))
+			continue
+		}
+		if vr.Default != nil {
+			v.checkValue(op, vr.Pos, vr.Type, false, vr.Default)
+		}
+	}
+	v.checkDirectives(op, op.Pos, op.Directives)
+
+	root, ok := v.s.Roots[op.Type]
+	if !ok {
+		v.errorf(op.Pos, "schema does not support %s operations", op.Type)
+		return
+	}
+	v.checkSelections(op, root, op.SelectionSet)
+}
+
+func (v *validator) checkFragment(f *Fragment) {
+	v.checkDirectives(f, f.Pos, f.Directives)
+	if !v.checkTypeCondition(f.Pos, f.TypeCondition) {
+		return
+	}
+	v.checkSelections(f, f.TypeCondition, f.SelectionSet)
+}
+
+// checkTypeCondition checks that a fragment is on a composite type.
+func (v *validator) checkTypeCondition(pos scanner.Position, typ string) bool {
+	if _, ok := v.s.Types[typ]; !ok && !builtinScalars[typ] {
+		v.errorf(pos, "unknown type %s", typ)
+		return false
+	}
+	if !v.s.IsComposite(typ) {
+		v.errorf(pos, "fragment can not be on %s, which is not an object, interface or union", typ)
+		return false
+	}
+	return true
+}
+
+// checkApplies checks that a fragment on typ may apply to a value of parent.
+func (v *validator) checkApplies(pos scanner.Position, parent, typ string) {
+	possible := make(map[string]bool)
+	for _, t := range v.s.PossibleTypes(parent) {
+		possible[t] = true
+	}
+	for _, t := range v.s.PossibleTypes(typ) {
+		if possible[t] {
+			return
+		}
+	}
+	v.errorf(pos, "fragment on %s can never apply to %s", typ, parent)
+}
+
+func (v *validator) checkSelections(owner interface{}, parent string, sels []Selection) {
+	for _, sel := range sels {
+		switch s := sel.(type) {
+		case *Field:
+			v.checkField(owner, parent, s)
+		case *FragmentSpread:
+			v.checkDirectives(owner, s.Pos, s.Directives)
+			v.spreads[owner] = append(v.spreads[owner], s.Name)
+
+			f := v.doc.Fragment(s.Name)
+			if f == nil {
+				v.errorf(s.Pos, "unknown fragment %s", s.Name)
+				continue
+			}
+			if v.s.IsComposite(f.TypeCondition) {
+				v.checkApplies(s.Pos, parent, f.TypeCondition)
+			}
+		case *InlineFragment:
+			v.checkDirectives(owner, s.Pos, s.Directives)
+
+			typ := s.TypeCondition
+			if typ == "" {
+				typ = parent
+			} else if !v.checkTypeCondition(s.Pos, typ) {
+				continue
+			} else {
+				v.checkApplies(s.Pos, parent, typ)
+			}
+			v.checkSelections(owner, typ, s.SelectionSet)
+		}
+	}
+}
+
+func (v *validator) checkField(owner interface{}, parent string, f *Field) {
+	fd := v.s.Field(parent, f.Name)
+	if fd == nil {
+		v.errorf(f.Pos, "field %s is not defined by %s", f.Name, parent)
+		return
+	}
+
+	v.checkArgs(owner, f.Pos, parent+"."+f.Name, fd.Args, f.Args)
+	v.checkDirectives(owner, f.Pos, f.Directives)
+
+	typ := TypeName(graph.FieldType(fd))
+	switch {
+	case v.s.IsLeaf(typ) && len(f.SelectionSet) > 0:
+		v.errorf(f.Pos, "field %s of type %s must not have a selection", f.Name, typeString(graph.FieldType(fd)))
+	case !v.s.IsLeaf(typ) && len(f.SelectionSet) == 0:
+		v.errorf(f.Pos, "field %s of type %s must have a selection", f.Name, typeString(graph.FieldType(fd)))
+	case len(f.SelectionSet) > 0:
+		v.checkSelections(owner, typ, f.SelectionSet)
+	}
+}
+
+func (v *validator) checkDirectives(owner interface{}, pos scanner.Position, dirs []*ast.DirectiveLit) {
+	for _, d := range dirs {
+		var args []*ast.Arg
+		if d.Args != nil {
+			args = d.Args.Args
+		}
+
+		switch d.Name {
+		case "skip", "include":
+			v.checkArgs(owner, pos, "@"+d.Name, conditionArgs, args)
+			continue
+		}
+
+		dir, ok := v.s.Types[d.Name].GetType().(*ast.TypeSpec_Directive)
+		if !ok {
+			v.errorf(pos, "unknown directive @%s", d.Name)
+			continue
+		}
+		v.checkArgs(owner, pos, "@"+d.Name, dir.Directive.Args, args)
+	}
+}
+
+// checkArgs checks the arguments of a field or directive against their definitions.
+func (v *validator) checkArgs(owner interface{}, pos scanner.Position, of string, defs *ast.InputValueList, args []*ast.Arg) {
+	given := make(map[string]bool, len(args))
+	for _, arg := range args {
+		name := arg.Name.Name
+		if given[name] {
+			v.errorf(pos, "argument %s of %s is given more than once", name, of)
+		}
+		given[name] = true
+
+		def := findInputValue(defs, name)
+		if def == nil {
+			v.errorf(pos, "unknown argument %s of %s", name, of)
+			continue
+		}
+
+		switch a := arg.Value.(type) {
+		case *ast.Arg_BasicLit:
+			v.checkValue(owner, pos, graph.InputValueType(def), def.Default != nil, a.BasicLit)
+		case *ast.Arg_CompositeLit:
+			v.checkValue(owner, pos, graph.InputValueType(def), def.Default != nil, a.CompositeLit)
+		}
+	}
+
+	if defs == nil {
+		return
+	}
+	for _, def := range defs.List {
+		if _, nonNull := def.Type.(*ast.InputValue_NonNull); nonNull && def.Default == nil && !given[def.Name.Name] {
+			v.errorf(pos, "argument %s of %s is required", def.Name.Name, of)
+		}
+	}
+}
+
+func findInputValue(ivs *ast.InputValueList, name string) *ast.InputValue {
+	if ivs == nil {
+		return nil
+	}
+	for _, iv := range ivs.List {
+		if iv.Name.Name == name {
+			return iv
+		}
+	}
+	return nil
+}
+
+// checkValue checks that a value, i.e. an *ast.BasicLit or *ast.CompositeLit, is
+// of the given type. Variables are recorded as usages of owner. hasDefault is
+// whether the location of the value has a default value.
+//
+func (v *validator) checkValue(owner interface{}, pos scanner.Position, typ interface{}, hasDefault bool, val interface{}) {
+	if c, ok := val.(*ast.CompositeLit); ok {
+		if b, ok := c.Value.(*ast.CompositeLit_BasicLit); ok {
+			val = b.BasicLit
+		}
+	}
+
+	if lit, ok := val.(*ast.BasicLit); ok {
+		switch lit.Kind {
+		case token.Token_VAR:
+			v.usages[owner] = append(v.usages[owner], usage{pos: pos, name: lit.Value, typ: typ, hasDefault: hasDefault})
+			return
+		case token.Token_NULL:
+			if _, nonNull := typ.(*ast.NonNull); nonNull {
+				v.errorf(pos, "null given for %s", typeString(typ))
+			}
+			return
+		}
+	}
+
+	switch t := typ.(type) {
+	case *ast.NonNull:
+		v.checkValue(owner, pos, nonNullElem(t), false, val)
+	case *ast.List:
+		c, ok := val.(*ast.CompositeLit)
+		if !ok {
+			v.checkValue(owner, pos, listElem(t), false, val)
+			return
+		}
+
+		l, ok := c.Value.(*ast.CompositeLit_ListLit)
+		if !ok {
+			v.checkValue(owner, pos, listElem(t), false, val)
+			return
+		}
+		switch w := l.ListLit.List.(type) {
+		case *ast.ListLit_BasicList:
+			for _, item := range w.BasicList.Values {
+				v.checkValue(owner, pos, listElem(t), false, item)
+			}
+		case *ast.ListLit_CompositeList:
+			for _, item := range w.CompositeList.Values {
+				v.checkValue(owner, pos, listElem(t), false, item)
+			}
+		}
+	case *ast.Ident:
+		switch w := val.(type) {
+		case *ast.BasicLit:
+			v.checkLiteral(pos, t.Name, w)
+		case *ast.CompositeLit:
+			obj, ok := w.Value.(*ast.CompositeLit_ObjLit)
+			if !ok {
+				v.errorf(pos, "list given for %s", t.Name)
+				return
+			}
+			v.checkObject(owner, pos, t.Name, obj.ObjLit)
+		}
+	}
+}
+
+// checkLiteral checks that a basic literal is a value of the named type.
+func (v *validator) checkLiteral(pos scanner.Position, typ string, lit *ast.BasicLit) {
+	ok := false
+	switch typ {
+	case "Int":
+		ok = lit.Kind == token.Token_INT
+	case "Float":
+		ok = lit.Kind == token.Token_INT || lit.Kind == token.Token_FLOAT
+	case "String":
+		ok = lit.Kind == token.Token_STRING
+	case "Boolean":
+		ok = lit.Kind == token.Token_BOOL
+	case "ID":
+		ok = lit.Kind == token.Token_STRING || lit.Kind == token.Token_INT
+	default:
+		switch t := v.s.Types[typ].GetType().(type) {
+		case *ast.TypeSpec_Scalar:
+			ok = true
+		case *ast.TypeSpec_Enum:
+			ok = lit.Kind == token.Token_IDENT && t.Enum.Values != nil && hasEnumValue(t.Enum.Values, lit.Value)
+		}
+	}
+
+	if !ok {
+		v.errorf(pos, "%s is not a valid %s", lit.Value, typ)
+	}
+}
+
+func hasEnumValue(vals *ast.FieldList, name string) bool {
+	for _, ev := range vals.List {
+		if ev.Name.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkObject checks an object literal against the fields of an input.
+func (v *validator) checkObject(owner interface{}, pos scanner.Position, typ string, obj *ast.ObjLit) {
+	input, ok := v.s.Types[typ].GetType().(*ast.TypeSpec_Input)
+	if !ok {
+		v.errorf(pos, "object given for %s", typ)
+		return
+	}
+
+	given := make(map[string]bool, len(obj.Fields))
+	for _, p := range obj.Fields {
+		given[p.Key.Name] = true
+
+		def := findInputValue(input.Input.Fields, p.Key.Name)
+		if def == nil {
+			v.errorf(pos, "field %s is not defined by %s", p.Key.Name, typ)
+			continue
+		}
+		v.checkValue(owner, pos, graph.InputValueType(def), def.Default != nil, p.Val)
+	}
+
+	if input.Input.Fields == nil {
+		return
+	}
+	for _, def := range input.Input.Fields.List {
+		if _, nonNull := def.Type.(*ast.InputValue_NonNull); nonNull && def.Default == nil && !given[def.Name.Name] {
+			v.errorf(pos, "field %s of %s is required", def.Name.Name, typ)
+		}
+	}
+}
+
+// checkFragmentCycles checks that no fragment spreads itself, directly or indirectly.
+func (v *validator) checkFragmentCycles() {
+	done := make(map[string]bool, len(v.doc.Fragments))
+	for _, f := range v.doc.Fragments {
+		onPath := make(map[string]bool)
+
+		var visit func(f *Fragment) bool
+		visit = func(f *Fragment) bool {
+			if onPath[f.Name] {
+				return true
+			}
+			if done[f.Name] {
+				return false
+			}
+
+			onPath[f.Name] = true
+			defer delete(onPath, f.Name)
+			for _, name := range v.spreads[f] {
+				if g := v.doc.Fragment(name); g != nil && visit(g) {
+					return true
+				}
+			}
+			done[f.Name] = true
+			return false
+		}
+
+		if visit(f) {
+			v.errorf(f.Pos, "fragment %s spreads itself", f.Name)
+			done[f.Name] = true
+		}
+	}
+}
+
+// checkVariables checks that the variables of an operation are defined,
+// used and of the types expected where they're used. Any fragments
+// spread by the operation are added to used.
+//
+func (v *validator) checkVariables(op *Operation, used map[string]bool) {
+	usages := v.usages[op]
+	seen := make(map[string]bool)
+
+	var visit func(owner interface{})
+	visit = func(owner interface{}) {
+		for _, name := range v.spreads[owner] {
+			f := v.doc.Fragment(name)
+			if f == nil || seen[name] {
+				continue
+			}
+
+			seen[name] = true
+			used[name] = true
+			usages = append(usages, v.usages[f]...)
+			visit(f)
+		}
+	}
+	visit(op)
+
+	vars := make(map[string]*Variable, len(op.Variables))
+	for _, vr := range op.Variables {
+		vars[vr.Name] = vr
+	}
+
+	usedVars := make(map[string]bool, len(vars))
+	for _, u := range usages {
+		usedVars[u.name] = true
+
+		vr, ok := vars[u.name]
+		if !ok {
+			v.errorf(u.pos, "variable $%s is not defined by %s", u.name, opName(op))
+			continue
+		}
+		if !allowedVariable(vr.Type, vr.Default != nil, u.typ, u.hasDefault) {
+			v.errorf(u.pos, "variable $%s of type %s is used where %s is expected", u.name, typeString(vr.Type), typeString(u.typ))
+		}
+	}
+
+	for _, vr := range op.Variables {
+		if !usedVars[vr.Name] {
+			v.errorf(vr.Pos, "variable $%s is never used in %s", vr.Name, opName(op))
+		}
+	}
+}
+
+// allowedVariable reports whether a variable may be used where a value of
+// typ is expected. A nullable variable may be used in a non-null location,
+// if either has a default value.
+//
+func allowedVariable(varType interface{}, varDefault bool, typ interface{}, locDefault bool) bool {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		if _, ok := varType.(*ast.NonNull); !ok {
+			if !varDefault && !locDefault {
+				return false
+			}
+			return compatibleTypes(varType, nonNullElem(nn))
+		}
+	}
+	return compatibleTypes(varType, typ)
+}
+
+func compatibleTypes(varType, typ interface{}) bool {
+	if nn, ok := typ.(*ast.NonNull); ok {
+		vnn, ok := varType.(*ast.NonNull)
+		return ok && compatibleTypes(nonNullElem(vnn), nonNullElem(nn))
+	}
+	if vnn, ok := varType.(*ast.NonNull); ok {
+		return compatibleTypes(nonNullElem(vnn), typ)
+	}
+	if l, ok := typ.(*ast.List); ok {
+		vl, ok := varType.(*ast.List)
+		return ok && compatibleTypes(listElem(vl), listElem(l))
+	}
+	if _, ok := varType.(*ast.List); ok {
+		return false
+	}
+	return TypeName(varType) == TypeName(typ)
+}
@@ -0,0 +1,244 @@
+package operation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/graph"
+	"github.com/gqlc/graphql/ast"
+	gqlparser "github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+var testSchema = `schema {
+	query: Query
+	mutation: Mutation
+}
+
+directive @cached on QUERY
+
+type Query {
+	user(id: ID!): User
+	search(text: String!, kind: Kind = USER): [Result!]!
+	node(id: ID!): Node
+}
+
+type Mutation {
+	createUser(input: UserInput!): User!
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	name: String
+	friends(first: Int, after: String): [User!]
+}
+
+type Post implements Node {
+	id: ID!
+	title: String!
+}
+
+union Result = User | Post
+
+enum Kind {
+	USER
+	POST
+}
+
+input UserInput {
+	name: String!
+	tags: [String!]
+	kind: Kind
+}`
+
+func newTestSchema(t *testing.T) *Schema {
+	doc, err := gqlparser.ParseDoc(token.NewDocSet(), "schema", strings.NewReader(testSchema), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSchema([]*ast.Document{doc})
+}
+
+func TestNewSchema(t *testing.T) {
+	s := newTestSchema(t)
+
+	if s.Roots[Query] != "Query" || s.Roots[Mutation] != "Mutation" || s.Roots[Subscription] != "" {
+		t.Errorf("unexpected roots: %v", s.Roots)
+	}
+	if types := s.PossibleTypes("Node"); len(types) != 2 || types[0] != "User" || types[1] != "Post" {
+		t.Errorf("unexpected possible types of Node: %v", types)
+	}
+	if types := s.PossibleTypes("Result"); len(types) != 2 {
+		t.Errorf("unexpected possible types of Result: %v", types)
+	}
+	if f := s.Field("Result", "__typename"); f == nil {
+		t.Error("expected __typename on Result")
+	}
+	if f := s.Field("User", "friends"); f == nil || typeString(graph.FieldType(f)) != "[User!]" {
+		t.Errorf("unexpected field: %v", f)
+	}
+	if !s.IsLeaf("Kind") || !s.IsLeaf("ID") || s.IsLeaf("UserInput") || !s.IsComposite("Result") {
+		t.Error("unexpected kinds")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := newTestSchema(t)
+
+	testCases := []struct {
+		Name string
+		Src  string
+		Errs []string
+	}{
+		{
+			Name: "Valid",
+			Src: `query GetUser($id: ID!, $first: Int = 10) @cached {
+	user(id: $id) {
+		...UserFields
+		friends(first: $first) { __typename name }
+	}
+	search(text: "gqlc", kind: POST) {
+		... on Node { id }
+		... on Post { title }
+	}
+}
+
+mutation Create($name: String!) {
+	createUser(input: {name: $name, tags: "go", kind: USER}) { id }
+}
+
+fragment UserFields on User {
+	id
+	name
+}`,
+		},
+		{
+			Name: "Names",
+			Src: `query Q { node(id: 1) { id } }
+query Q { node(id: 1) { id } }
+{ node(id: 1) { id } }`,
+			Errs: []string{
+				"2:1: operation Q is declared more than once",
+				"3:1: anonymous operation must be the only operation of the document",
+			},
+		},
+		{
+			Name: "Fields",
+			Src: `{
+	user(id: 1) { id email }
+	node(id: 1)
+	search(text: "a") { id }
+}`,
+			Errs: []string{
+				"2:19: field email is not defined by User",
+				"3:2: field node of type Node must have a selection",
+				"4:22: field id is not defined by Result",
+			},
+		},
+		{
+			Name: "Leaf",
+			Src:  `{ user(id: 1) { name { id } } }`,
+			Errs: []string{"field name of type String must not have a selection"},
+		},
+		{
+			Name: "Arguments",
+			Src:  `{ user(name: "a") { id friends(first: "1", first: 2) { id } } search(text: "a", kind: GROUP) { __typename } }`,
+			Errs: []string{
+				"unknown argument name of Query.user",
+				"argument id of Query.user is required",
+				`"1" is not a valid Int`,
+				"argument first of User.friends is given more than once",
+				"GROUP is not a valid Kind",
+			},
+		},
+		{
+			Name: "Inputs",
+			Src:  `mutation { createUser(input: {tags: [null], age: 1}) { id } }`,
+			Errs: []string{
+				"null given for String!",
+				"field age is not defined by UserInput",
+				"field name of UserInput is required",
+			},
+		},
+		{
+			Name: "Variables",
+			Src: `query Q($id: ID, $kind: Kind, $text: String = "a", $user: User) {
+	user(id: $id) { id }
+	search(text: $text, kind: $kind) { __typename }
+	node(id: $missing) { id }
+}`,
+			Errs: []string{
+				"1:52: variable $user must have an input type, not User",
+				"variable $id of type ID is used where ID! is expected",
+				"variable $missing is not defined by query Q",
+				"1:52: variable $user is never used in query Q",
+			},
+		},
+		{
+			Name: "UnusedVariable",
+			Src:  `query Q($id: ID!) { node(id: 1) { id } }`,
+			Errs: []string{"1:9: variable $id is never used in query Q"},
+		},
+		{
+			Name: "FragmentVariables",
+			Src: `query Q($first: Int) { user(id: 1) { ...F } }
+
+fragment F on User { friends(first: $first, after: $after) { id } }`,
+			Errs: []string{"variable $after is not defined by query Q"},
+		},
+		{
+			Name: "Fragments",
+			Src: `{
+	user(id: 1) { ...Missing ...OnPost ... on Kind { id } }
+}
+
+fragment OnPost on Post { id }
+fragment Unused on User { id }
+fragment Cycle on User { ...Cycle }`,
+			Errs: []string{
+				"2:16: unknown fragment Missing",
+				"2:27: fragment on Post can never apply to User",
+				"2:37: fragment can not be on Kind, which is not an object, interface or union",
+				"7:1: fragment Cycle spreads itself",
+				"6:1: fragment Unused is never used",
+				"7:1: fragment Cycle is never used",
+			},
+		},
+		{
+			Name: "Directives",
+			Src:  `{ user(id: 1) @include @unknown { id } }`,
+			Errs: []string{
+				"argument if of @include is required",
+				"unknown directive @unknown",
+			},
+		},
+		{
+			Name: "Root",
+			Src:  `subscription { user(id: 1) { id } }`,
+			Errs: []string{"schema does not support subscription operations"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			doc, err := Parse("ops.graphql", []byte(testCase.Src))
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			errs := Validate(doc, s)
+			if len(errs) != len(testCase.Errs) {
+				subT.Fatalf("expected %d errors but got: %v", len(testCase.Errs), errs)
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), testCase.Errs[i]) {
+					subT.Errorf("expected %q in %q", testCase.Errs[i], err)
+				}
+			}
+		})
+	}
+}
@@ -21,6 +21,121 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// Features a plugin may declare support for.
+type Response_Feature int32
+
+const (
+	Response_FEATURE_NONE Response_Feature = 0
+)
+
+var Response_Feature_name = map[int32]string{
+	0: "FEATURE_NONE",
+}
+
+var Response_Feature_value = map[string]int32{
+	"FEATURE_NONE": 0,
+}
+
+func (x Response_Feature) String() string {
+	return proto.EnumName(Response_Feature_name, int32(x))
+}
+
+func (Response_Feature) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{2, 0}
+}
+
+type Response_Diagnostic_Severity int32
+
+const (
+	Response_Diagnostic_ERROR   Response_Diagnostic_Severity = 0
+	Response_Diagnostic_WARNING Response_Diagnostic_Severity = 1
+)
+
+var Response_Diagnostic_Severity_name = map[int32]string{
+	0: "ERROR",
+	1: "WARNING",
+}
+
+var Response_Diagnostic_Severity_value = map[string]int32{
+	"ERROR":   0,
+	"WARNING": 1,
+}
+
+func (x Response_Diagnostic_Severity) String() string {
+	return proto.EnumName(Response_Diagnostic_Severity_name, int32(x))
+}
+
+func (Response_Diagnostic_Severity) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{2, 0, 0}
+}
+
+// The version number of gqlc.
+type Version struct {
+	Major int32 `protobuf:"varint,1,opt,name=major,proto3" json:"major,omitempty"`
+	Minor int32 `protobuf:"varint,2,opt,name=minor,proto3" json:"minor,omitempty"`
+	Patch int32 `protobuf:"varint,3,opt,name=patch,proto3" json:"patch,omitempty"`
+	// A suffix for alpha, beta or rc release, e.g., "alpha-1", "rc2". It should
+	// be empty for mainline stable releases. Local builds, which have no
+	// semantic version, report their version string here.
+	Suffix               string   `protobuf:"bytes,4,opt,name=suffix,proto3" json:"suffix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Version) Reset()         { *m = Version{} }
+func (m *Version) String() string { return proto.CompactTextString(m) }
+func (*Version) ProtoMessage()    {}
+func (*Version) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{0}
+}
+
+func (m *Version) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Version.Unmarshal(m, b)
+}
+func (m *Version) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Version.Marshal(b, m, deterministic)
+}
+func (m *Version) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Version.Merge(m, src)
+}
+func (m *Version) XXX_Size() int {
+	return xxx_messageInfo_Version.Size(m)
+}
+func (m *Version) XXX_DiscardUnknown() {
+	xxx_messageInfo_Version.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Version proto.InternalMessageInfo
+
+func (m *Version) GetMajor() int32 {
+	if m != nil {
+		return m.Major
+	}
+	return 0
+}
+
+func (m *Version) GetMinor() int32 {
+	if m != nil {
+		return m.Minor
+	}
+	return 0
+}
+
+func (m *Version) GetPatch() int32 {
+	if m != nil {
+		return m.Patch
+	}
+	return 0
+}
+
+func (m *Version) GetSuffix() string {
+	if m != nil {
+		return m.Suffix
+	}
+	return ""
+}
+
 // An encoded PluginRequest is written to the plugin's stdin.
 type Request struct {
 	// The .gql/.graphql files to generate.
@@ -28,17 +143,24 @@ type Request struct {
 	// The generator parameter passed on the command-line encoded as JSON.
 	Parameter string `protobuf:"bytes,2,opt,name=parameter,proto3" json:"parameter,omitempty"`
 	// Documents are all the parsed documents to be generated.
-	Documents            []*ast.Document `protobuf:"bytes,3,rep,name=documents,proto3" json:"documents,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Documents []*ast.Document `protobuf:"bytes,3,rep,name=documents,proto3" json:"documents,omitempty"`
+	// The version number of gqlc.
+	CompilerVersion *Version `protobuf:"bytes,4,opt,name=compiler_version,json=compilerVersion,proto3" json:"compiler_version,omitempty"`
+	// The directory generated files will be written to.
+	OutputDir string `protobuf:"bytes,5,opt,name=output_dir,json=outputDir,proto3" json:"output_dir,omitempty"`
+	// The names of the directives registered by gqlc itself, e.g. "resolver".
+	// These are compiler-level directives and not part of any document.
+	GqlcDirectives       []string `protobuf:"bytes,6,rep,name=gqlc_directives,json=gqlcDirectives,proto3" json:"gqlc_directives,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Request) Reset()         { *m = Request{} }
 func (m *Request) String() string { return proto.CompactTextString(m) }
 func (*Request) ProtoMessage()    {}
 func (*Request) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22a625af4bc1cc87, []int{0}
+	return fileDescriptor_22a625af4bc1cc87, []int{1}
 }
 
 func (m *Request) XXX_Unmarshal(b []byte) error {
@@ -80,24 +202,53 @@ func (m *Request) GetDocuments() []*ast.Document {
 	return nil
 }
 
+func (m *Request) GetCompilerVersion() *Version {
+	if m != nil {
+		return m.CompilerVersion
+	}
+	return nil
+}
+
+func (m *Request) GetOutputDir() string {
+	if m != nil {
+		return m.OutputDir
+	}
+	return ""
+}
+
+func (m *Request) GetGqlcDirectives() []string {
+	if m != nil {
+		return m.GqlcDirectives
+	}
+	return nil
+}
+
 // The plugin writes an encoded PluginResponse to stdout.
 type Response struct {
 	// Error message. If non-empty code generation failed. The plugin
 	// process should exit with status code zero even if it reports
 	// an error in this way.
 	//
-	Error                string           `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	File                 []*Response_File `protobuf:"bytes,2,rep,name=file,proto3" json:"file,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// A bitmask of features the plugin supports. This is a bitwise "or"
+	// of values from the Feature enum.
+	// gqlc warns about features it doesn't know, e.g. when it's older than the plugin.
+	SupportedFeatures uint64 `protobuf:"varint,3,opt,name=supported_features,json=supportedFeatures,proto3" json:"supported_features,omitempty"`
+	// Diagnostics reported by the plugin. Any ERROR diagnostic fails code
+	// generation, in which case no files are written.
+	//
+	Diagnostic           []*Response_Diagnostic `protobuf:"bytes,4,rep,name=diagnostic,proto3" json:"diagnostic,omitempty"`
+	File                 []*Response_File       `protobuf:"bytes,2,rep,name=file,proto3" json:"file,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
 }
 
 func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22a625af4bc1cc87, []int{1}
+	return fileDescriptor_22a625af4bc1cc87, []int{2}
 }
 
 func (m *Response) XXX_Unmarshal(b []byte) error {
@@ -125,6 +276,20 @@ func (m *Response) GetError() string {
 	return ""
 }
 
+func (m *Response) GetSupportedFeatures() uint64 {
+	if m != nil {
+		return m.SupportedFeatures
+	}
+	return 0
+}
+
+func (m *Response) GetDiagnostic() []*Response_Diagnostic {
+	if m != nil {
+		return m.Diagnostic
+	}
+	return nil
+}
+
 func (m *Response) GetFile() []*Response_File {
 	if m != nil {
 		return m.File
@@ -132,6 +297,75 @@ func (m *Response) GetFile() []*Response_File {
 	return nil
 }
 
+// Represents a warning or error found by the plugin.
+type Response_Diagnostic struct {
+	Severity Response_Diagnostic_Severity `protobuf:"varint,1,opt,name=severity,proto3,enum=Response_Diagnostic_Severity" json:"severity,omitempty"`
+	// The diagnostic message.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// The name of the document the diagnostic refers to.
+	Document string `protobuf:"bytes,3,opt,name=document,proto3" json:"document,omitempty"`
+	// The schema position the diagnostic refers to. This must be a position
+	// taken from the request documents, e.g. the namePos of an Ident.
+	// Zero means there is no position.
+	Pos                  int64    `protobuf:"varint,4,opt,name=pos,proto3" json:"pos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Response_Diagnostic) Reset()         { *m = Response_Diagnostic{} }
+func (m *Response_Diagnostic) String() string { return proto.CompactTextString(m) }
+func (*Response_Diagnostic) ProtoMessage()    {}
+func (*Response_Diagnostic) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{2, 0}
+}
+
+func (m *Response_Diagnostic) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Response_Diagnostic.Unmarshal(m, b)
+}
+func (m *Response_Diagnostic) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Response_Diagnostic.Marshal(b, m, deterministic)
+}
+func (m *Response_Diagnostic) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Response_Diagnostic.Merge(m, src)
+}
+func (m *Response_Diagnostic) XXX_Size() int {
+	return xxx_messageInfo_Response_Diagnostic.Size(m)
+}
+func (m *Response_Diagnostic) XXX_DiscardUnknown() {
+	xxx_messageInfo_Response_Diagnostic.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Response_Diagnostic proto.InternalMessageInfo
+
+func (m *Response_Diagnostic) GetSeverity() Response_Diagnostic_Severity {
+	if m != nil {
+		return m.Severity
+	}
+	return Response_Diagnostic_ERROR
+}
+
+func (m *Response_Diagnostic) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Response_Diagnostic) GetDocument() string {
+	if m != nil {
+		return m.Document
+	}
+	return ""
+}
+
+func (m *Response_Diagnostic) GetPos() int64 {
+	if m != nil {
+		return m.Pos
+	}
+	return 0
+}
+
 // Represents a single generated file.
 type Response_File struct {
 	// The file name, relative to the output directory. The name must not
@@ -140,6 +374,31 @@ type Response_File struct {
 	// the path separator, not "\".
 	//
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// If non-empty, indicates that the named file should already exist,
+	// having been generated earlier in the same gqlc run, and the content
+	// should be inserted into that file at the given insertion point.
+	//
+	// An insertion point is a line in the file containing the text:
+	//   @@gqlc_insertion_point(NAME)
+	// The content is inserted immediately above that line, with every
+	// inserted line indented by the whitespace preceding the insertion
+	// point. The insertion point itself is kept, so it may be used again.
+	//
+	InsertionPoint string `protobuf:"bytes,2,opt,name=insertion_point,json=insertionPoint,proto3" json:"insertion_point,omitempty"`
+	// The permission bits of the file, e.g. 0755 for an executable script.
+	// Zero means gqlc's default permissions.
+	//
+	Mode uint32 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	// If true, the named file is deleted from the output directory instead
+	// of being written. This lets plugins clean up files they used to
+	// generate. It is not an error if the file does not exist.
+	//
+	Delete bool `protobuf:"varint,4,opt,name=delete,proto3" json:"delete,omitempty"`
+	// The file contents, for files which are not valid UTF-8. If non-empty,
+	// it is written instead of content. It can not be used with an
+	// insertion point.
+	//
+	BinaryContent []byte `protobuf:"bytes,14,opt,name=binary_content,json=binaryContent,proto3" json:"binary_content,omitempty"`
 	// The file contents.
 	Content              string   `protobuf:"bytes,15,opt,name=content,proto3" json:"content,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -151,7 +410,7 @@ func (m *Response_File) Reset()         { *m = Response_File{} }
 func (m *Response_File) String() string { return proto.CompactTextString(m) }
 func (*Response_File) ProtoMessage()    {}
 func (*Response_File) Descriptor() ([]byte, []int) {
-	return fileDescriptor_22a625af4bc1cc87, []int{1, 0}
+	return fileDescriptor_22a625af4bc1cc87, []int{2, 1}
 }
 
 func (m *Response_File) XXX_Unmarshal(b []byte) error {
@@ -179,6 +438,34 @@ func (m *Response_File) GetName() string {
 	return ""
 }
 
+func (m *Response_File) GetInsertionPoint() string {
+	if m != nil {
+		return m.InsertionPoint
+	}
+	return ""
+}
+
+func (m *Response_File) GetMode() uint32 {
+	if m != nil {
+		return m.Mode
+	}
+	return 0
+}
+
+func (m *Response_File) GetDelete() bool {
+	if m != nil {
+		return m.Delete
+	}
+	return false
+}
+
+func (m *Response_File) GetBinaryContent() []byte {
+	if m != nil {
+		return m.BinaryContent
+	}
+	return nil
+}
+
 func (m *Response_File) GetContent() string {
 	if m != nil {
 		return m.Content
@@ -187,29 +474,54 @@ func (m *Response_File) GetContent() string {
 }
 
 func init() {
+	proto.RegisterEnum("Response_Feature", Response_Feature_name, Response_Feature_value)
+	proto.RegisterEnum("Response_Diagnostic_Severity", Response_Diagnostic_Severity_name, Response_Diagnostic_Severity_value)
+	proto.RegisterType((*Version)(nil), "Version")
 	proto.RegisterType((*Request)(nil), "Request")
 	proto.RegisterType((*Response)(nil), "Response")
+	proto.RegisterType((*Response_Diagnostic)(nil), "Response.Diagnostic")
 	proto.RegisterType((*Response_File)(nil), "Response.File")
 }
 
 func init() { proto.RegisterFile("plugin.proto", fileDescriptor_22a625af4bc1cc87) }
 
 var fileDescriptor_22a625af4bc1cc87 = []byte{
-	// 256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x50, 0xc1, 0x6a, 0xeb, 0x30,
-	0x10, 0xc4, 0xb1, 0x5f, 0x62, 0xed, 0x2b, 0x69, 0x11, 0x85, 0x08, 0x53, 0xa8, 0xf1, 0xc9, 0xbd,
-	0x28, 0x90, 0xb6, 0x7f, 0x50, 0xda, 0x6b, 0x11, 0xbd, 0x07, 0xc5, 0xdd, 0x18, 0x83, 0x2d, 0x39,
-	0xd2, 0xfa, 0xd2, 0x2f, 0xe8, 0x67, 0x17, 0x2b, 0x76, 0x72, 0xdb, 0x99, 0x59, 0x66, 0x67, 0x07,
-	0x6e, 0xfa, 0x76, 0xa8, 0x1b, 0x23, 0x7b, 0x67, 0xc9, 0x66, 0x9b, 0xfa, 0xd4, 0x56, 0xdb, 0x30,
-	0x1f, 0x86, 0xe3, 0x56, 0x7b, 0x3a, 0x0b, 0xc5, 0x6f, 0x04, 0x2b, 0x85, 0xa7, 0x01, 0x3d, 0xf1,
-	0x12, 0xee, 0x8e, 0x4d, 0x8b, 0x7b, 0xb2, 0xfb, 0x1a, 0x0d, 0x3a, 0x4d, 0x28, 0xa2, 0x3c, 0x2e,
-	0x99, 0x5a, 0x8f, 0xfc, 0x97, 0xfd, 0x98, 0x58, 0xfe, 0x00, 0xac, 0xd7, 0x4e, 0x77, 0x48, 0xe8,
-	0xc4, 0x22, 0x8f, 0x4a, 0xa6, 0xae, 0x04, 0x7f, 0x05, 0xf6, 0x6d, 0xab, 0xa1, 0x43, 0x43, 0x5e,
-	0xc4, 0x79, 0x5c, 0xfe, 0xdf, 0x6d, 0xe4, 0x18, 0x40, 0xce, 0x01, 0xe4, 0xdb, 0xa4, 0xab, 0xeb,
-	0x66, 0xf1, 0x03, 0xa9, 0x42, 0xdf, 0x5b, 0xe3, 0x91, 0xdf, 0xc3, 0x3f, 0x74, 0xce, 0x3a, 0x11,
-	0x05, 0xf3, 0x33, 0xe0, 0x05, 0x24, 0x63, 0x10, 0xb1, 0x08, 0x9e, 0x6b, 0x39, 0xaf, 0xcb, 0xf7,
-	0xa6, 0x45, 0x15, 0xb4, 0xec, 0x05, 0x92, 0x11, 0x71, 0x0e, 0x89, 0xd1, 0x1d, 0x4e, 0x06, 0x61,
-	0xe6, 0x02, 0x56, 0x95, 0x35, 0x84, 0x86, 0xc4, 0x6d, 0xa0, 0x67, 0xb8, 0x7b, 0x82, 0xe5, 0x67,
-	0xe8, 0x8b, 0x3f, 0x42, 0x7a, 0x79, 0x33, 0x95, 0x53, 0x35, 0x19, 0xbb, 0xdc, 0x3a, 0x2c, 0xc3,
-	0x13, 0xcf, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x44, 0x71, 0x9d, 0x1c, 0x61, 0x01, 0x00, 0x00,
+	// 591 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0xc1, 0x6e, 0xdb, 0x3a,
+	0x10, 0x8c, 0x62, 0xd9, 0x96, 0x36, 0x89, 0xa3, 0x47, 0x04, 0x2f, 0x82, 0xdf, 0x0b, 0x6a, 0x08,
+	0x28, 0xea, 0x1e, 0xaa, 0x00, 0x4e, 0x7b, 0xe8, 0x31, 0xa8, 0x9d, 0xa0, 0x17, 0x27, 0x60, 0xd3,
+	0xf6, 0x28, 0x28, 0xf2, 0xda, 0x65, 0x61, 0x91, 0x0a, 0x49, 0x05, 0xcd, 0x07, 0xf4, 0x7b, 0x5a,
+	0xa0, 0x3f, 0x58, 0x90, 0xa2, 0xe4, 0x1e, 0x7a, 0xe3, 0xcc, 0xac, 0xb4, 0x3b, 0xb3, 0x24, 0x1c,
+	0x56, 0xdb, 0x7a, 0xc3, 0x78, 0x5a, 0x49, 0xa1, 0xc5, 0xf8, 0x74, 0xf3, 0xb0, 0x2d, 0xce, 0xed,
+	0xf9, 0xbe, 0x5e, 0x9f, 0xe7, 0x4a, 0x37, 0x42, 0x52, 0xc0, 0xf0, 0x13, 0x4a, 0xc5, 0x04, 0x27,
+	0x27, 0xd0, 0x2f, 0xf3, 0xaf, 0x42, 0xc6, 0xde, 0xc4, 0x9b, 0xf6, 0x69, 0x03, 0x2c, 0xcb, 0xb8,
+	0x90, 0xf1, 0xbe, 0x63, 0x19, 0x6f, 0xd8, 0x2a, 0xd7, 0xc5, 0x97, 0xb8, 0xd7, 0xb0, 0x16, 0x90,
+	0x7f, 0x61, 0xa0, 0xea, 0xf5, 0x9a, 0x7d, 0x8b, 0xfd, 0x89, 0x37, 0x0d, 0xa9, 0x43, 0xc9, 0xf7,
+	0x7d, 0x18, 0x52, 0x7c, 0xa8, 0x51, 0x69, 0x32, 0x85, 0x68, 0xcd, 0xb6, 0x98, 0x69, 0x91, 0x6d,
+	0x90, 0xa3, 0xcc, 0x35, 0xc6, 0xde, 0xa4, 0x37, 0x0d, 0xe9, 0xc8, 0xf0, 0x77, 0xe2, 0xda, 0xb1,
+	0xe4, 0x7f, 0x08, 0xab, 0x5c, 0xe6, 0x25, 0x6a, 0x6c, 0xba, 0x87, 0x74, 0x47, 0x90, 0x37, 0x10,
+	0xae, 0x44, 0x51, 0x97, 0xc8, 0xb5, 0x8a, 0x7b, 0x93, 0xde, 0xf4, 0x60, 0x76, 0x9a, 0x1a, 0x97,
+	0x69, 0xeb, 0x32, 0x9d, 0x3b, 0x9d, 0xee, 0x2a, 0xc9, 0x05, 0x44, 0x85, 0x28, 0x2b, 0xb6, 0x45,
+	0x99, 0x3d, 0x36, 0xc6, 0xed, 0xb0, 0x07, 0xb3, 0x20, 0x75, 0x41, 0xd0, 0xe3, 0xb6, 0xa2, 0x4d,
+	0xe6, 0x0c, 0x40, 0xd4, 0xba, 0xaa, 0x75, 0xb6, 0x62, 0x32, 0xee, 0x37, 0xa3, 0x34, 0xcc, 0x9c,
+	0x49, 0xf2, 0x02, 0x8e, 0x4d, 0x63, 0x23, 0x62, 0xa1, 0xd9, 0x23, 0xaa, 0x78, 0xd0, 0x38, 0x32,
+	0xf4, 0xbc, 0x63, 0x93, 0x1f, 0x3e, 0x04, 0x14, 0x55, 0x25, 0xb8, 0x42, 0x13, 0x21, 0x4a, 0xe9,
+	0xe2, 0x0e, 0x69, 0x03, 0xc8, 0x2b, 0x20, 0xaa, 0xae, 0x2a, 0x21, 0x35, 0xae, 0xb2, 0x35, 0xe6,
+	0xba, 0x96, 0xa8, 0x6c, 0xca, 0x3e, 0xfd, 0xa7, 0x53, 0xae, 0x9c, 0x40, 0x5e, 0x03, 0xac, 0x58,
+	0xbe, 0xe1, 0x42, 0x69, 0x56, 0xc4, 0xbe, 0x8d, 0xe1, 0x24, 0x6d, 0x7b, 0xa4, 0xf3, 0x4e, 0xa3,
+	0x7f, 0xd4, 0x91, 0x04, 0x7c, 0x93, 0x75, 0xbc, 0x6f, 0xeb, 0x47, 0xbb, 0xfa, 0x2b, 0xb6, 0x45,
+	0x6a, 0xb5, 0xf1, 0x2f, 0x0f, 0x60, 0xf7, 0x39, 0x79, 0x0b, 0x81, 0xc2, 0x47, 0x94, 0x4c, 0x3f,
+	0xd9, 0x81, 0x47, 0xb3, 0xb3, 0xbf, 0xb5, 0x49, 0x3f, 0xb8, 0x22, 0xda, 0x95, 0x93, 0x18, 0x86,
+	0x25, 0x2a, 0x95, 0x6f, 0xd0, 0x6d, 0xb1, 0x85, 0x64, 0x0c, 0x41, 0xbb, 0x19, 0x6b, 0x31, 0xa4,
+	0x1d, 0x26, 0x11, 0xf4, 0x2a, 0xa1, 0xec, 0x6e, 0x7a, 0xd4, 0x1c, 0x93, 0x04, 0x82, 0xf6, 0xef,
+	0x24, 0x84, 0xfe, 0x82, 0xd2, 0x1b, 0x1a, 0xed, 0x91, 0x03, 0x18, 0x7e, 0xbe, 0xa4, 0xcb, 0xf7,
+	0xcb, 0xeb, 0xc8, 0x1b, 0xff, 0xf4, 0xc0, 0x37, 0x26, 0x08, 0x01, 0x9f, 0xe7, 0x25, 0xba, 0x70,
+	0xed, 0xd9, 0xec, 0x89, 0x71, 0x85, 0x52, 0x33, 0xc1, 0xb3, 0x4a, 0x30, 0xae, 0xdd, 0x40, 0xa3,
+	0x8e, 0xbe, 0x35, 0xac, 0xf9, 0xb8, 0x14, 0x2b, 0xb4, 0x33, 0x1d, 0x51, 0x7b, 0x36, 0x77, 0x7b,
+	0x85, 0x5b, 0xd4, 0x68, 0x47, 0x0a, 0xa8, 0x43, 0xe4, 0x39, 0x8c, 0xee, 0x19, 0xcf, 0xe5, 0x53,
+	0x56, 0x08, 0xae, 0x8d, 0x93, 0xd1, 0xc4, 0x9b, 0x1e, 0xd2, 0xa3, 0x86, 0x7d, 0xd7, 0x90, 0x26,
+	0x84, 0x56, 0x3f, 0x6e, 0x42, 0x70, 0x30, 0xf9, 0x0f, 0x86, 0x6e, 0x9d, 0x24, 0x82, 0xc3, 0xab,
+	0xc5, 0xe5, 0xdd, 0x47, 0xba, 0xc8, 0x96, 0x37, 0xcb, 0x45, 0xb4, 0x37, 0x7b, 0x09, 0x83, 0x5b,
+	0xfb, 0x8e, 0xc9, 0x33, 0x08, 0xba, 0x97, 0x11, 0xa4, 0xee, 0x35, 0x8d, 0xc3, 0x6e, 0x09, 0xf7,
+	0x03, 0x7b, 0xef, 0x2f, 0x7e, 0x0f, 0x00, 0x4d, 0x8f, 0x30, 0x24, 0xf9, 0x03, 0x00, 0x00,
 }
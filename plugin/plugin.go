@@ -6,16 +6,28 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/gqlc/gqlc/gen"
 	"github.com/gqlc/gqlc/plugin/pb"
+	"github.com/gqlc/gqlc/types"
 	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/token"
 	"go.uber.org/zap"
 )
 
+// knownFeatures is the bitmask of the pb.Response_Feature values gqlc knows.
+const knownFeatures = uint64(pb.Response_FEATURE_NONE)
+
 // Generator executes an external plugin as a generator.
 // The name of the plugin is given by the generators Prefix and Name fields.
 //
@@ -25,6 +37,16 @@ type Generator struct {
 	Name   string
 	Prefix string
 
+	// Version is the gqlc version reported to the plugin. If
+	// empty, the gqlc command line sets it to its own version.
+	//
+	Version string
+
+	// Timeout is the maximum amount of time the plugin may run
+	// for each document. Zero means no timeout.
+	//
+	Timeout time.Duration
+
 	lookOnce    sync.Once
 	path        string
 	lookPathErr error
@@ -67,9 +89,12 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	// Marshall doc
 	g.log.Info("marshalling request")
 	b, perr := proto.Marshal(&pb.Request{
-		FileToGenerate: []string{doc.Name},
-		Parameter:      string(b),
-		Documents:      []*ast.Document{doc},
+		FileToGenerate:  []string{doc.Name},
+		Parameter:       string(b),
+		Documents:       []*ast.Document{doc},
+		CompilerVersion: parseVersion(g.Version),
+		OutputDir:       outputDir(ctx),
+		GqlcDirectives:  types.Directives(),
 	})
 	if perr != nil {
 		err = perr
@@ -78,17 +103,23 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 
 	// Configure plugin command
 	if g.Cmd == nil {
-		g.Cmd = exec.CommandContext(ctx, g.path)
+		g.Cmd = exec.Command(g.path)
 	}
 	out := new(bytes.Buffer)
+	stderr := &logWriter{log: g.log.Named("stderr")}
 	g.Stdin = bytes.NewReader(b)
 	g.Stdout = out
+	g.Stderr = stderr
 
 	// Exec plugin
-	g.log.Info("executing plugin")
-	err = g.Run()
+	g.log.Info("executing plugin", zap.Duration("timeout", g.Timeout))
+	err = g.run(ctx)
+	stderr.Flush()
 	g.Cmd = nil
 	if err != nil {
+		if msg := strings.TrimSpace(stderr.out.String()); msg != "" {
+			err = fmt.Errorf("%w\n%s", err, msg)
+		}
 		return
 	}
 
@@ -99,27 +130,264 @@ func (g *Generator) Generate(ctx context.Context, doc *ast.Document, opts map[st
 	if err != nil {
 		return
 	}
+	g.log.Info("plugin supports features", zap.Uint64("features", resp.SupportedFeatures))
+	if unknown := resp.SupportedFeatures &^ knownFeatures; unknown != 0 {
+		log.Printf("%s: warning: plugin supports features unknown to gqlc %s: %#x", g.Prefix+g.Name, g.Version, unknown)
+	}
 
-	// Check response
+	// Report diagnostics
+	var errs []string
 	if resp.Error != "" {
-		return errors.New(resp.Error)
+		errs = append(errs, resp.Error)
+	}
+
+	dset := gen.DocSet(ctx)
+	for _, d := range resp.Diagnostic {
+		msg := formatDiagnostic(dset, doc.Name, d)
+		if d.Severity == pb.Response_Diagnostic_WARNING {
+			log.Println(msg)
+			continue
+		}
+
+		errs = append(errs, msg)
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
 	}
 
 	// Write plugin files
 	gCtx := gen.Context(ctx)
 	for _, f := range resp.File {
-		g.log.Info("writing content from plugin", zap.String("file", f.Name))
+		g.log.Info("writing content from plugin", zap.String("file", f.Name), zap.String("insertion_point", f.InsertionPoint), zap.Bool("delete", f.Delete))
 
-		w, ferr := gCtx.Open(f.Name)
-		if ferr != nil {
-			err = ferr
+		err = writeFile(gCtx, f)
+		if err != nil {
 			return
 		}
+	}
+	return
+}
+
+// fileContext is implemented by GeneratorContexts which support
+// every file instruction a plugin may respond with.
+//
+type fileContext interface {
+	// OpenFile opens a file with the given permission bits.
+	OpenFile(filename string, perm os.FileMode) (io.WriteCloser, error)
+
+	// ReadFile reads a file generated earlier in the same run.
+	ReadFile(filename string) ([]byte, error)
+
+	// Remove removes a file.
+	Remove(filename string) error
+}
 
-		_, err = w.Write([]byte(f.Content))
+// writeFile applies a single file from a plugin response to the generator context.
+func writeFile(gCtx gen.GeneratorContext, f *pb.Response_File) (err error) {
+	fCtx, ok := gCtx.(fileContext)
+	if !ok && (f.Delete || f.InsertionPoint != "" || f.Mode != 0) {
+		return fmt.Errorf("generator context does not support deleting, inserting into or setting the mode of files: %s", f.Name)
+	}
+
+	if f.Delete {
+		return fCtx.Remove(f.Name)
+	}
+
+	content := []byte(f.Content)
+	if len(f.BinaryContent) > 0 {
+		if f.InsertionPoint != "" {
+			return fmt.Errorf("binary content can not be inserted into: %s", f.Name)
+		}
+		content = f.BinaryContent
+	}
+
+	if f.InsertionPoint != "" {
+		src, rerr := fCtx.ReadFile(f.Name)
+		if rerr != nil {
+			return rerr
+		}
+
+		content, err = insert(src, f.InsertionPoint, f.Content)
 		if err != nil {
-			return
+			return fmt.Errorf("%s: %w", f.Name, err)
 		}
 	}
+
+	var w io.WriteCloser
+	if f.Mode != 0 {
+		w, err = fCtx.OpenFile(f.Name, os.FileMode(f.Mode)&os.ModePerm)
+	} else {
+		w, err = gCtx.Open(f.Name)
+	}
+	if err != nil {
+		return
+	}
+
+	_, err = w.Write(content)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
 	return
 }
+
+// insert inserts content into src immediately above the line containing
+// the named insertion point. Each inserted line is indented like the
+// insertion point.
+//
+func insert(src []byte, point, content string) ([]byte, error) {
+	i := bytes.Index(src, []byte("@@gqlc_insertion_point("+point+")"))
+	if i < 0 {
+		return nil, fmt.Errorf("insertion point not found: %s", point)
+	}
+
+	lineStart := bytes.LastIndexByte(src[:i], '\n') + 1
+	line := src[lineStart:i]
+	indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+
+	var b bytes.Buffer
+	b.Grow(len(src) + len(content))
+	b.Write(src[:lineStart])
+	for _, l := range strings.SplitAfter(content, "\n") {
+		if l == "" {
+			continue
+		}
+
+		if l != "\n" {
+			b.Write(indent)
+		}
+		b.WriteString(l)
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	b.Write(src[lineStart:])
+	return b.Bytes(), nil
+}
+
+// run executes the plugin command and kills it,
+// if it does not exit before the timeout or ctx is done.
+//
+func (g *Generator) run(ctx context.Context) error {
+	if g.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.Timeout)
+		defer cancel()
+	}
+
+	err := g.Start()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- g.Wait() }()
+
+	select {
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		g.Process.Kill()
+		<-done
+
+		if ctx.Err() == context.DeadlineExceeded && g.Timeout > 0 {
+			return fmt.Errorf("plugin timed out after %s", g.Timeout)
+		}
+		return ctx.Err()
+	}
+}
+
+// logWriter logs every line written by a plugin to stderr.
+// All output is kept for reporting if the plugin fails.
+//
+type logWriter struct {
+	log  *zap.Logger
+	line []byte
+	out  bytes.Buffer
+}
+
+func (w *logWriter) Write(b []byte) (int, error) {
+	w.out.Write(b)
+
+	w.line = append(w.line, b...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.log.Warn(string(w.line[:i]))
+		w.line = w.line[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush logs any remaining partial line.
+func (w *logWriter) Flush() {
+	if len(w.line) > 0 {
+		w.log.Warn(string(w.line))
+		w.line = nil
+	}
+}
+
+// formatDiagnostic formats a plugin diagnostic like the
+// compilers own diagnostics, resolving its schema position
+// with the given token.DocSet.
+//
+func formatDiagnostic(dset *token.DocSet, docName string, d *pb.Response_Diagnostic) string {
+	pos := d.Document
+	if pos == "" {
+		pos = docName
+	}
+	if dset != nil && d.Pos > 0 {
+		if p := dset.Position(token.Pos(d.Pos)); p.IsValid() {
+			pos = p.String()
+		}
+	}
+
+	return fmt.Sprintf("%s: %s: %s", pos, strings.ToLower(d.Severity.String()), d.Message)
+}
+
+// dirContext is implemented by GeneratorContexts which
+// know the directory they write to.
+//
+type dirContext interface {
+	Dir() string
+}
+
+// outputDir returns the output directory of the generator context, if known.
+func outputDir(ctx context.Context) string {
+	gCtx, ok := gen.Context(ctx).(dirContext)
+	if !ok {
+		return ""
+	}
+	return gCtx.Dir()
+}
+
+// parseVersion converts a gqlc version string, e.g. "v0.7.0-rc1", into
+// a pb.Version. Strings which are not semantic versions, such as local
+// builds, are reported entirely in the suffix.
+//
+func parseVersion(s string) *pb.Version {
+	v := &pb.Version{}
+
+	vs := strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(vs, '-'); i > -1 {
+		vs, v.Suffix = vs[:i], vs[i+1:]
+	}
+
+	parts := strings.Split(vs, ".")
+	if len(parts) != 3 {
+		return &pb.Version{Suffix: s}
+	}
+
+	nums := make([]int32, len(parts))
+	for i, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			return &pb.Version{Suffix: s}
+		}
+		nums[i] = int32(n)
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v
+}
@@ -6,14 +6,17 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"os"
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/gqlc/gqlc/gen"
 	"github.com/gqlc/gqlc/plugin/pb"
+	"github.com/gqlc/gqlc/types"
 	"github.com/gqlc/graphql/ast"
 	"github.com/gqlc/graphql/parser"
 	"github.com/gqlc/graphql/token"
@@ -33,12 +36,14 @@ const (
 )
 
 var (
-	testDoc *ast.Document
+	testDset *token.DocSet
+	testDoc  *ast.Document
 )
 
 func TestMain(m *testing.M) {
 	var err error
-	testDoc, err = parser.ParseDoc(token.NewDocSet(), "test", strings.NewReader(testGql), 0)
+	testDset = token.NewDocSet()
+	testDoc, err = parser.ParseDoc(testDset, "test", strings.NewReader(testGql), 0)
 	if err != nil {
 		panic(err)
 	}
@@ -146,6 +151,357 @@ func TestResponseError(t *testing.T) {
 	}
 }
 
+func TestPluginFailure(t *testing.T) {
+	// Get helper cmd
+	cmd := helperCommand(t, "fail")
+
+	// Create generate and run generate
+	var b bytes.Buffer
+	g := &Generator{
+		Name: "test",
+		Cmd:  cmd,
+	}
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, nil)
+	if err == nil {
+		t.Error("expected error")
+		return
+	}
+
+	cerr, ok := err.(gen.GeneratorError)
+	if !ok {
+		t.Fatal("unexpected err type")
+		return
+	}
+
+	if cerr.Msg != "exit status 1\nsomething went wrong\nreally wrong" {
+		t.Errorf("unexpected error message: %s", cerr.Msg)
+	}
+}
+
+func TestPluginTimeout(t *testing.T) {
+	// Get helper cmd
+	cmd := helperCommand(t, "hang")
+
+	// Create generate and run generate
+	var b bytes.Buffer
+	g := &Generator{
+		Name:    "test",
+		Cmd:     cmd,
+		Timeout: 100 * time.Millisecond,
+	}
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, nil)
+	if err == nil {
+		t.Error("expected error")
+		return
+	}
+
+	cerr, ok := err.(gen.GeneratorError)
+	if !ok {
+		t.Fatal("unexpected err type")
+		return
+	}
+
+	if cerr.Msg != "plugin timed out after 100ms" {
+		t.Errorf("unexpected error message: %s", cerr.Msg)
+	}
+}
+
+func TestResponseDiagnostics(t *testing.T) {
+	// Get helper cmd
+	cmd := helperCommand(t, "diagnostics")
+
+	// Capture warnings
+	var logs bytes.Buffer
+	log.SetFlags(0)
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	defer log.SetFlags(log.LstdFlags)
+
+	// Create generate and run generate
+	var b bytes.Buffer
+	g := &Generator{
+		Name: "test",
+		Cmd:  cmd,
+	}
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	ctx = gen.WithDocSet(ctx, testDset)
+	err := g.Generate(ctx, testDoc, nil)
+	if err == nil {
+		t.Error("expected error")
+		return
+	}
+
+	cerr, ok := err.(gen.GeneratorError)
+	if !ok {
+		t.Fatal("unexpected err type")
+		return
+	}
+
+	if cerr.Msg != "test:1:8: error: Test is not supported" {
+		t.Errorf("unexpected error message: %s", cerr.Msg)
+	}
+
+	if logs.String() != "test: warning: consider adding a description\n" {
+		t.Errorf("unexpected warnings: %s", logs.String())
+	}
+
+	if b.Len() > 0 {
+		t.Error("expected no files to be written")
+	}
+}
+
+func TestUnknownFeatures(t *testing.T) {
+	// Get helper cmd
+	cmd := helperCommand(t, "features")
+
+	// Capture warnings
+	var logs bytes.Buffer
+	log.SetFlags(0)
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	defer log.SetFlags(log.LstdFlags)
+
+	// Create generate and run generate
+	var b bytes.Buffer
+	g := &Generator{
+		Name:    "test",
+		Version: "v1.2.3",
+		Cmd:     cmd,
+	}
+	ctx := gen.WithContext(context.Background(), gen.TestCtx{Writer: &b})
+	err := g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if logs.String() != "test: warning: plugin supports features unknown to gqlc v1.2.3: 0x6\n" {
+		t.Errorf("unexpected warnings: %s", logs.String())
+	}
+}
+
+func TestInsert(t *testing.T) {
+	testCases := []struct {
+		Name    string
+		Src     string
+		Point   string
+		Content string
+		Ex      string
+		Err     string
+	}{
+		{
+			Name:    "TopLevel",
+			Src:     "a\n// @@gqlc_insertion_point(imports)\nb\n",
+			Point:   "imports",
+			Content: "c\nd\n",
+			Ex:      "a\nc\nd\n// @@gqlc_insertion_point(imports)\nb\n",
+		},
+		{
+			Name:    "Indented",
+			Src:     "func() {\n\t// @@gqlc_insertion_point(body)\n}\n",
+			Point:   "body",
+			Content: "a()\n\nb()",
+			Ex:      "func() {\n\ta()\n\n\tb()\n\t// @@gqlc_insertion_point(body)\n}\n",
+		},
+		{
+			Name:    "FirstLine",
+			Src:     "@@gqlc_insertion_point(top)",
+			Point:   "top",
+			Content: "a\n",
+			Ex:      "a\n@@gqlc_insertion_point(top)",
+		},
+		{
+			Name:  "Missing",
+			Src:   "@@gqlc_insertion_point(top)",
+			Point: "bottom",
+			Err:   "insertion point not found: bottom",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			out, err := insert([]byte(testCase.Src), testCase.Point, testCase.Content)
+			if err != nil {
+				if err.Error() != testCase.Err {
+					subT.Error(err)
+				}
+				return
+			}
+
+			if string(out) != testCase.Ex {
+				subT.Errorf("expected: %q, but got: %q", testCase.Ex, out)
+			}
+		})
+	}
+}
+
+type memFile struct {
+	bytes.Buffer
+
+	ctx  *memCtx
+	name string
+}
+
+func (f *memFile) Close() error {
+	f.ctx.files[f.name] = f.Bytes()
+	return nil
+}
+
+type memCtx struct {
+	files map[string][]byte
+	modes map[string]os.FileMode
+}
+
+func (ctx *memCtx) Open(name string) (io.WriteCloser, error) {
+	return &memFile{ctx: ctx, name: name}, nil
+}
+
+func (ctx *memCtx) OpenFile(name string, perm os.FileMode) (io.WriteCloser, error) {
+	ctx.modes[name] = perm
+	return ctx.Open(name)
+}
+
+func (ctx *memCtx) ReadFile(name string) ([]byte, error) {
+	b, ok := ctx.files[name]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return b, nil
+}
+
+func (ctx *memCtx) Remove(name string) error {
+	delete(ctx.files, name)
+	return nil
+}
+
+func TestWriteFile(t *testing.T) {
+	ctx := &memCtx{
+		files: map[string][]byte{
+			"stale.txt": []byte("stale"),
+			"model.go":  []byte("package model\n\n// @@gqlc_insertion_point(validate)\n"),
+		},
+		modes: make(map[string]os.FileMode),
+	}
+
+	files := []*pb.Response_File{
+		{Name: "stale.txt", Delete: true},
+		{Name: "run.sh", Mode: 0755, Content: "#!/bin/sh\n"},
+		{Name: "logo.png", BinaryContent: []byte{0x89, 0x50, 0x4e, 0x47}},
+		{Name: "model.go", InsertionPoint: "validate", Content: "func Validate() {}\n"},
+	}
+	for _, f := range files {
+		if err := writeFile(ctx, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, exists := ctx.files["stale.txt"]; exists {
+		t.Error("expected stale.txt to be deleted")
+	}
+	if ctx.modes["run.sh"] != 0755 {
+		t.Errorf("unexpected mode for run.sh: %s", ctx.modes["run.sh"])
+	}
+	if !bytes.Equal(ctx.files["logo.png"], []byte{0x89, 0x50, 0x4e, 0x47}) {
+		t.Errorf("unexpected content for logo.png: %v", ctx.files["logo.png"])
+	}
+
+	ex := "package model\n\nfunc Validate() {}\n// @@gqlc_insertion_point(validate)\n"
+	if string(ctx.files["model.go"]) != ex {
+		t.Errorf("expected: %q, but got: %q", ex, ctx.files["model.go"])
+	}
+
+	t.Run("Unsupported", func(subT *testing.T) {
+		var b bytes.Buffer
+		err := writeFile(gen.TestCtx{Writer: &b}, &pb.Response_File{Name: "stale.txt", Delete: true})
+		if err == nil {
+			subT.Error("expected error")
+		}
+	})
+
+	t.Run("BinaryInsertion", func(subT *testing.T) {
+		err := writeFile(ctx, &pb.Response_File{Name: "model.go", InsertionPoint: "validate", BinaryContent: []byte{0}})
+		if err == nil {
+			subT.Error("expected error")
+		}
+	})
+}
+
+type testDirCtx struct {
+	gen.TestCtx
+
+	dir string
+}
+
+func (ctx testDirCtx) Dir() string { return ctx.dir }
+
+func TestRequestMetadata(t *testing.T) {
+	// Get helper cmd
+	cmd := helperCommand(t, "metadata")
+
+	// Create generate and run generate
+	var b bytes.Buffer
+	g := &Generator{
+		Name:    "test",
+		Version: "v1.2.3-rc1",
+		Cmd:     cmd,
+	}
+	ctx := gen.WithContext(context.Background(), testDirCtx{TestCtx: gen.TestCtx{Writer: &b}, dir: "/out"})
+	err := g.Generate(ctx, testDoc, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ex := fmt.Sprintf("1.2.3 rc1 /out %v", types.Directives())
+	if b.String() != ex {
+		t.Errorf("expected: %s, but got: %s", ex, b.String())
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	testCases := []struct {
+		Name string
+		V    string
+		Ex   *pb.Version
+	}{
+		{
+			Name: "Release",
+			V:    "0.7.0",
+			Ex:   &pb.Version{Major: 0, Minor: 7, Patch: 0},
+		},
+		{
+			Name: "Prefixed",
+			V:    "v1.2.3",
+			Ex:   &pb.Version{Major: 1, Minor: 2, Patch: 3},
+		},
+		{
+			Name: "Suffix",
+			V:    "v1.2.3-rc1",
+			Ex:   &pb.Version{Major: 1, Minor: 2, Patch: 3, Suffix: "rc1"},
+		},
+		{
+			Name: "Dev",
+			V:    "D.E.V",
+			Ex:   &pb.Version{Suffix: "D.E.V"},
+		},
+		{
+			Name: "Empty",
+			V:    "",
+			Ex:   &pb.Version{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			v := parseVersion(testCase.V)
+			if !proto.Equal(v, testCase.Ex) {
+				subT.Errorf("expected: %v, but got: %v", testCase.Ex, v)
+			}
+		})
+	}
+}
+
 type testCtx struct {
 	opener func(filename string) (io.WriteCloser, error)
 	w      io.WriteCloser
@@ -268,6 +624,106 @@ func TestHelperProcess(t *testing.T) {
 			os.Exit(0)
 		}
 
+		_, err = os.Stdout.Write(b)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	case "metadata":
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		var req pb.Request
+		err = proto.Unmarshal(b, &req)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		v := req.CompilerVersion
+		resp := &pb.Response{
+			File: []*pb.Response_File{
+				{
+					Name:    "test.txt",
+					Content: fmt.Sprintf("%d.%d.%d %s %s %v", v.Major, v.Minor, v.Patch, v.Suffix, req.OutputDir, req.GqlcDirectives),
+				},
+			},
+		}
+		b, err = proto.Marshal(resp)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		_, err = os.Stdout.Write(b)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	case "features":
+		b, err := proto.Marshal(&pb.Response{SupportedFeatures: 6})
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		_, err = os.Stdout.Write(b)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+	case "fail":
+		fmt.Fprintln(os.Stderr, "something went wrong")
+		fmt.Fprint(os.Stderr, "really wrong")
+		os.Exit(1)
+	case "hang":
+		time.Sleep(10 * time.Second)
+	case "diagnostics":
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		var req pb.Request
+		err = proto.Unmarshal(b, &req)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
+		doc := req.Documents[0]
+		name := doc.Types[0].Spec.(*ast.TypeDecl_TypeSpec).TypeSpec.Name
+		resp := &pb.Response{
+			Diagnostic: []*pb.Response_Diagnostic{
+				{
+					Severity: pb.Response_Diagnostic_WARNING,
+					Message:  "consider adding a description",
+					Document: doc.Name,
+				},
+				{
+					Severity: pb.Response_Diagnostic_ERROR,
+					Message:  "Test is not supported",
+					Document: doc.Name,
+					Pos:      name.NamePos,
+				},
+			},
+			File: []*pb.Response_File{
+				{
+					Name:    "test.txt",
+					Content: outDoc,
+				},
+			},
+		}
+		b, err = proto.Marshal(resp)
+		if err != nil {
+			fmt.Fprintln(os.Stdout, err)
+			os.Exit(0)
+		}
+
 		_, err = os.Stdout.Write(b)
 		if err != nil {
 			fmt.Fprintln(os.Stdout, err)
@@ -0,0 +1,208 @@
+package plugintest
+
+import (
+	"strings"
+
+	"github.com/gqlc/compiler"
+	"github.com/gqlc/gqlc/plugin/pb"
+	"github.com/gqlc/gqlc/types"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+// Case is a canonical request sent to a plugin.
+type Case struct {
+	// Name identifies the case. It is also the name of the
+	// directory the golden files of the case are kept in.
+	//
+	Name string
+
+	// Request is the request sent to the plugin.
+	Request *pb.Request
+
+	// WantError reports whether the plugin is expected
+	// to report an error through the response.
+	//
+	WantError bool
+}
+
+const typesGql = `"Types contains every kind of type declaration."
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+"Time is a RFC3339 timestamp."
+scalar Time
+
+"""
+Node is an object with a globally unique id.
+
+Block descriptions may span multiple lines.
+"""
+interface Node {
+	"id uniquely identifies the node."
+	id: ID!
+}
+
+"Query represents valid queries."
+type Query {
+	"node returns a node by its id."
+	node(id: ID!): Node
+
+	"search performs a search over all nodes."
+	search(text: String = "*", first: Int = 10, kinds: [Kind!] = [USER]): [Result!]!
+}
+
+"Mutation represents valid mutations."
+type Mutation {
+	"createUser creates a new user."
+	createUser(input: UserInput!): User
+}
+
+"User is a registered user."
+type User implements Node {
+	id: ID!
+	name: String!
+	friends: [User]
+	createdAt: Time
+	score: Float
+	active: Boolean
+}
+
+"Post is a post written by a user."
+type Post implements Node {
+	id: ID!
+	author: User!
+	title: String
+}
+
+"Result is the result of a search."
+union Result = User | Post
+
+"Kind is the kind of a node."
+enum Kind {
+	"USER is a User."
+	USER
+	POST @deprecated(reason: "posts are no longer searchable")
+}
+
+"UserInput contains the fields to create a User with."
+input UserInput {
+	name: String!
+	friends: [ID!] = []
+}
+
+"cached marks a field as cacheable."
+directive @cached(ttl: Int = 60) on FIELD_DEFINITION | OBJECT
+`
+
+const importsGql = `@import(paths: ["types"])
+
+"Page is a page of nodes."
+type Page {
+	nodes: [Node]
+	kind: Kind
+}
+`
+
+const extensionsGql = `type Query {
+	version: String
+}
+
+extend type Query {
+	"uptime is how long the service has been running."
+	uptime: Int
+}
+
+scalar Void
+
+extend scalar Void @cached
+
+enum Color {
+	RED
+}
+
+extend enum Color {
+	GREEN
+	BLUE
+}
+`
+
+const unicodeGql = `"Ünïcödé contains non-ASCII names and descriptions: 日本語, emoji 🚀, and escapes é."
+type Unicode {
+	"名前 is a name."
+	name: String
+
+	"""
+	Ελληνικά text in a block description.
+	"""
+	greek: String
+}
+`
+
+func parse(dset *token.DocSet, name, src string) *ast.Document {
+	doc, err := parser.ParseDoc(dset, name, strings.NewReader(src), parser.ParseComments)
+	if err != nil {
+		panic("plugintest: invalid canonical document: " + err.Error())
+	}
+	return doc
+}
+
+// compile prepares documents the same way gqlc does before
+// handing them to generators, i.e. imports are reduced and
+// type extensions are merged.
+//
+func compile(docs ...*ast.Document) []*ast.Document {
+	ir, err := compiler.ReduceImports(compiler.ToIR(docs))
+	if err != nil {
+		panic("plugintest: invalid canonical document: " + err.Error())
+	}
+
+	for d, types := range ir {
+		ir[d] = compiler.MergeExtensions(types)
+	}
+	return compiler.FromIR(ir)
+}
+
+func newRequest(param string, toGenerate string, docs ...*ast.Document) *pb.Request {
+	return &pb.Request{
+		FileToGenerate:  []string{toGenerate},
+		Parameter:       param,
+		Documents:       docs,
+		CompilerVersion: &pb.Version{Suffix: "plugintest"},
+		GqlcDirectives:  types.Directives(),
+	}
+}
+
+// Cases returns the canonical requests. param is
+// the JSON encoded options given to each request.
+//
+func Cases(param string) []Case {
+	dset := token.NewDocSet()
+
+	return []Case{
+		{
+			Name:    "Types",
+			Request: newRequest(param, "types", compile(parse(dset, "types", typesGql))...),
+		},
+		{
+			Name:    "Imports",
+			Request: newRequest(param, "imports", compile(parse(dset, "imports", importsGql), parse(dset, "types", typesGql))...),
+		},
+		{
+			Name:    "Extensions",
+			Request: newRequest(param, "extensions", compile(parse(dset, "extensions", extensionsGql))...),
+		},
+		{
+			Name:    "Unicode",
+			Request: newRequest(param, "unicode", compile(parse(dset, "unicode", unicodeGql))...),
+		},
+		{
+			Name:      "MalformedOptions",
+			Request:   newRequest("{", "types", compile(parse(dset, "types", typesGql))...),
+			WantError: true,
+		},
+	}
+}
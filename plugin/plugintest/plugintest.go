@@ -0,0 +1,288 @@
+// Package plugintest provides a conformance test kit for gqlc plugins.
+//
+// Plugin authors point the kit at their plugin executable from a regular
+// Go test:
+//
+//	var update = flag.Bool("update", false, "update golden files")
+//
+//	func TestConformance(t *testing.T) {
+//		plugintest.Run(t, plugintest.Config{
+//			Path:   "./bin/gqlc-gen-example",
+//			Golden: "testdata",
+//			Update: *update,
+//		})
+//	}
+//
+// The kit sends a set of canonical requests to the plugin and checks that
+// it exits cleanly, responds with a well-formed response, only names files
+// with safe relative paths and reports errors through Response.Error.
+//
+package plugintest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gqlc/gqlc/plugin/pb"
+)
+
+// Config configures how the plugin is tested.
+type Config struct {
+	// Path is the path of the plugin executable.
+	Path string
+
+	// Args are any additional arguments to run the plugin with.
+	Args []string
+
+	// Env is the environment of the plugin. If nil,
+	// the environment of the test is used.
+	//
+	Env []string
+
+	// Options are the generator options sent with every request.
+	Options map[string]interface{}
+
+	// Golden is the directory golden files are kept in. If empty,
+	// the generated files are not compared to golden files.
+	//
+	Golden string
+
+	// Update rewrites the golden files with the generated files.
+	Update bool
+
+	// Timeout is the maximum amount of time the plugin
+	// may run for each request. (default: 1 minute)
+	//
+	Timeout time.Duration
+}
+
+// Run sends every canonical request to the plugin and checks its responses.
+func Run(t *testing.T, cfg Config) {
+	t.Helper()
+
+	param, err := json.Marshal(cfg.Options)
+	if err != nil {
+		t.Fatalf("plugintest: encoding options: %s", err)
+	}
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = time.Minute
+	}
+
+	for _, c := range Cases(string(param)) {
+		c := c
+		t.Run(c.Name, func(subT *testing.T) {
+			resp, err := Exec(cfg, c.Request)
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			for _, msg := range Check(resp) {
+				subT.Error(msg)
+			}
+
+			switch {
+			case c.WantError && resp.Error == "":
+				subT.Error("expected error to be reported through Response.Error")
+			case !c.WantError && resp.Error != "":
+				subT.Errorf("unexpected error: %s", resp.Error)
+			}
+
+			if c.WantError || cfg.Golden == "" {
+				return
+			}
+			compareGolden(subT, filepath.Join(cfg.Golden, c.Name), resp, cfg.Update)
+		})
+	}
+}
+
+// Exec executes the plugin with the given request. It returns an error,
+// if the plugin does not exit with status zero or its response can not
+// be unmarshalled.
+//
+func Exec(cfg Config, req *pb.Request) (*pb.Response, error) {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cfg.Path, cfg.Args...)
+	cmd.Env = cfg.Env
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("plugin timed out after %s", cfg.Timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin must exit with status zero and report errors through Response.Error: %w\n%s", err, stderr.Bytes())
+	}
+
+	var resp pb.Response
+	err = proto.Unmarshal(stdout.Bytes(), &resp)
+	if err != nil {
+		return nil, fmt.Errorf("malformed response: %w", err)
+	}
+	return &resp, nil
+}
+
+// Check checks that a response is well-formed. It returns
+// a message for every problem found.
+//
+func Check(resp *pb.Response) (problems []string) {
+	written := make(map[string]bool, len(resp.File))
+	for i, f := range resp.File {
+		if err := CheckName(f.Name); err != nil {
+			problems = append(problems, fmt.Sprintf("file %d: %s", i, err))
+			continue
+		}
+
+		switch {
+		case f.Delete:
+			if f.InsertionPoint != "" || f.Content != "" || len(f.BinaryContent) > 0 {
+				problems = append(problems, fmt.Sprintf("file %d: %s: deleted files must not have content", i, f.Name))
+			}
+			delete(written, f.Name)
+		case f.InsertionPoint != "":
+			if len(f.BinaryContent) > 0 {
+				problems = append(problems, fmt.Sprintf("file %d: %s: binary content can not be inserted", i, f.Name))
+			}
+		default:
+			if written[f.Name] {
+				problems = append(problems, fmt.Sprintf("file %d: %s: generated more than once", i, f.Name))
+			}
+			written[f.Name] = true
+		}
+
+		if f.Mode&^uint32(os.ModePerm) != 0 {
+			problems = append(problems, fmt.Sprintf("file %d: %s: mode must only contain permission bits: %o", i, f.Name, f.Mode))
+		}
+	}
+
+	for i, d := range resp.Diagnostic {
+		if d.Message == "" {
+			problems = append(problems, fmt.Sprintf("diagnostic %d: missing message", i))
+		}
+		if d.Pos < 0 {
+			problems = append(problems, fmt.Sprintf("diagnostic %d: invalid position: %d", i, d.Pos))
+		}
+	}
+	return
+}
+
+// CheckName checks that a file name is a safe relative path,
+// which can not lie outside of the output directory.
+//
+func CheckName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("file name must not be empty")
+	case strings.ContainsRune(name, '\\'):
+		return fmt.Errorf("%s: file name must use \"/\" as the path separator", name)
+	case path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "":
+		return fmt.Errorf("%s: file name must be relative", name)
+	}
+
+	for _, elem := range strings.Split(name, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return fmt.Errorf("%s: file name must not contain empty, \".\" or \"..\" components", name)
+		}
+	}
+	return nil
+}
+
+func compareGolden(t *testing.T, dir string, resp *pb.Response, update bool) {
+	t.Helper()
+
+	files := make(map[string][]byte)
+	for _, f := range resp.File {
+		switch {
+		case f.Delete:
+			delete(files, f.Name)
+		case f.InsertionPoint != "":
+			// Insertion points are applied by gqlc, so they're
+			// golden tested as their own files.
+			files[f.Name+"@"+f.InsertionPoint] = []byte(f.Content)
+		case len(f.BinaryContent) > 0:
+			files[f.Name] = f.BinaryContent
+		default:
+			files[f.Name] = []byte(f.Content)
+		}
+	}
+
+	if update {
+		err := os.RemoveAll(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for name, content := range files {
+			fname := filepath.Join(dir, filepath.FromSlash(name))
+			err = os.MkdirAll(filepath.Dir(fname), 0755)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = ioutil.WriteFile(fname, content, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	golden := make(map[string]bool)
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, _ := filepath.Rel(dir, p)
+		golden[filepath.ToSlash(rel)] = true
+		return nil
+	})
+
+	for name, content := range files {
+		if !golden[name] {
+			t.Errorf("%s: missing golden file", name)
+			continue
+		}
+
+		ex, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if !bytes.Equal(ex, content) {
+			t.Errorf("%s: does not match golden file\nexpected:\n%s\ngot:\n%s", name, ex, content)
+		}
+	}
+
+	for name := range golden {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s: golden file was not generated", name)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package plugintest
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gqlc/gqlc/doc"
+	"github.com/gqlc/gqlc/plugin/pb"
+	"github.com/gqlc/gqlc/plugin/sdk"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func helperConfig(cmd string) Config {
+	return Config{
+		Path: os.Args[0],
+		Args: []string{"-test.run=TestHelperProcess", "--", cmd},
+		Env:  []string{"GO_WANT_HELPER_PROCESS=1"},
+	}
+}
+
+func TestRun(t *testing.T) {
+	cfg := helperConfig("doc")
+	cfg.Golden = "testdata"
+	cfg.Update = *update
+
+	Run(t, cfg)
+}
+
+func TestExec_ExitStatus(t *testing.T) {
+	_, err := Exec(helperConfig("exit"), Cases("")[0].Request)
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Resp *pb.Response
+		Len  int
+	}{
+		{
+			Name: "Valid",
+			Resp: &pb.Response{
+				File: []*pb.Response_File{
+					{Name: "a/b.go", Content: "package a"},
+					{Name: "a/b.go", InsertionPoint: "imports", Content: "import \"fmt\""},
+					{Name: "stale.go", Delete: true},
+					{Name: "run.sh", Mode: 0755},
+				},
+			},
+		},
+		{
+			Name: "UnsafeNames",
+			Resp: &pb.Response{
+				File: []*pb.Response_File{
+					{Name: ""},
+					{Name: "/etc/passwd"},
+					{Name: "../../x"},
+					{Name: "a/./b"},
+					{Name: "a//b"},
+					{Name: `a\b`},
+				},
+			},
+			Len: 6,
+		},
+		{
+			Name: "Duplicates",
+			Resp: &pb.Response{
+				File: []*pb.Response_File{
+					{Name: "a.go"},
+					{Name: "a.go"},
+				},
+			},
+			Len: 1,
+		},
+		{
+			Name: "InvalidFiles",
+			Resp: &pb.Response{
+				File: []*pb.Response_File{
+					{Name: "a.go", InsertionPoint: "x", BinaryContent: []byte{0}},
+					{Name: "b.go", Delete: true, Content: "b"},
+					{Name: "c.go", Mode: uint32(os.ModeSetuid | 0755)},
+				},
+			},
+			Len: 3,
+		},
+		{
+			Name: "InvalidDiagnostics",
+			Resp: &pb.Response{
+				Diagnostic: []*pb.Response_Diagnostic{
+					{Pos: -1, Message: "bad pos"},
+					{},
+				},
+			},
+			Len: 2,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			problems := Check(testCase.Resp)
+			if len(problems) != testCase.Len {
+				subT.Errorf("expected %d problems, but got: %s", testCase.Len, strings.Join(problems, "\n"))
+			}
+		})
+	}
+}
+
+// TestHelperProcess isn't a real test. It's used as a helper process
+// for running plugins.
+//
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	args := os.Args
+	for len(args) > 0 {
+		if args[0] == "--" {
+			args = args[1:]
+			break
+		}
+		args = args[1:]
+	}
+
+	switch args[0] {
+	case "doc":
+		sdk.Main(sdk.Generator(&doc.Generator{}))
+	case "exit":
+		os.Exit(1)
+	}
+}
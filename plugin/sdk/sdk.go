@@ -0,0 +1,326 @@
+// Package sdk provides helpers for writing gqlc plugins in Go.
+//
+// A plugin is an executable, which reads an encoded pb.Request from stdin
+// and writes an encoded pb.Response to stdout. Main handles all of this, so
+// a plugin only needs to implement a Func:
+//
+//	func main() {
+//		sdk.Main(func(req *sdk.Request) (*sdk.Response, error) {
+//			...
+//		})
+//	}
+//
+// Existing gen.Generators can be turned into plugins with Generator:
+//
+//	func main() {
+//		sdk.Main(sdk.Generator(&golang.Generator{}))
+//	}
+//
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/plugin/pb"
+	"github.com/gqlc/graphql/ast"
+)
+
+// Request is a plugin request with its generator options decoded.
+type Request struct {
+	*pb.Request
+
+	// Options are the generator options given to gqlc. They have the
+	// same types as the options passed to a gen.Generator, i.e. int64,
+	// float64, bool, string or slices of them.
+	//
+	Options map[string]interface{}
+}
+
+// DecodeOptions decodes the generator options into v.
+func (r *Request) DecodeOptions(v interface{}) error {
+	if r.Parameter == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(r.Parameter), v)
+}
+
+// Response is the response written back to gqlc.
+type Response = pb.Response
+
+// Func handles a single plugin request. Any error it returns
+// is reported back to gqlc through the response.
+//
+type Func func(*Request) (*Response, error)
+
+// Main runs fn as a plugin, reading the request from stdin and writing the
+// response to stdout. Errors returned by fn are reported through the response,
+// so Main only exits with a non-zero status if the request can not be read
+// or the response can not be written.
+//
+func Main(fn Func) {
+	err := Run(os.Stdin, os.Stdout, fn)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Run reads an encoded request from r, handles it with fn
+// and writes the encoded response to w.
+//
+func Run(r io.Reader, w io.Writer, fn Func) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("sdk: reading request: %w", err)
+	}
+
+	var req pb.Request
+	err = proto.Unmarshal(b, &req)
+	if err != nil {
+		return fmt.Errorf("sdk: unmarshalling request: %w", err)
+	}
+
+	b, err = proto.Marshal(handle(&req, fn))
+	if err != nil {
+		return fmt.Errorf("sdk: marshalling response: %w", err)
+	}
+
+	_, err = w.Write(b)
+	if err != nil {
+		return fmt.Errorf("sdk: writing response: %w", err)
+	}
+	return nil
+}
+
+// Call is a test harness for plugins. It handles req with fn exactly
+// like Run does, including encoding the request and response, but
+// without any IO.
+//
+func Call(fn Func, req *pb.Request) (*Response, error) {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	err = Run(bytes.NewReader(b), &out, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp pb.Response
+	err = proto.Unmarshal(out.Bytes(), &resp)
+	return &resp, err
+}
+
+// NewRequest creates a request like the one gqlc sends to plugins,
+// with the given options encoded as the parameter.
+//
+func NewRequest(opts map[string]interface{}, docs ...*ast.Document) (*pb.Request, error) {
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.Request{
+		Parameter: string(b),
+		Documents: docs,
+	}
+	for _, doc := range docs {
+		req.FileToGenerate = append(req.FileToGenerate, doc.Name)
+	}
+	return req, nil
+}
+
+func handle(req *pb.Request, fn Func) (resp *Response) {
+	defer func() {
+		if r := recover(); r != nil {
+			resp = &Response{Error: fmt.Sprintf("plugin panicked: %v", r)}
+		}
+	}()
+
+	opts, err := decodeOptions(req.Parameter)
+	if err != nil {
+		return &Response{Error: fmt.Sprintf("decoding options: %s", err)}
+	}
+
+	resp, err = fn(&Request{Request: req, Options: opts})
+	if err != nil {
+		return &Response{Error: err.Error()}
+	}
+	if resp == nil {
+		resp = new(Response)
+	}
+	return
+}
+
+// decodeOptions decodes the JSON encoded options into the
+// same types the gqlc command line parses them into.
+//
+func decodeOptions(param string) (map[string]interface{}, error) {
+	opts := make(map[string]interface{})
+	if param == "" || param == "null" {
+		return opts, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(param)))
+	dec.UseNumber()
+	err := dec.Decode(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range opts {
+		opts[k] = convertOption(v)
+	}
+	return opts, nil
+}
+
+func convertOption(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(string(x), 10, 64); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case []interface{}:
+		return convertSlice(x)
+	}
+	return v
+}
+
+func convertSlice(vals []interface{}) interface{} {
+	if len(vals) == 0 {
+		return vals
+	}
+
+	conv := make([]interface{}, len(vals))
+	for i, v := range vals {
+		conv[i] = convertOption(v)
+	}
+
+	switch conv[0].(type) {
+	case int64:
+		out := make([]int64, len(conv))
+		for i, v := range conv {
+			x, ok := v.(int64)
+			if !ok {
+				return conv
+			}
+			out[i] = x
+		}
+		return out
+	case float64:
+		out := make([]float64, len(conv))
+		for i, v := range conv {
+			switch x := v.(type) {
+			case float64:
+				out[i] = x
+			case int64:
+				out[i] = float64(x)
+			default:
+				return conv
+			}
+		}
+		return out
+	case bool:
+		out := make([]bool, len(conv))
+		for i, v := range conv {
+			x, ok := v.(bool)
+			if !ok {
+				return conv
+			}
+			out[i] = x
+		}
+		return out
+	case string:
+		out := make([]string, len(conv))
+		for i, v := range conv {
+			x, ok := v.(string)
+			if !ok {
+				return conv
+			}
+			out[i] = x
+		}
+		return out
+	}
+	return conv
+}
+
+// Generator returns a Func, which runs g as a plugin. g is called
+// once for every document to generate and every file it opens in
+// its GeneratorContext is returned in the response. If g is a
+// gen.DocsGenerator, it is called once with all the documents to generate.
+//
+func Generator(g gen.Generator) Func {
+	return func(req *Request) (*Response, error) {
+		gCtx := new(memCtx)
+		ctx := gen.WithContext(context.Background(), gCtx)
+
+		docs := make([]*ast.Document, 0, len(req.FileToGenerate))
+		for _, doc := range req.Documents {
+			if contains(req.FileToGenerate, doc.Name) {
+				docs = append(docs, doc)
+			}
+		}
+
+		err := gen.Run(ctx, "", g, docs, req.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := new(Response)
+		for _, f := range gCtx.files {
+			resp.File = append(resp.File, &pb.Response_File{
+				Name:    f.name,
+				Content: f.String(),
+			})
+		}
+		return resp, nil
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// memCtx is a GeneratorContext which keeps all files in memory.
+type memCtx struct {
+	files []*memFile
+}
+
+func (ctx *memCtx) Open(name string) (io.WriteCloser, error) {
+	for _, f := range ctx.files {
+		if f.name == name {
+			f.Reset()
+			return f, nil
+		}
+	}
+
+	f := &memFile{name: name}
+	ctx.files = append(ctx.files, f)
+	return f, nil
+}
+
+type memFile struct {
+	bytes.Buffer
+
+	name string
+}
+
+func (*memFile) Close() error { return nil }
@@ -0,0 +1,239 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gqlc/gqlc/gen"
+	"github.com/gqlc/gqlc/plugin/pb"
+	"github.com/gqlc/graphql/ast"
+	"github.com/gqlc/graphql/parser"
+	"github.com/gqlc/graphql/token"
+)
+
+func parseDoc(t *testing.T, name, src string) *ast.Document {
+	t.Helper()
+
+	doc, err := parser.ParseDoc(token.NewDocSet(), name, strings.NewReader(src), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestRun(t *testing.T) {
+	req, err := NewRequest(map[string]interface{}{"package": "test"}, parseDoc(t, "test", `scalar Test`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = Run(bytes.NewReader(b), &out, func(req *Request) (*Response, error) {
+		var opts struct {
+			Package string `json:"package"`
+		}
+		err := req.DecodeOptions(&opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Response{
+			File: []*pb.Response_File{
+				{
+					Name:    req.FileToGenerate[0] + ".txt",
+					Content: opts.Package,
+				},
+			},
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp pb.Response
+	err = proto.Unmarshal(out.Bytes(), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.File) != 1 || resp.File[0].Name != "test.txt" || resp.File[0].Content != "test" {
+		t.Errorf("unexpected response: %v", resp)
+	}
+}
+
+func TestRun_MalformedRequest(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(strings.NewReader("\x01"), &out, func(*Request) (*Response, error) { return nil, nil })
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestCall_Errors(t *testing.T) {
+	testCases := []struct {
+		Name string
+		Fn   Func
+		Err  string
+	}{
+		{
+			Name: "Error",
+			Fn:   func(*Request) (*Response, error) { return nil, errors.New("test error") },
+			Err:  "test error",
+		},
+		{
+			Name: "Panic",
+			Fn:   func(*Request) (*Response, error) { panic("oops") },
+			Err:  "plugin panicked: oops",
+		},
+		{
+			Name: "NilResponse",
+			Fn:   func(*Request) (*Response, error) { return nil, nil },
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(subT *testing.T) {
+			resp, err := Call(testCase.Fn, &pb.Request{})
+			if err != nil {
+				subT.Fatal(err)
+			}
+
+			if resp.Error != testCase.Err {
+				subT.Errorf("expected error: %s, but got: %s", testCase.Err, resp.Error)
+			}
+		})
+	}
+}
+
+func TestDecodeOptions(t *testing.T) {
+	opts, err := decodeOptions(`{"a":1,"b":1.5,"c":true,"d":"s","e":[1,2],"f":["x","y"],"g":[true,false],"h":[1.5,2],"i":[1,"x"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := map[string]interface{}{
+		"a": int64(1),
+		"b": 1.5,
+		"c": true,
+		"d": "s",
+		"e": []int64{1, 2},
+		"f": []string{"x", "y"},
+		"g": []bool{true, false},
+		"h": []float64{1.5, 2},
+		"i": []interface{}{int64(1), "x"},
+	}
+	if !reflect.DeepEqual(opts, ex) {
+		t.Errorf("expected: %#v, but got: %#v", ex, opts)
+	}
+
+	opts, err = decodeOptions("null")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts) != 0 {
+		t.Errorf("expected no options, but got: %v", opts)
+	}
+}
+
+type testGenerator struct{}
+
+func (testGenerator) Generate(ctx context.Context, doc *ast.Document, opts map[string]interface{}) error {
+	if doc.Name == "bad" {
+		return gen.GeneratorError{GenName: "test", DocName: doc.Name, Msg: "bad doc"}
+	}
+
+	w, err := gen.Context(ctx).Open(doc.Name + ".txt")
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	_, err = fmt.Fprintf(w, "%s %v", doc.Name, opts["n"])
+	return err
+}
+
+func TestGenerator(t *testing.T) {
+	one, two := parseDoc(t, "one", `scalar One`), parseDoc(t, "two", `scalar Two`)
+
+	req, err := NewRequest(map[string]interface{}{"n": 1}, one, two)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.FileToGenerate = []string{"two"}
+
+	resp, err := Call(Generator(testGenerator{}), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Error != "" {
+		t.Fatal(resp.Error)
+	}
+	if len(resp.File) != 1 || resp.File[0].Name != "two.txt" || resp.File[0].Content != "two 1" {
+		t.Errorf("unexpected files: %v", resp.File)
+	}
+
+	t.Run("Error", func(subT *testing.T) {
+		req, err := NewRequest(nil, parseDoc(subT, "bad", `scalar Bad`))
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		resp, err := Call(Generator(testGenerator{}), req)
+		if err != nil {
+			subT.Fatal(err)
+		}
+
+		if resp.Error != "compiler: generator error occurred in test:bad bad doc" {
+			subT.Errorf("unexpected error: %s", resp.Error)
+		}
+	})
+}
+
+type testDocsGenerator struct{ testGenerator }
+
+func (testDocsGenerator) GenerateDocs(ctx context.Context, docs []*ast.Document, opts map[string]interface{}) error {
+	w, err := gen.Context(ctx).Open("index.txt")
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	for _, doc := range docs {
+		fmt.Fprintln(w, doc.Name)
+	}
+	return nil
+}
+
+func TestGenerator_Docs(t *testing.T) {
+	one, two, thr := parseDoc(t, "one", `scalar One`), parseDoc(t, "two", `scalar Two`), parseDoc(t, "thr", `scalar Thr`)
+
+	req, err := NewRequest(nil, one, two, thr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.FileToGenerate = []string{"one", "thr"}
+
+	resp, err := Call(Generator(testDocsGenerator{}), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Error != "" {
+		t.Fatal(resp.Error)
+	}
+	if len(resp.File) != 1 || resp.File[0].Name != "index.txt" || resp.File[0].Content != "one\nthr\n" {
+		t.Errorf("unexpected files: %v", resp.File)
+	}
+}
@@ -34,3 +34,10 @@ func IsGqlcDirective(name string) bool {
 	}
 	return false
 }
+
+// Directives returns the names of all directives custom to gqlc.
+func Directives() []string {
+	names := make([]string, len(dirs))
+	copy(names, dirs)
+	return names
+}